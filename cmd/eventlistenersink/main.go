@@ -28,8 +28,11 @@ import (
 	dynamicClientset "github.com/tektoncd/triggers/pkg/client/dynamic/clientset"
 	"github.com/tektoncd/triggers/pkg/client/dynamic/clientset/tekton"
 	"github.com/tektoncd/triggers/pkg/client/informers/externalversions"
+	"github.com/tektoncd/triggers/pkg/interceptors"
 	triggerLogging "github.com/tektoncd/triggers/pkg/logging"
+	"github.com/tektoncd/triggers/pkg/metrics"
 	"github.com/tektoncd/triggers/pkg/sink"
+	"github.com/tektoncd/triggers/pkg/tracing"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
@@ -87,11 +90,14 @@ func main() {
 
 	factory := externalversions.NewSharedInformerFactoryWithOptions(sinkClients.TriggersClient,
 		30*time.Second, externalversions.WithNamespace(sinkArgs.ElNamespace))
-	go func(ctx context.Context) {
-		factory.Start(ctx.Done())
-		<-ctx.Done()
-	}(ctx)
-
+	informers := factory.Triggers().V1alpha1()
+	// The sink is ready once the informers of the resources it reads have synced
+	readiness := sink.NewReadiness(
+		informers.EventListeners().Informer().HasSynced,
+		informers.Triggers().Informer().HasSynced,
+		informers.TriggerBindings().Informer().HasSynced,
+		informers.TriggerTemplates().Informer().HasSynced,
+	)
 	// Create EventListener Sink
 	r := sink.Sink{
 		KubeClientSet:               kubeClient,
@@ -103,35 +109,151 @@ func main() {
 		EventListenerNamespace:      sinkArgs.ElNamespace,
 		Logger:                      logger,
 		Auth:                        sink.DefaultAuthOverride{},
-		EventListenerLister:         factory.Triggers().V1alpha1().EventListeners().Lister(),
-		TriggerLister:               factory.Triggers().V1alpha1().Triggers().Lister(),
-		TriggerBindingLister:        factory.Triggers().V1alpha1().TriggerBindings().Lister(),
-		ClusterTriggerBindingLister: factory.Triggers().V1alpha1().ClusterTriggerBindings().Lister(),
-		TriggerTemplateLister:       factory.Triggers().V1alpha1().TriggerTemplates().Lister(),
+		EventQueue:                  sink.NewEventQueue(sinkArgs.QueueSize, sinkArgs.QueueWorkers, sinkArgs.QueueRetries),
+		DeliveryStore:               sink.NewMemoryDeliveryStore(),
+		MaxBodySize:                 sinkArgs.MaxBodySize,
+		MaxBatchSize:                sinkArgs.MaxBatchSize,
+		CloudEvents:                 sink.NewCloudEventSender(),
+		RateLimiters:                sink.NewRateLimiters(),
+		EventListenerLister:         informers.EventListeners().Lister(),
+		TriggerLister:               informers.Triggers().Lister(),
+		TriggerBindingLister:        informers.TriggerBindings().Lister(),
+		ClusterTriggerBindingLister: informers.ClusterTriggerBindings().Lister(),
+		TriggerTemplateLister:       informers.TriggerTemplates().Lister(),
+		ClusterInterceptorLister:    sink.NewLazyClusterInterceptorLister(ctx, factory),
+		InterceptorClients:          interceptors.NewClients(http.DefaultClient),
+		InterceptorTokenFile:        sinkArgs.InterceptorTokenFile,
+		Secrets:                     interceptors.NewSecretCache(sinkArgs.SecretTTL),
+		TokenReviews:                interceptors.NewTokenReviewCache(kubeClient, sinkArgs.TokenReviewTTL),
+	}
+	// Start the informers once all the listers are registered. The
+	// ClusterInterceptors one is only started once a Trigger refers to one.
+	go func(ctx context.Context) {
+		factory.Start(ctx.Done())
+		<-ctx.Done()
+	}(ctx)
+
+	// Serve metrics on a separate port
+	if err := metrics.RegisterViews(); err != nil {
+		logger.Fatalf("failed to register metrics views: %v", err)
+	}
+	metricsHandler, err := metrics.NewHandler()
+	if err != nil {
+		logger.Fatalf("failed to create metrics exporter: %v", err)
+	}
+	metricsMux := http.NewServeMux()
+	metricsMux.Handle("/metrics", metricsHandler)
+	logger.Infof("Serving metrics on port %s", sinkArgs.MetricsPort)
+	go func() {
+		if err := http.ListenAndServe(fmt.Sprintf(":%s", sinkArgs.MetricsPort), metricsMux); err != nil {
+			logger.Fatalf("failed to start eventlistener metrics server: %v", err)
+		}
+	}()
+
+	// Serve the admin endpoint on a separate port, which the EventListener's
+	// Service does not expose. Events are only kept when it is served.
+	if sinkArgs.AdminPort != "" {
+		r.EventStore = sink.NewEventStore(sinkArgs.RecentEvents)
+		logger.Infof("Serving the admin endpoint on port %s", sinkArgs.AdminPort)
+		go func() {
+			if err := http.ListenAndServe(fmt.Sprintf(":%s", sinkArgs.AdminPort), r.AdminHandler(sinkArgs.AdminTokenFile)); err != nil {
+				logger.Fatalf("failed to start eventlistener admin server: %v", err)
+			}
+		}()
+	}
+
+	if sinkArgs.TracingEndpoint != "" {
+		flush, err := tracing.Setup(sinkArgs.TracingEndpoint, sinkArgs.TracingSampleRate)
+		if err != nil {
+			logger.Fatalf("failed to set up tracing: %v", err)
+		}
+		defer flush()
+		logger.Infof("Exporting traces to %s", sinkArgs.TracingEndpoint)
 	}
 
+	r.StartWorkers()
+	go r.PruneFailedEvents(ctx, sink.FailedEventPruneInterval)
+
 	// Listen and serve
 	logger.Infof("Listen and serve on port %s", sinkArgs.Port)
+	eventMux := http.NewServeMux()
+	// The handlers of events are waited for on shutdown, even after timing out
+	var drainer sink.Drainer
+	var eventHandler http.Handler = drainer.Track(http.HandlerFunc(r.HandleEvent))
+	if sinkArgs.TLSClientCAFile != "" {
+		// Probes are served without a client certificate
+		eventHandler = sink.RequireClientCertificate(eventHandler)
+	}
+	eventMux.Handle("/", eventHandler)
+	var batchHandler http.Handler = drainer.Track(http.HandlerFunc(r.HandleBatch))
+	if sinkArgs.TLSClientCAFile != "" {
+		batchHandler = sink.RequireClientCertificate(batchHandler)
+	}
+	eventMux.Handle(sink.BatchPath, batchHandler)
+	eventMux.Handle(sink.BatchPath+"/", batchHandler)
+
 	mux := http.NewServeMux()
-	mux.HandleFunc("/", r.HandleEvent)
+	// Only the responses to events are recorded, including those that timed
+	// out, and not those to the probes
+	mux.Handle("/", metrics.InstrumentHandler(sinkArgs.ElName, http.TimeoutHandler(eventMux,
+		sinkArgs.ELTimeOutHandler*time.Second, "EventListener Timeout!\n")))
 
 	// For handling Liveness Probe
-	// TODO(dibyom): Livness, metrics etc. should be on a separate port
+	// TODO(dibyom): Livness should be on a separate port
 	mux.HandleFunc("/live", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(200)
 		fmt.Fprint(w, "ok")
 	})
+	mux.Handle("/ready", readiness)
 
 	srv := &http.Server{
 		Addr:         fmt.Sprintf(":%s", sinkArgs.Port),
 		ReadTimeout:  sinkArgs.ELReadTimeOut * time.Second,
 		WriteTimeout: sinkArgs.ELWriteTimeOut * time.Second,
 		IdleTimeout:  sinkArgs.ELIdleTimeOut * time.Second,
-		Handler: http.TimeoutHandler(mux,
-			sinkArgs.ELTimeOutHandler*time.Second, "EventListener Timeout!\n"),
+		Handler:      mux,
+	}
+
+	if sinkArgs.TLSCertFile != "" {
+		srv.TLSConfig, err = sink.NewTLSConfig(ctx, sinkArgs.TLSCertFile, sinkArgs.TLSKeyFile, sinkArgs.TLSClientCAFile, logger)
+		if err != nil {
+			logger.Fatalf("failed to load the TLS certificates: %v", err)
+		}
 	}
 
-	if err := srv.ListenAndServe(); err != nil {
-		logger.Fatalf("failed to start eventlistener sink: %v", err)
+	go func() {
+		var err error
+		if srv.TLSConfig != nil {
+			// The certificates are served from the TLSConfig
+			err = srv.ListenAndServeTLS("", "")
+		} else {
+			err = srv.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
+			logger.Fatalf("failed to start eventlistener sink: %v", err)
+		}
+	}()
+
+	<-ctx.Done()
+	// Stop receiving new events, then wait for those being handled, which
+	// may queue Triggers, before draining the event queue
+	logger.Info("Shutting down the eventlistener sink")
+	deadline := time.Now().Add(sinkArgs.ELShutdownTimeOut * time.Second)
+	shutdownCtx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		logger.Errorf("failed to shut down the eventlistener sink: %v", err)
+	}
+	if err := drainer.Wait(time.Until(deadline)); err != nil {
+		logger.Error(err)
+	}
+	logger.Info("Draining the event queue")
+	drainDeadline := time.Now().Add(sinkArgs.ELDrainTimeOut * time.Second)
+	if err := r.EventQueue.Drain(time.Until(drainDeadline)); err != nil {
+		logger.Error(err)
+	}
+	// The CloudEvents of the drained Triggers are sent within the same time
+	if err := r.CloudEvents.Drain(time.Until(drainDeadline)); err != nil {
+		logger.Error(err)
 	}
 }
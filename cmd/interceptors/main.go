@@ -0,0 +1,138 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/tektoncd/triggers/pkg/interceptors"
+	"github.com/tektoncd/triggers/pkg/interceptors/server"
+	triggerLogging "github.com/tektoncd/triggers/pkg/logging"
+	"github.com/tektoncd/triggers/pkg/tracing"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"knative.dev/pkg/signals"
+)
+
+const (
+	// InterceptorsLogKey is the name of the logger for the interceptors cmd
+	InterceptorsLogKey = "interceptors"
+	// ConfigName is the name of the ConfigMap that the logging config will be stored in
+	ConfigName = "config-logging-triggers"
+)
+
+var (
+	portFlag = flag.Int("port", 8082,
+		"The port that the interceptors are served on.")
+	secretTTLFlag = flag.Duration("secretttl", time.Minute,
+		"How long the values of the secrets read by the interceptors are cached for.")
+	shutdownTimeOutFlag = flag.Duration("shutdowntimeout", 10*time.Second,
+		"How long the requests being intercepted are waited for on shutdown.")
+	tracingEndpointFlag = flag.String("tracingendpoint", "",
+		"The address of the OTLP receiver that traces are exported to. Traces are not exported when it is empty.")
+	tracingSampleRateFlag = flag.Float64("tracingsamplerate", 1,
+		"The probability that the traces started by the interceptors are sampled.")
+	authenticateFlag = flag.Bool("authenticate", true,
+		"Whether callers must authenticate with a ServiceAccount token, only intercepting the events of the Triggers in their namespace.")
+	tokenReviewTTLFlag = flag.Duration("tokenreviewttl", time.Minute,
+		"How long the outcome of reviewing the token of a caller is cached for.")
+	tlsCertFileFlag = flag.String("tlscertfile", "",
+		"The file holding the certificate served over HTTPS. The interceptors are served over HTTP if empty.")
+	tlsKeyFileFlag = flag.String("tlskeyfile", "",
+		"The file holding the private key of the certificate served over HTTPS.")
+)
+
+func main() {
+	// set up signals so we handle the first shutdown signal gracefully
+	ctx := signals.NewContext()
+
+	clusterConfig, err := rest.InClusterConfig()
+	if err != nil {
+		log.Fatalf("Failed to get in cluster config: %v", err)
+	}
+	kubeClient, err := kubernetes.NewForConfig(clusterConfig)
+	if err != nil {
+		log.Fatalf("Failed to get the Kubernetes client set: %v", err)
+	}
+
+	// Parses the flags
+	logger := triggerLogging.ConfigureLogging(InterceptorsLogKey, ConfigName, ctx.Done(), kubeClient)
+	defer func() {
+		_ = logger.Sync()
+	}()
+
+	if *tracingEndpointFlag != "" {
+		flush, err := tracing.Setup(*tracingEndpointFlag, *tracingSampleRateFlag)
+		if err != nil {
+			logger.Fatalf("failed to set up tracing: %v", err)
+		}
+		defer flush()
+		logger.Infof("Exporting traces to %s", *tracingEndpointFlag)
+	}
+
+	if (*tlsCertFileFlag == "") != (*tlsKeyFileFlag == "") {
+		logger.Fatal("-tlscertfile and -tlskeyfile must be set together")
+	}
+
+	s := server.NewWithCoreInterceptors(kubeClient, interceptors.NewSecretCache(*secretTTLFlag), logger)
+	if *authenticateFlag {
+		s.Authenticator = server.NewTokenReviewAuthenticator(kubeClient, interceptors.TokenAudience, *tokenReviewTTLFlag)
+	}
+	readiness := &server.Readiness{}
+	go readiness.Check(ctx, kubeClient, time.Second)
+
+	mux := http.NewServeMux()
+	mux.Handle("/", s)
+	mux.HandleFunc("/live", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, "ok")
+	})
+	mux.Handle("/ready", readiness)
+
+	srv := &http.Server{
+		Addr:         fmt.Sprintf(":%d", *portFlag),
+		ReadTimeout:  5 * time.Second,
+		WriteTimeout: 20 * time.Second,
+		IdleTimeout:  120 * time.Second,
+		Handler:      mux,
+	}
+	go func() {
+		logger.Infof("Serving the interceptors on port %d", *portFlag)
+		var err error
+		if *tlsCertFileFlag != "" {
+			err = srv.ListenAndServeTLS(*tlsCertFileFlag, *tlsKeyFileFlag)
+		} else {
+			err = srv.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
+			logger.Fatalf("failed to start the interceptors server: %v", err)
+		}
+	}()
+
+	<-ctx.Done()
+	logger.Info("Shutting down the interceptors server")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), *shutdownTimeOutFlag)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		logger.Errorf("failed to shut down the interceptors server: %v", err)
+	}
+}
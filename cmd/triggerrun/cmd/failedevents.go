@@ -0,0 +1,158 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	triggersclientset "github.com/tektoncd/triggers/pkg/client/clientset/versioned"
+	"github.com/tektoncd/triggers/pkg/client/informers/externalversions"
+	"github.com/tektoncd/triggers/pkg/sink"
+	"go.uber.org/zap"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+var (
+	failedEventsCmd = &cobra.Command{
+		Use:   "failed-events",
+		Short: "List and replay the events that failed to create resources.",
+		Long:  "failed-events works with the events an EventListener with a deadLetter kept because they failed to create the resources of a Trigger.",
+	}
+	failedEventsListCmd = &cobra.Command{
+		Use:   "list",
+		Short: "List the failed events of an EventListener.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			kubeClient, _, err := getKubeClient(kubeconfig)
+			if err != nil {
+				return fmt.Errorf("fail to get clients: %w", err)
+			}
+			return listFailedEvents(kubeClient, failedEventsNamespace, failedEventsEventListener, os.Stdout)
+		},
+	}
+	failedEventsReplayCmd = &cobra.Command{
+		Use:   "replay NAME",
+		Short: "Replay a failed event of an EventListener.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return replay(args[0], os.Stdout)
+		},
+	}
+	failedEventsNamespace     string
+	failedEventsEventListener string
+)
+
+func init() {
+	failedEventsCmd.PersistentFlags().StringVarP(&failedEventsNamespace, "namespace", "n", "default", "namespace of the EventListener")
+	failedEventsCmd.PersistentFlags().StringVarP(&failedEventsEventListener, "eventlistener", "e", "", "name of the EventListener")
+	failedEventsCmd.AddCommand(failedEventsListCmd, failedEventsReplayCmd)
+	rootCmd.AddCommand(failedEventsCmd)
+}
+
+func listFailedEvents(kubeClient kubernetes.Interface, namespace, eventListener string, writer io.Writer) error {
+	if eventListener == "" {
+		return errors.New("the EventListener must be set with --eventlistener")
+	}
+	events, err := sink.ListFailedEvents(context.Background(), kubeClient, namespace, eventListener)
+	if err != nil {
+		return fmt.Errorf("fail to list failed events: %w", err)
+	}
+	w := tabwriter.NewWriter(writer, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tTRIGGER\tEVENT ID\tFAILED AT\tERROR")
+	for _, fe := range events {
+		trigger := fe.Trigger.Name
+		if trigger == "" {
+			trigger = fe.Trigger.TriggerRef
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", fe.Name, trigger, fe.EventID, fe.FailedAt.Format(time.RFC3339), fe.Error)
+	}
+	return w.Flush()
+}
+
+func replay(name string, writer io.Writer) error {
+	kubeClient, triggerClient, err := getKubeClient(kubeconfig)
+	if err != nil {
+		return fmt.Errorf("fail to get clients: %w", err)
+	}
+	fe, err := sink.GetFailedEvent(context.Background(), kubeClient, failedEventsNamespace, name)
+	if err != nil {
+		return fmt.Errorf("fail to get failed event %s: %w", name, err)
+	}
+	config, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
+	if err != nil {
+		return fmt.Errorf("Failed to build config from the flags: %w", err)
+	}
+	logger, _ := zap.NewProduction()
+	r := newSink(config, logger.Sugar())
+	r.TriggersClient = triggerClient
+	r.EventListenerName = fe.EventListener
+	r.EventListenerNamespace = fe.Namespace
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := startListers(ctx, &r, triggerClient); err != nil {
+		return err
+	}
+	return replayFailedEvent(r, fe, writer)
+}
+
+// listerSyncTimeout bounds the wait for the informers of the listers to sync,
+// which never happens when they may not list and watch their resources.
+const listerSyncTimeout = 30 * time.Second
+
+// startListers sets the listers of r, watching the resources of its
+// EventListener's namespace until ctx is done. The ClusterInterceptors are
+// only watched once a Trigger refers to one.
+func startListers(ctx context.Context, r *sink.Sink, client triggersclientset.Interface) error {
+	factory := externalversions.NewSharedInformerFactoryWithOptions(client, 0, externalversions.WithNamespace(r.EventListenerNamespace))
+	informers := factory.Triggers().V1alpha1()
+	r.EventListenerLister = informers.EventListeners().Lister()
+	r.TriggerLister = informers.Triggers().Lister()
+	r.TriggerBindingLister = informers.TriggerBindings().Lister()
+	r.ClusterTriggerBindingLister = informers.ClusterTriggerBindings().Lister()
+	r.TriggerTemplateLister = informers.TriggerTemplates().Lister()
+	r.ClusterInterceptorLister = sink.NewLazyClusterInterceptorLister(ctx, factory)
+	factory.Start(ctx.Done())
+	syncCtx, cancel := context.WithTimeout(ctx, listerSyncTimeout)
+	defer cancel()
+	for informer, synced := range factory.WaitForCacheSync(syncCtx.Done()) {
+		if !synced {
+			return fmt.Errorf("fail to sync the informer for %s within %s, check that you may list and watch it", informer, listerSyncTimeout)
+		}
+	}
+	return nil
+}
+
+func replayFailedEvent(r sink.Sink, fe sink.FailedEvent, writer io.Writer) error {
+	result, err := r.ReplayFailedEvent(context.Background(), fe)
+	if err != nil {
+		return fmt.Errorf("fail to replay failed event %s: %w", fe.Name, err)
+	}
+	fmt.Fprintf(writer, "Replayed failed event %s\n", fe.Name)
+	for _, c := range result.Resources {
+		fmt.Fprintf(writer, "Created %s %s/%s\n", c.Kind, c.Namespace, c.Name)
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	triggersv1 "github.com/tektoncd/triggers/pkg/apis/triggers/v1alpha1"
+	faketriggersclient "github.com/tektoncd/triggers/pkg/client/clientset/versioned/fake"
+	"github.com/tektoncd/triggers/pkg/sink"
+	"github.com/tektoncd/triggers/test"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ktesting "k8s.io/client-go/testing"
+)
+
+func TestListFailedEvents(t *testing.T) {
+	failedAt := metav1.NewTime(time.Date(2020, 10, 1, 12, 0, 0, 0, time.UTC))
+	data, err := json.Marshal(sink.FailedEvent{
+		EventListener: "my-el",
+		Namespace:     "default",
+		EventID:       "ab1cd",
+		Trigger:       triggersv1.EventListenerTrigger{TriggerRef: "my-trigger"},
+		Error:         "create failed",
+		FailedAt:      failedAt,
+	})
+	if err != nil {
+		t.Fatalf("failed to marshal the failed event: %v", err)
+	}
+	kubeClient, _ := getFakeTriggersClient(t, test.Resources{
+		Secrets: []*corev1.Secret{{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "my-el-failed-ab1cd-0123abcd",
+				Namespace: "default",
+				Labels: map[string]string{
+					"triggers.tekton.dev/eventlistener": "my-el",
+					"triggers.tekton.dev/failed-event":  "true",
+				},
+			},
+			Data: map[string][]byte{"event.json": data},
+		}},
+	})
+
+	buf := new(bytes.Buffer)
+	if err := listFailedEvents(kubeClient, "default", "my-el", buf); err != nil {
+		t.Fatalf("listFailedEvents() unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("listFailedEvents() printed %d lines, want a header and an event:\n%s", len(lines), buf)
+	}
+	if got := strings.Fields(lines[1]); strings.Join(got, " ") != "my-el-failed-ab1cd-0123abcd my-trigger ab1cd 2020-10-01T12:00:00Z create failed" {
+		t.Errorf("listFailedEvents() printed %q", lines[1])
+	}
+}
+
+func TestListFailedEvents_NoEventListener(t *testing.T) {
+	kubeClient, _ := getFakeTriggersClient(t, test.Resources{})
+	if err := listFailedEvents(kubeClient, "default", "", new(bytes.Buffer)); err == nil {
+		t.Error("listFailedEvents() expected an error without an EventListener")
+	}
+}
+
+func TestStartListers(t *testing.T) {
+	_, triggerClient := getFakeTriggersClient(t, test.Resources{
+		EventListeners: []*triggersv1.EventListener{{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-el", Namespace: "default"},
+		}},
+	})
+	// ClusterInterceptors are only watched once a Trigger refers to one, so
+	// the listers sync without being allowed to list them
+	triggerClient.(*faketriggersclient.Clientset).PrependReactor("list", "clusterinterceptors", func(action ktesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(triggersv1.Resource("clusterinterceptors"), "", errors.New("not allowed to list clusterinterceptors"))
+	})
+	r := sink.Sink{EventListenerNamespace: "default"}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := startListers(ctx, &r, triggerClient); err != nil {
+		t.Fatalf("startListers() unexpected error: %v", err)
+	}
+	if _, err := r.EventListenerLister.EventListeners("default").Get("my-el"); err != nil {
+		t.Errorf("EventListenerLister did not list the EventListener: %v", err)
+	}
+}
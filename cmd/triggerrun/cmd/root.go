@@ -127,7 +127,7 @@ func trigger(triggerFile, httpPath, action, kubeconfig string, writer io.Writer)
 			}
 		case "create":
 			{
-				err := r.CreateResources("", resources, tri.Name, eventID, eventLog)
+				_, err := r.CreateResources(context.Background(), "", resources, tri.Name, eventID, tri.Spec.Retry, tri.Spec.DryRun, eventLog)
 				if err != nil {
 					return fmt.Errorf("fail to create resources: %w", err)
 				}
@@ -0,0 +1,117 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+)
+
+// Check that ClusterInterceptor may be validated and defaulted.
+var _ apis.Validatable = (*ClusterInterceptor)(nil)
+var _ apis.Defaultable = (*ClusterInterceptor)(nil)
+
+// +genclient
+// +genclient:nonNamespaced
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +k8s:openapi-gen=true
+
+// ClusterInterceptor registers an interceptor that runs out of process.
+// Triggers in any namespace refer to it by name, and the EventListener sends
+// it the events to intercept as JSON encoded InterceptorRequests over HTTP.
+type ClusterInterceptor struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec holds the desired state of the ClusterInterceptor from the client
+	Spec ClusterInterceptorSpec `json:"spec"`
+}
+
+// ClusterInterceptorSpec describes how to reach the interceptor.
+type ClusterInterceptorSpec struct {
+	ClientConfig ClientConfig `json:"clientConfig"`
+}
+
+// ClientConfig describes how the EventListener connects to an interceptor.
+// Exactly one of URL and Service is specified.
+type ClientConfig struct {
+	// URL is the address of the interceptor
+	// +optional
+	URL *apis.URL `json:"url,omitempty"`
+	// Service is a reference to the Service of the interceptor
+	// +optional
+	Service *ServiceReference `json:"service,omitempty"`
+	// CaBundle is a PEM encoded CA bundle used to verify the certificate of
+	// the interceptor. The interceptor is reached over HTTPS when it is set.
+	// +optional
+	CaBundle []byte `json:"caBundle,omitempty"`
+}
+
+// ServiceReference is a reference to the Service of an interceptor.
+type ServiceReference struct {
+	// Name is the name of the Service
+	Name string `json:"name"`
+	// Namespace is the namespace of the Service
+	Namespace string `json:"namespace"`
+	// Path is the path that events are sent to
+	// +optional
+	Path string `json:"path,omitempty"`
+	// Port is the port of the Service, 80 or 443 by default
+	// +optional
+	Port *int32 `json:"port,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClusterInterceptorList contains a list of ClusterInterceptor
+type ClusterInterceptorList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterInterceptor `json:"items"`
+}
+
+// ResolveURL returns the URL that the events to intercept are sent to.
+func (ci *ClusterInterceptor) ResolveURL() (*url.URL, error) {
+	cc := ci.Spec.ClientConfig
+	switch {
+	case cc.URL != nil:
+		u := url.URL(*cc.URL)
+		return &u, nil
+	case cc.Service != nil:
+		u := &url.URL{
+			Scheme: "http",
+			Host:   fmt.Sprintf("%s.%s.svc", cc.Service.Name, cc.Service.Namespace),
+			Path:   cc.Service.Path,
+		}
+		if len(cc.CaBundle) != 0 {
+			u.Scheme = "https"
+		}
+		if cc.Service.Port != nil {
+			u.Host = fmt.Sprintf("%s:%d", u.Host, *cc.Service.Port)
+		}
+		return u, nil
+	default:
+		return nil, errors.New("clientConfig sets neither url nor service")
+	}
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+
+	"github.com/tektoncd/pipeline/pkg/apis/validate"
+	"knative.dev/pkg/apis"
+)
+
+// Validate validates a ClusterInterceptor.
+func (ci *ClusterInterceptor) Validate(ctx context.Context) *apis.FieldError {
+	if err := validate.ObjectMetadata(ci.GetObjectMeta()); err != nil {
+		return err.ViaField("metadata")
+	}
+	return ci.Spec.ClientConfig.validate(ctx).ViaField("spec.clientConfig")
+}
+
+func (cc *ClientConfig) validate(ctx context.Context) (errs *apis.FieldError) {
+	switch {
+	case cc.URL == nil && cc.Service == nil:
+		errs = errs.Also(apis.ErrMissingOneOf("url", "service"))
+	case cc.URL != nil && cc.Service != nil:
+		errs = errs.Also(apis.ErrMultipleOneOf("url", "service"))
+	case cc.URL != nil:
+		if cc.URL.Scheme == "" || cc.URL.Host == "" {
+			errs = errs.Also(apis.ErrInvalidValue(errors.New("url must be absolute"), "url"))
+		} else if cc.URL.Scheme != "http" && cc.URL.Scheme != "https" {
+			errs = errs.Also(apis.ErrInvalidValue(fmt.Errorf("unsupported scheme %q", cc.URL.Scheme), "url"))
+		} else if len(cc.CaBundle) != 0 && cc.URL.Scheme != "https" {
+			errs = errs.Also(apis.ErrInvalidValue(errors.New("url must use https with a caBundle"), "url"))
+		}
+	case cc.Service != nil:
+		if cc.Service.Name == "" {
+			errs = errs.Also(apis.ErrMissingField("service.name"))
+		}
+		if cc.Service.Namespace == "" {
+			errs = errs.Also(apis.ErrMissingField("service.namespace"))
+		}
+		if p := cc.Service.Port; p != nil && (*p < 1 || *p > 65535) {
+			errs = errs.Also(apis.ErrOutOfBoundsValue(*p, 1, 65535, "service.port"))
+		}
+	}
+	if len(cc.CaBundle) != 0 && !x509.NewCertPool().AppendCertsFromPEM(cc.CaBundle) {
+		errs = errs.Also(apis.ErrInvalidValue(errors.New("no PEM encoded certificates"), "caBundle"))
+	}
+	return errs
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/tektoncd/triggers/pkg/apis/triggers/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/ptr"
+)
+
+// testCABundle is a self-signed certificate for tests.
+const testCABundle = `-----BEGIN CERTIFICATE-----
+MIIBejCCAR+gAwIBAgIUC6uB4hLyptIJfv1f/cDb+hMnMX4wCgYIKoZIzj0EAwIw
+EjEQMA4GA1UEAwwHdGVzdC1jYTAeFw0yNjEwMTYxNjE4NDFaFw0zNjEwMTMxNjE4
+NDFaMBIxEDAOBgNVBAMMB3Rlc3QtY2EwWTATBgcqhkjOPQIBBggqhkjOPQMBBwNC
+AATd5K57hgIyECitKYktAmOwEI/mE8HsItTZFvRS2L1CSHZx5bHYsiu2oA1K4QFu
+77HcgJZKUEGPzn/Ht0iZjZW0o1MwUTAdBgNVHQ4EFgQU/WVh7jUoh1MiOdihDWad
+MWwXfPQwHwYDVR0jBBgwFoAU/WVh7jUoh1MiOdihDWadMWwXfPQwDwYDVR0TAQH/
+BAUwAwEB/zAKBggqhkjOPQQDAgNJADBGAiEA8tJJBNv3Ptm1GXDK4xmQikEG7n3k
+ddSoy/YMQFT13qICIQDnLp9PJSj3YiYtYrRWzaQYFSlay5VKMBYbe1IrSQkBjw==
+-----END CERTIFICATE-----`
+
+func clusterInterceptor(cc v1alpha1.ClientConfig) *v1alpha1.ClusterInterceptor {
+	return &v1alpha1.ClusterInterceptor{
+		ObjectMeta: metav1.ObjectMeta{Name: "scanner"},
+		Spec:       v1alpha1.ClusterInterceptorSpec{ClientConfig: cc},
+	}
+}
+
+func mustParseURL(t *testing.T, u string) *apis.URL {
+	t.Helper()
+	parsed, err := apis.ParseURL(u)
+	if err != nil {
+		t.Fatalf("apis.ParseURL(%q) unexpected error: %v", u, err)
+	}
+	return parsed
+}
+
+func TestClusterInterceptorValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		ci   *v1alpha1.ClusterInterceptor
+	}{{
+		name: "url",
+		ci:   clusterInterceptor(v1alpha1.ClientConfig{URL: mustParseURL(t, "http://scanner.example.com/intercept")}),
+	}, {
+		name: "https url with a caBundle",
+		ci: clusterInterceptor(v1alpha1.ClientConfig{
+			URL:      mustParseURL(t, "https://scanner.example.com"),
+			CaBundle: []byte(testCABundle),
+		}),
+	}, {
+		name: "service",
+		ci: clusterInterceptor(v1alpha1.ClientConfig{
+			Service: &v1alpha1.ServiceReference{Name: "scanner", Namespace: "tools", Path: "/intercept", Port: ptr.Int32(8080)},
+		}),
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.ci.Validate(context.Background()); err != nil {
+				t.Errorf("ClusterInterceptor.Validate() returned error: %s", err)
+			}
+		})
+	}
+}
+
+func TestClusterInterceptorValidate_error(t *testing.T) {
+	tests := []struct {
+		name string
+		ci   *v1alpha1.ClusterInterceptor
+	}{{
+		name: "neither url nor service",
+		ci:   clusterInterceptor(v1alpha1.ClientConfig{}),
+	}, {
+		name: "both url and service",
+		ci: clusterInterceptor(v1alpha1.ClientConfig{
+			URL:     mustParseURL(t, "http://scanner.example.com"),
+			Service: &v1alpha1.ServiceReference{Name: "scanner", Namespace: "tools"},
+		}),
+	}, {
+		name: "relative url",
+		ci:   clusterInterceptor(v1alpha1.ClientConfig{URL: mustParseURL(t, "/intercept")}),
+	}, {
+		name: "unsupported scheme",
+		ci:   clusterInterceptor(v1alpha1.ClientConfig{URL: mustParseURL(t, "ftp://scanner.example.com")}),
+	}, {
+		name: "http url with a caBundle",
+		ci: clusterInterceptor(v1alpha1.ClientConfig{
+			URL:      mustParseURL(t, "http://scanner.example.com"),
+			CaBundle: []byte(testCABundle),
+		}),
+	}, {
+		name: "invalid caBundle",
+		ci: clusterInterceptor(v1alpha1.ClientConfig{
+			URL:      mustParseURL(t, "https://scanner.example.com"),
+			CaBundle: []byte("not a certificate"),
+		}),
+	}, {
+		name: "service without a namespace",
+		ci: clusterInterceptor(v1alpha1.ClientConfig{
+			Service: &v1alpha1.ServiceReference{Name: "scanner"},
+		}),
+	}, {
+		name: "service with an invalid port",
+		ci: clusterInterceptor(v1alpha1.ClientConfig{
+			Service: &v1alpha1.ServiceReference{Name: "scanner", Namespace: "tools", Port: ptr.Int32(0)},
+		}),
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.ci.Validate(context.Background()); err == nil {
+				t.Errorf("ClusterInterceptor.Validate() expected error for ClusterInterceptor: %v", tt.ci)
+			}
+		})
+	}
+}
+
+func TestClusterInterceptor_ResolveURL(t *testing.T) {
+	tests := []struct {
+		name string
+		cc   v1alpha1.ClientConfig
+		want string
+	}{{
+		name: "url",
+		cc:   v1alpha1.ClientConfig{URL: mustParseURL(t, "http://scanner.example.com/intercept")},
+		want: "http://scanner.example.com/intercept",
+	}, {
+		name: "service",
+		cc:   v1alpha1.ClientConfig{Service: &v1alpha1.ServiceReference{Name: "scanner", Namespace: "tools"}},
+		want: "http://scanner.tools.svc",
+	}, {
+		name: "service with a port and path",
+		cc: v1alpha1.ClientConfig{
+			Service: &v1alpha1.ServiceReference{Name: "scanner", Namespace: "tools", Path: "/intercept", Port: ptr.Int32(8080)},
+		},
+		want: "http://scanner.tools.svc:8080/intercept",
+	}, {
+		name: "service with a caBundle",
+		cc: v1alpha1.ClientConfig{
+			Service:  &v1alpha1.ServiceReference{Name: "scanner", Namespace: "tools"},
+			CaBundle: []byte(testCABundle),
+		},
+		want: "https://scanner.tools.svc",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := clusterInterceptor(tt.cc).ResolveURL()
+			if err != nil {
+				t.Fatalf("ResolveURL() unexpected error: %v", err)
+			}
+			if got.String() != tt.want {
+				t.Errorf("ResolveURL() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -59,8 +59,248 @@ type EventListenerSpec struct {
 	Replicas           *int32                 `json:"replicas,omitempty"`
 	PodTemplate        PodTemplate            `json:"podTemplate,omitempty"`
 	Resources          Resources              `json:"resources,omitempty"`
+	// ProcessingMode determines whether the EventListener waits for its
+	// Triggers to be processed before responding to an event.
+	// Defaults to Sync.
+	// +optional
+	ProcessingMode EventProcessingMode `json:"processingMode,omitempty"`
+	// Deduplication configures the EventListener to skip events that were
+	// already delivered.
+	// +optional
+	Deduplication *Deduplication `json:"deduplication,omitempty"`
+	// CloudEventReply makes the EventListener reply to CloudEvents with a
+	// CloudEvent whose data is the EventListener's response.
+	// +optional
+	CloudEventReply bool `json:"cloudEventReply,omitempty"`
+	// CloudEventURI is the URI that the EventListener sends CloudEvents
+	// about the outcome of processing events to.
+	// +optional
+	CloudEventURI string `json:"cloudEventURI,omitempty"`
+	// MatchPolicy determines whether an event is processed by all of the
+	// EventListener's Triggers or only by the first one that matches it.
+	// Defaults to All.
+	// +optional
+	MatchPolicy TriggerMatchPolicy `json:"matchPolicy,omitempty"`
+	// RateLimit limits the rate and concurrency of the events the
+	// EventListener processes.
+	// +optional
+	RateLimit *RateLimit `json:"rateLimit,omitempty"`
+	// TLS configures the EventListener to serve HTTPS.
+	// +optional
+	TLS *EventListenerTLS `json:"tls,omitempty"`
+	// DeadLetter keeps the events that failed to create the resources of a
+	// Trigger so that they can be replayed.
+	// +optional
+	DeadLetter *DeadLetter `json:"deadLetter,omitempty"`
+	// Admin serves an admin endpoint replaying the recent events of the
+	// EventListener without creating resources, for debugging.
+	// +optional
+	Admin *EventListenerAdmin `json:"admin,omitempty"`
+	// Authentication authenticates the requests sent to the EventListener
+	// before any of its Triggers processes them.
+	// +optional
+	Authentication *EventListenerAuthentication `json:"authentication,omitempty"`
+	// AllowedSources restricts the addresses that the EventListener accepts
+	// requests from.
+	// +optional
+	AllowedSources *AllowedSources `json:"allowedSources,omitempty"`
+}
+
+// AllowedSources restricts the addresses that the EventListener accepts
+// requests from.
+type AllowedSources struct {
+	// CIDRs are the ranges of addresses, such as 192.30.252.0/22, that
+	// requests are accepted from.
+	CIDRs []string `json:"cidrs"`
+	// TrustedProxies are the ranges of addresses of the proxies, such as an
+	// ingress controller, whose X-Forwarded-For header is trusted to hold the
+	// address of the client that sent a request.
+	// +optional
+	TrustedProxies []string `json:"trustedProxies,omitempty"`
+}
+
+// EventListenerAuthentication configures how the requests sent to the
+// EventListener are authenticated. Exactly one of its fields must be set.
+type EventListenerAuthentication struct {
+	// BearerToken requires requests to send the token held in a Secret.
+	// +optional
+	BearerToken *BearerTokenAuthentication `json:"bearerToken,omitempty"`
+	// HMAC requires requests to sign their body with the key held in a
+	// Secret.
+	// +optional
+	HMAC *HMACAuthentication `json:"hmac,omitempty"`
+	// TokenReview requires requests to send a token that the Kubernetes API
+	// server authenticates, such as that of a ServiceAccount.
+	// +optional
+	TokenReview *TokenReviewAuthentication `json:"tokenReview,omitempty"`
+}
+
+// BearerTokenAuthentication requires requests to send the token held in a
+// Secret in their Authorization header, as in "Authorization: Bearer <token>".
+type BearerTokenAuthentication struct {
+	SecretRef SecretRef `json:"secretRef"`
+}
+
+// The HMAC algorithms that requests can be signed with.
+const (
+	HMACSHA1   = "sha1"
+	HMACSHA256 = "sha256"
+	HMACSHA512 = "sha512"
+)
+
+// HMACAuthentication requires requests to send the hex encoded HMAC of their
+// body, keyed with the value held in a Secret, in a header.
+type HMACAuthentication struct {
+	SecretRef SecretRef `json:"secretRef"`
+	// Header is the header holding the signature, which may be prefixed
+	// with the algorithm as in "sha256=<signature>".
+	Header string `json:"header"`
+	// Algorithm is the hash function of the HMAC: sha1, sha256 or sha512.
+	// Defaults to sha256.
+	// +optional
+	Algorithm string `json:"algorithm,omitempty"`
+}
+
+// TokenReviewAuthentication requires requests to send, in their
+// Authorization header, a token that a TokenReview authenticates.
+type TokenReviewAuthentication struct {
+	// Audiences are the audiences that the token must be valid for. Defaults
+	// to the audiences of the Kubernetes API server.
+	// +optional
+	Audiences []string `json:"audiences,omitempty"`
+	// Users are the names of the users allowed to send events, such as
+	// system:serviceaccount:<namespace>:<name>. Defaults to any
+	// authenticated user.
+	// +optional
+	Users []string `json:"users,omitempty"`
+}
+
+// EventListenerAdmin configures the admin endpoint of the EventListener, served
+// on a separate port of its Pods.
+type EventListenerAdmin struct {
+	// TokenSecretName is the name of a Secret in the EventListener's
+	// namespace holding the bearer token (token) that requests to the admin
+	// endpoint must be sent with.
+	TokenSecretName string `json:"tokenSecretName"`
+	// RecentEvents is the number of recent events kept by each Pod of the
+	// EventListener so that they can be replayed. Defaults to 100.
+	// +optional
+	RecentEvents int32 `json:"recentEvents,omitempty"`
+}
+
+// DeadLetter configures how the events that failed to create the resources of
+// a Trigger are kept. Each of them is kept in a ConfigMap in the
+// EventListener's namespace.
+type DeadLetter struct {
+	// MaxEvents is the maximum number of failed events kept for the
+	// EventListener, after which the oldest ones are deleted. Defaults to
+	// 100.
+	// +optional
+	MaxEvents int32 `json:"maxEvents,omitempty"`
 }
 
+// EventListenerTLS holds the certificates the EventListener serves HTTPS
+// with. The certificates are reloaded when their Secrets change.
+type EventListenerTLS struct {
+	// SecretName is the name of a Secret of type kubernetes.io/tls in the
+	// EventListener's namespace holding the certificate (tls.crt) and private
+	// key (tls.key) served by the EventListener.
+	SecretName string `json:"secretName"`
+	// ClientCASecretName is the name of a Secret in the EventListener's
+	// namespace holding a bundle of CA certificates (ca.crt). When set,
+	// events must be sent with a client certificate signed by one of them.
+	// +optional
+	ClientCASecretName string `json:"clientCASecretName,omitempty"`
+}
+
+// Deduplication defines how the EventListener identifies redeliveries of an
+// event. Exactly one of Header or Expression must be set.
+type Deduplication struct {
+	// Header is the name of the request header holding the delivery key,
+	// e.g. X-GitHub-Delivery.
+	// +optional
+	Header string `json:"header,omitempty"`
+	// Expression is a CEL expression evaluated against the event's body,
+	// header and requestURL that returns the delivery key as a string.
+	// +optional
+	Expression string `json:"expression,omitempty"`
+	// TTL is how long a delivery key is remembered. Defaults to one hour.
+	// +optional
+	TTL *metav1.Duration `json:"ttl,omitempty"`
+}
+
+// RateLimit limits the events processed with a token bucket refilled with
+// Limit tokens every Period, and with a maximum number of events processed at
+// once. Events over the limit are rejected.
+type RateLimit struct {
+	// Limit is the number of events admitted every Period. Zero means
+	// that the rate is not limited.
+	// +optional
+	Limit int32 `json:"limit,omitempty"`
+	// Period is the period over which Limit events are admitted.
+	// Defaults to one second.
+	// +optional
+	Period *metav1.Duration `json:"period,omitempty"`
+	// Burst is the number of events admitted at once when the bucket is
+	// full. Defaults to Limit.
+	// +optional
+	Burst int32 `json:"burst,omitempty"`
+	// MaxInFlight is the maximum number of events processed at once. Zero
+	// means that the concurrency is not limited.
+	// +optional
+	MaxInFlight int32 `json:"maxInFlight,omitempty"`
+	// Key is a CEL expression evaluated on the event, such as
+	// body.repository.full_name. Events with different keys are limited
+	// separately.
+	// +optional
+	Key string `json:"key,omitempty"`
+}
+
+// RetryPolicy configures how creating the resources of a Trigger is retried
+// with an exponential backoff when it fails with a transient error, such as a
+// 429 or 5xx status code, a timeout or a conflict on a generated name.
+// Resources are created in a single attempt when it is not set.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts at creating each
+	// resource. Defaults to 3, and 1 disables retries.
+	// +optional
+	MaxAttempts int32 `json:"maxAttempts,omitempty"`
+	// Backoff is the delay before the first retry, which doubles before
+	// each next one. Defaults to one second.
+	// +optional
+	Backoff *metav1.Duration `json:"backoff,omitempty"`
+	// RetryGeneratedNames also retries creating resources named with a
+	// generateName after server errors and timeouts, which do not tell
+	// whether the resource was created. Such resources may then be created
+	// more than once.
+	// +optional
+	RetryGeneratedNames bool `json:"retryGeneratedNames,omitempty"`
+}
+
+// EventProcessingMode defines how the EventListener sink processes incoming events.
+type EventProcessingMode string
+
+const (
+	// SyncProcessingMode processes every Trigger before responding to the event.
+	SyncProcessingMode EventProcessingMode = "Sync"
+	// AsyncProcessingMode responds to the event as soon as it has been read and
+	// processes the Triggers in the background.
+	AsyncProcessingMode EventProcessingMode = "Async"
+)
+
+// TriggerMatchPolicy defines which of the EventListener's Triggers process an
+// incoming event.
+type TriggerMatchPolicy string
+
+const (
+	// AllMatchPolicy processes the event with every Trigger concurrently.
+	AllMatchPolicy TriggerMatchPolicy = "All"
+	// FirstMatchPolicy evaluates the Triggers one at a time in order of their
+	// Priority, and only processes the event with the first Trigger whose
+	// interceptors let it continue.
+	FirstMatchPolicy TriggerMatchPolicy = "FirstMatch"
+)
+
 type Resources struct {
 	KubernetesResource *KubernetesResource `json:"kubernetesResource,omitempty"`
 }
@@ -101,6 +341,32 @@ type EventListenerTrigger struct {
 	// multi-tenant model based scenarios
 	// +optional
 	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+	// Priority orders the Triggers of an EventListener with the FirstMatch
+	// MatchPolicy. Triggers with a higher Priority are evaluated first, and
+	// those with the same Priority in the order they are listed.
+	// +optional
+	Priority int32 `json:"priority,omitempty"`
+	// RateLimit limits the rate and concurrency of the events the Trigger
+	// creates resources for. It applies once the Trigger's interceptors let
+	// the event continue.
+	// +optional
+	RateLimit *RateLimit `json:"rateLimit,omitempty"`
+	// Retry configures how creating the Trigger's resources is retried when
+	// it fails with a transient error.
+	// +optional
+	Retry *RetryPolicy `json:"retry,omitempty"`
+	// DryRun creates the Trigger's resources with a server-side dry run, so
+	// that they are validated without being persisted.
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
+	// Path is the path of the requests that the Trigger processes the events
+	// of, such as /github. Defaults to all paths.
+	// +optional
+	Path string `json:"path,omitempty"`
+	// Method is the HTTP method of the requests that the Trigger processes
+	// the events of, such as POST. Defaults to all methods.
+	// +optional
+	Method string `json:"method,omitempty"`
 }
 
 // EventInterceptor provides a hook to intercept and pre-process events
@@ -259,12 +525,18 @@ func (el *EventListener) GetOwnerReference() *metav1.OwnerReference {
 
 // SetAddress sets the address (as part of Addressable contract) and marks the correct condition.
 func (els *EventListenerStatus) SetAddress(hostname string) {
+	els.SetAddressWithScheme("http", hostname)
+}
+
+// SetAddressWithScheme sets the address with the given URL scheme, such as
+// https.
+func (els *EventListenerStatus) SetAddressWithScheme(scheme, hostname string) {
 	if els.Address == nil {
 		els.Address = &duckv1alpha1.Addressable{}
 	}
 	if hostname != "" {
 		els.Address.URL = &apis.URL{
-			Scheme: "http",
+			Scheme: scheme,
 			Host:   hostname,
 		}
 	} else {
@@ -19,6 +19,10 @@ package v1alpha1
 import (
 	"context"
 	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/validation"
@@ -36,6 +40,47 @@ func (s *EventListenerSpec) validate(ctx context.Context) (errs *apis.FieldError
 			errs = errs.Also(apis.ErrInvalidValue(*s.Replicas, "spec.replicas"))
 		}
 	}
+	switch s.ProcessingMode {
+	case "", SyncProcessingMode, AsyncProcessingMode:
+	default:
+		errs = errs.Also(apis.ErrInvalidValue(s.ProcessingMode, "spec.processingMode"))
+	}
+	switch s.MatchPolicy {
+	case "", AllMatchPolicy, FirstMatchPolicy:
+	default:
+		errs = errs.Also(apis.ErrInvalidValue(s.MatchPolicy, "spec.matchPolicy"))
+	}
+	if s.Deduplication != nil {
+		errs = errs.Also(s.Deduplication.validate(ctx).ViaField("spec.deduplication"))
+	}
+	if s.DeadLetter != nil && s.DeadLetter.MaxEvents < 0 {
+		errs = errs.Also(apis.ErrInvalidValue(s.DeadLetter.MaxEvents, "spec.deadLetter.maxEvents"))
+	}
+	if s.RateLimit != nil {
+		errs = errs.Also(s.RateLimit.validate(ctx).ViaField("spec.rateLimit"))
+	}
+	if s.TLS != nil && s.TLS.SecretName == "" {
+		errs = errs.Also(apis.ErrMissingField("spec.tls.secretName"))
+	}
+	if s.Authentication != nil {
+		errs = errs.Also(s.Authentication.validate(ctx).ViaField("spec.authentication"))
+	}
+	if s.AllowedSources != nil {
+		errs = errs.Also(s.AllowedSources.validate(ctx).ViaField("spec.allowedSources"))
+	}
+	if s.Admin != nil {
+		if s.Admin.TokenSecretName == "" {
+			errs = errs.Also(apis.ErrMissingField("spec.admin.tokenSecretName"))
+		}
+		if s.Admin.RecentEvents < 0 {
+			errs = errs.Also(apis.ErrInvalidValue(s.Admin.RecentEvents, "spec.admin.recentEvents"))
+		}
+	}
+	if s.CloudEventURI != "" {
+		if u, err := url.Parse(s.CloudEventURI); err != nil || !u.IsAbs() {
+			errs = errs.Also(apis.ErrInvalidValue(s.CloudEventURI, "spec.cloudEventURI"))
+		}
+	}
 	if len(s.Triggers) == 0 {
 		errs = errs.Also(apis.ErrMissingField("spec.triggers"))
 	}
@@ -138,6 +183,58 @@ func podSpecMask(in *corev1.PodSpec) *corev1.PodSpec {
 	return out
 }
 
+func (d *Deduplication) validate(ctx context.Context) (errs *apis.FieldError) {
+	if d.Header == "" && d.Expression == "" {
+		errs = errs.Also(apis.ErrMissingOneOf("header", "expression"))
+	}
+	if d.Header != "" && d.Expression != "" {
+		errs = errs.Also(apis.ErrMultipleOneOf("header", "expression"))
+	}
+	if d.TTL != nil && d.TTL.Duration <= 0 {
+		errs = errs.Also(apis.ErrInvalidValue(d.TTL.Duration.String(), "ttl"))
+	}
+	return errs
+}
+
+func (l *RateLimit) validate(ctx context.Context) (errs *apis.FieldError) {
+	if l.Limit < 0 {
+		errs = errs.Also(apis.ErrInvalidValue(l.Limit, "limit"))
+	}
+	if l.Burst < 0 {
+		errs = errs.Also(apis.ErrInvalidValue(l.Burst, "burst"))
+	}
+	if l.MaxInFlight < 0 {
+		errs = errs.Also(apis.ErrInvalidValue(l.MaxInFlight, "maxInFlight"))
+	}
+	if l.Limit == 0 && l.MaxInFlight == 0 {
+		errs = errs.Also(apis.ErrMissingOneOf("limit", "maxInFlight"))
+	}
+	if l.Period != nil && l.Period.Duration <= 0 {
+		errs = errs.Also(apis.ErrInvalidValue(l.Period.Duration.String(), "period"))
+	}
+	return errs
+}
+
+func (p *RetryPolicy) validate(ctx context.Context) (errs *apis.FieldError) {
+	if p.MaxAttempts < 0 {
+		errs = errs.Also(apis.ErrInvalidValue(p.MaxAttempts, "maxAttempts"))
+	}
+	if p.Backoff != nil && p.Backoff.Duration <= 0 {
+		errs = errs.Also(apis.ErrInvalidValue(p.Backoff.Duration.String(), "backoff"))
+	}
+	return errs
+}
+
+// httpMethods are the HTTP methods that Triggers can respond to.
+var httpMethods = map[string]bool{
+	http.MethodGet:    true,
+	http.MethodHead:   true,
+	http.MethodPost:   true,
+	http.MethodPut:    true,
+	http.MethodPatch:  true,
+	http.MethodDelete: true,
+}
+
 func (t *EventListenerTrigger) validate(ctx context.Context) (errs *apis.FieldError) {
 	if t.Template == nil && t.TriggerRef == "" {
 		errs = errs.Also(apis.ErrMissingOneOf("template", "triggerRef"))
@@ -155,6 +252,28 @@ func (t *EventListenerTrigger) validate(ctx context.Context) (errs *apis.FieldEr
 		errs = errs.Also(interceptor.validate(ctx).ViaField(fmt.Sprintf("interceptors[%d]", i)))
 	}
 
+	if t.RateLimit != nil {
+		// Triggers are limited separately by name
+		if t.Name == "" && t.TriggerRef == "" {
+			errs = errs.Also(apis.ErrMissingField("name"))
+		}
+		errs = errs.Also(t.RateLimit.validate(ctx).ViaField("rateLimit"))
+	}
+
+	if t.Retry != nil {
+		errs = errs.Also(t.Retry.validate(ctx).ViaField("retry"))
+	}
+
+	// The sink serves its probes at /live and /ready, and batches of events
+	// under /batch
+	if t.Path != "" && (!strings.HasPrefix(t.Path, "/") || t.Path == "/live" || t.Path == "/ready" ||
+		t.Path == "/batch" || strings.HasPrefix(t.Path, "/batch/")) {
+		errs = errs.Also(apis.ErrInvalidValue(t.Path, "path"))
+	}
+	if t.Method != "" && !httpMethods[t.Method] {
+		errs = errs.Also(apis.ErrInvalidValue(t.Method, "method"))
+	}
+
 	// The trigger name is added as a label value for 'tekton.dev/trigger' so it must follow the k8s label guidelines:
 	// https://kubernetes.io/docs/concepts/overview/working-with-objects/labels/#syntax-and-character-set
 	if err := validation.IsValidLabelValue(t.Name); len(err) > 0 {
@@ -163,3 +282,61 @@ func (t *EventListenerTrigger) validate(ctx context.Context) (errs *apis.FieldEr
 
 	return errs
 }
+
+func (a *EventListenerAuthentication) validate(ctx context.Context) (errs *apis.FieldError) {
+	var set []string
+	if a.BearerToken != nil {
+		set = append(set, "bearerToken")
+		errs = errs.Also(a.BearerToken.SecretRef.validate().ViaField("bearerToken.secretRef"))
+	}
+	if a.HMAC != nil {
+		set = append(set, "hmac")
+		errs = errs.Also(a.HMAC.SecretRef.validate().ViaField("hmac.secretRef"))
+		if a.HMAC.Header == "" {
+			errs = errs.Also(apis.ErrMissingField("hmac.header"))
+		}
+		switch a.HMAC.Algorithm {
+		case "", HMACSHA1, HMACSHA256, HMACSHA512:
+		default:
+			errs = errs.Also(apis.ErrInvalidValue(a.HMAC.Algorithm, "hmac.algorithm"))
+		}
+	}
+	if a.TokenReview != nil {
+		set = append(set, "tokenReview")
+	}
+	switch len(set) {
+	case 0:
+		errs = errs.Also(apis.ErrMissingOneOf("bearerToken", "hmac", "tokenReview"))
+	case 1:
+	default:
+		errs = errs.Also(apis.ErrMultipleOneOf(set...))
+	}
+	return errs
+}
+
+func (r SecretRef) validate() (errs *apis.FieldError) {
+	if r.SecretName == "" {
+		errs = errs.Also(apis.ErrMissingField("secretName"))
+	}
+	if r.SecretKey == "" {
+		errs = errs.Also(apis.ErrMissingField("secretKey"))
+	}
+	return errs
+}
+
+func (a *AllowedSources) validate(ctx context.Context) (errs *apis.FieldError) {
+	if len(a.CIDRs) == 0 {
+		errs = errs.Also(apis.ErrMissingField("cidrs"))
+	}
+	for i, cidr := range a.CIDRs {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			errs = errs.Also(apis.ErrInvalidArrayValue(cidr, "cidrs", i))
+		}
+	}
+	for i, cidr := range a.TrustedProxies {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			errs = errs.Also(apis.ErrInvalidArrayValue(cidr, "trustedProxies", i))
+		}
+	}
+	return errs
+}
@@ -19,6 +19,7 @@ package v1alpha1_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/tektoncd/triggers/pkg/apis/triggers/v1alpha1"
 	bldr "github.com/tektoncd/triggers/test/builder"
@@ -163,6 +164,148 @@ func Test_EventListenerValidate(t *testing.T) {
 					bldr.EventListenerTriggerBinding("tb", "", "v1alpha1"),
 					bldr.EventListenerCELInterceptor("", bldr.EventListenerCELOverlay("body.value", "'testing'")),
 				))),
+	}, {
+		name: "Valid EventListener with Async processing mode",
+		el: bldr.EventListener("name", "namespace",
+			bldr.EventListenerSpec(
+				bldr.EventListenerProcessingMode(v1alpha1.AsyncProcessingMode),
+				bldr.EventListenerTrigger("tt", "v1alpha1"),
+			)),
+	}, {
+		name: "Valid EventListener with FirstMatch match policy",
+		el: bldr.EventListener("name", "namespace",
+			bldr.EventListenerSpec(
+				bldr.EventListenerMatchPolicy(v1alpha1.FirstMatchPolicy),
+				bldr.EventListenerTrigger("tt", "v1alpha1",
+					bldr.EventListenerTriggerPriority(10),
+				),
+				bldr.EventListenerTrigger("tt", "v1alpha1"),
+			)),
+	}, {
+		name: "Valid EventListener with header deduplication",
+		el: bldr.EventListener("name", "namespace",
+			bldr.EventListenerSpec(
+				bldr.EventListenerDeduplication(v1alpha1.Deduplication{
+					Header: "X-GitHub-Delivery",
+					TTL:    &metav1.Duration{Duration: 24 * time.Hour},
+				}),
+				bldr.EventListenerTrigger("tt", "v1alpha1"),
+			)),
+	}, {
+		name: "Valid EventListener with expression deduplication",
+		el: bldr.EventListener("name", "namespace",
+			bldr.EventListenerSpec(
+				bldr.EventListenerDeduplication(v1alpha1.Deduplication{
+					Expression: "body.head_commit.id",
+				}),
+				bldr.EventListenerTrigger("tt", "v1alpha1"),
+			)),
+	}, {
+		name: "Valid EventListener with rate limits",
+		el: bldr.EventListener("name", "namespace",
+			bldr.EventListenerSpec(
+				bldr.EventListenerRateLimit(v1alpha1.RateLimit{
+					Limit:       10,
+					Period:      &metav1.Duration{Duration: time.Minute},
+					Burst:       20,
+					MaxInFlight: 5,
+					Key:         "body.repository.full_name",
+				}),
+				bldr.EventListenerTrigger("tt", "v1alpha1",
+					bldr.EventListenerTriggerName("limited"),
+					bldr.EventListenerTriggerRateLimit(v1alpha1.RateLimit{MaxInFlight: 1}),
+				),
+			)),
+	}, {
+		name: "Valid EventListener with TLS",
+		el: bldr.EventListener("name", "namespace",
+			bldr.EventListenerSpec(
+				bldr.EventListenerTLS("el-tls", "el-client-ca"),
+				bldr.EventListenerTrigger("tt", "v1alpha1"),
+			)),
+	}, {
+		name: "Valid EventListener with a trigger retry policy",
+		el: bldr.EventListener("name", "namespace",
+			bldr.EventListenerSpec(
+				bldr.EventListenerTrigger("tt", "v1alpha1",
+					bldr.EventListenerTriggerRetry(v1alpha1.RetryPolicy{MaxAttempts: 5, Backoff: &metav1.Duration{Duration: time.Second}}),
+				),
+			)),
+	}, {
+		name: "Valid EventListener with bearer token authentication",
+		el: bldr.EventListener("name", "namespace",
+			bldr.EventListenerSpec(
+				bldr.EventListenerAuthentication(v1alpha1.EventListenerAuthentication{
+					BearerToken: &v1alpha1.BearerTokenAuthentication{
+						SecretRef: v1alpha1.SecretRef{SecretName: "token", SecretKey: "token"},
+					},
+				}),
+				bldr.EventListenerTrigger("tt", "v1alpha1"),
+			)),
+	}, {
+		name: "Valid EventListener with HMAC authentication",
+		el: bldr.EventListener("name", "namespace",
+			bldr.EventListenerSpec(
+				bldr.EventListenerAuthentication(v1alpha1.EventListenerAuthentication{
+					HMAC: &v1alpha1.HMACAuthentication{
+						SecretRef: v1alpha1.SecretRef{SecretName: "hmac", SecretKey: "key"},
+						Header:    "X-Signature",
+						Algorithm: v1alpha1.HMACSHA512,
+					},
+				}),
+				bldr.EventListenerTrigger("tt", "v1alpha1"),
+			)),
+	}, {
+		name: "Valid EventListener with TokenReview authentication",
+		el: bldr.EventListener("name", "namespace",
+			bldr.EventListenerSpec(
+				bldr.EventListenerAuthentication(v1alpha1.EventListenerAuthentication{
+					TokenReview: &v1alpha1.TokenReviewAuthentication{
+						Users: []string{"system:serviceaccount:ci:builder"},
+					},
+				}),
+				bldr.EventListenerTrigger("tt", "v1alpha1"),
+			)),
+	}, {
+		name: "Valid EventListener with allowed sources",
+		el: bldr.EventListener("name", "namespace",
+			bldr.EventListenerSpec(
+				bldr.EventListenerAllowedSources([]string{"192.30.252.0/22", "2620:112:3000::/44"}, "10.0.0.0/8"),
+				bldr.EventListenerTrigger("tt", "v1alpha1"),
+			)),
+	}, {
+		name: "Valid EventListener with an admin endpoint",
+		el: bldr.EventListener("name", "namespace",
+			bldr.EventListenerSpec(
+				bldr.EventListenerAdmin("el-admin", 10),
+				bldr.EventListenerTrigger("tt", "v1alpha1"),
+			)),
+	}, {
+		name: "Valid EventListener with a dead letter",
+		el: bldr.EventListener("name", "namespace",
+			bldr.EventListenerSpec(
+				bldr.EventListenerDeadLetter(10),
+				bldr.EventListenerTrigger("tt", "v1alpha1"),
+			)),
+	}, {
+		name: "Valid EventListener with trigger paths",
+		el: bldr.EventListener("name", "namespace",
+			bldr.EventListenerSpec(
+				bldr.EventListenerTrigger("tt", "v1alpha1",
+					bldr.EventListenerTriggerPath("/github"),
+					bldr.EventListenerTriggerMethod("POST"),
+				),
+				bldr.EventListenerTrigger("tt", "v1alpha1",
+					bldr.EventListenerTriggerPath("/gitlab"),
+				),
+			)),
+	}, {
+		name: "Valid EventListener with CloudEvent URI",
+		el: bldr.EventListener("name", "namespace",
+			bldr.EventListenerSpec(
+				bldr.EventListenerCloudEventURI("http://broker-ingress.knative-eventing.svc/default/default"),
+				bldr.EventListenerTrigger("tt", "v1alpha1"),
+			)),
 	}, {
 		name: "Valid EventListener with kubernetes resource for podspec",
 		el: bldr.EventListener("name", "namespace",
@@ -500,6 +643,242 @@ func TestEventListenerValidate_error(t *testing.T) {
 				bldr.EventListenerTrigger("tt", "v1alpha1",
 					bldr.EventListenerTriggerBinding("tb", "TriggerBinding", "v1alpha1"),
 				))),
+	}, {
+		name: "user specify invalid processing mode",
+		el: bldr.EventListener("name", "namespace",
+			bldr.EventListenerSpec(
+				bldr.EventListenerProcessingMode("Eventually"),
+				bldr.EventListenerTrigger("tt", "v1alpha1"),
+			)),
+	}, {
+		name: "user specify invalid match policy",
+		el: bldr.EventListener("name", "namespace",
+			bldr.EventListenerSpec(
+				bldr.EventListenerMatchPolicy("Any"),
+				bldr.EventListenerTrigger("tt", "v1alpha1"),
+			)),
+	}, {
+		name: "deduplication without header or expression",
+		el: bldr.EventListener("name", "namespace",
+			bldr.EventListenerSpec(
+				bldr.EventListenerDeduplication(v1alpha1.Deduplication{}),
+				bldr.EventListenerTrigger("tt", "v1alpha1"),
+			)),
+	}, {
+		name: "deduplication with both header and expression",
+		el: bldr.EventListener("name", "namespace",
+			bldr.EventListenerSpec(
+				bldr.EventListenerDeduplication(v1alpha1.Deduplication{
+					Header:     "X-GitHub-Delivery",
+					Expression: "body.head_commit.id",
+				}),
+				bldr.EventListenerTrigger("tt", "v1alpha1"),
+			)),
+	}, {
+		name: "deduplication with negative ttl",
+		el: bldr.EventListener("name", "namespace",
+			bldr.EventListenerSpec(
+				bldr.EventListenerDeduplication(v1alpha1.Deduplication{
+					Header: "X-GitHub-Delivery",
+					TTL:    &metav1.Duration{Duration: -time.Minute},
+				}),
+				bldr.EventListenerTrigger("tt", "v1alpha1"),
+			)),
+	}, {
+		name: "rate limit without limit or maxInFlight",
+		el: bldr.EventListener("name", "namespace",
+			bldr.EventListenerSpec(
+				bldr.EventListenerRateLimit(v1alpha1.RateLimit{Key: "body.repository.full_name"}),
+				bldr.EventListenerTrigger("tt", "v1alpha1"),
+			)),
+	}, {
+		name: "rate limit with negative limit",
+		el: bldr.EventListener("name", "namespace",
+			bldr.EventListenerSpec(
+				bldr.EventListenerRateLimit(v1alpha1.RateLimit{Limit: -1, MaxInFlight: 1}),
+				bldr.EventListenerTrigger("tt", "v1alpha1"),
+			)),
+	}, {
+		name: "rate limit with zero period",
+		el: bldr.EventListener("name", "namespace",
+			bldr.EventListenerSpec(
+				bldr.EventListenerRateLimit(v1alpha1.RateLimit{Limit: 1, Period: &metav1.Duration{}}),
+				bldr.EventListenerTrigger("tt", "v1alpha1"),
+			)),
+	}, {
+		name: "trigger rate limit without trigger name",
+		el: bldr.EventListener("name", "namespace",
+			bldr.EventListenerSpec(
+				bldr.EventListenerTrigger("tt", "v1alpha1",
+					bldr.EventListenerTriggerRateLimit(v1alpha1.RateLimit{Limit: 1}),
+				),
+			)),
+	}, {
+		name: "TLS without secret name",
+		el: bldr.EventListener("name", "namespace",
+			bldr.EventListenerSpec(
+				bldr.EventListenerTLS("", "el-client-ca"),
+				bldr.EventListenerTrigger("tt", "v1alpha1"),
+			)),
+	}, {
+		name: "negative trigger retry attempts",
+		el: bldr.EventListener("name", "namespace",
+			bldr.EventListenerSpec(
+				bldr.EventListenerTrigger("tt", "v1alpha1",
+					bldr.EventListenerTriggerRetry(v1alpha1.RetryPolicy{MaxAttempts: -1}),
+				),
+			)),
+	}, {
+		name: "zero trigger retry backoff",
+		el: bldr.EventListener("name", "namespace",
+			bldr.EventListenerSpec(
+				bldr.EventListenerTrigger("tt", "v1alpha1",
+					bldr.EventListenerTriggerRetry(v1alpha1.RetryPolicy{Backoff: &metav1.Duration{}}),
+				),
+			)),
+	}, {
+		name: "authentication without a mode",
+		el: bldr.EventListener("name", "namespace",
+			bldr.EventListenerSpec(
+				bldr.EventListenerAuthentication(v1alpha1.EventListenerAuthentication{}),
+				bldr.EventListenerTrigger("tt", "v1alpha1"),
+			)),
+	}, {
+		name: "authentication with two modes",
+		el: bldr.EventListener("name", "namespace",
+			bldr.EventListenerSpec(
+				bldr.EventListenerAuthentication(v1alpha1.EventListenerAuthentication{
+					BearerToken: &v1alpha1.BearerTokenAuthentication{
+						SecretRef: v1alpha1.SecretRef{SecretName: "token", SecretKey: "token"},
+					},
+					TokenReview: &v1alpha1.TokenReviewAuthentication{},
+				}),
+				bldr.EventListenerTrigger("tt", "v1alpha1"),
+			)),
+	}, {
+		name: "bearer token without secret key",
+		el: bldr.EventListener("name", "namespace",
+			bldr.EventListenerSpec(
+				bldr.EventListenerAuthentication(v1alpha1.EventListenerAuthentication{
+					BearerToken: &v1alpha1.BearerTokenAuthentication{
+						SecretRef: v1alpha1.SecretRef{SecretName: "token"},
+					},
+				}),
+				bldr.EventListenerTrigger("tt", "v1alpha1"),
+			)),
+	}, {
+		name: "HMAC without header",
+		el: bldr.EventListener("name", "namespace",
+			bldr.EventListenerSpec(
+				bldr.EventListenerAuthentication(v1alpha1.EventListenerAuthentication{
+					HMAC: &v1alpha1.HMACAuthentication{
+						SecretRef: v1alpha1.SecretRef{SecretName: "hmac", SecretKey: "key"},
+					},
+				}),
+				bldr.EventListenerTrigger("tt", "v1alpha1"),
+			)),
+	}, {
+		name: "HMAC with unknown algorithm",
+		el: bldr.EventListener("name", "namespace",
+			bldr.EventListenerSpec(
+				bldr.EventListenerAuthentication(v1alpha1.EventListenerAuthentication{
+					HMAC: &v1alpha1.HMACAuthentication{
+						SecretRef: v1alpha1.SecretRef{SecretName: "hmac", SecretKey: "key"},
+						Header:    "X-Signature",
+						Algorithm: "md5",
+					},
+				}),
+				bldr.EventListenerTrigger("tt", "v1alpha1"),
+			)),
+	}, {
+		name: "allowed sources without CIDRs",
+		el: bldr.EventListener("name", "namespace",
+			bldr.EventListenerSpec(
+				bldr.EventListenerAllowedSources(nil),
+				bldr.EventListenerTrigger("tt", "v1alpha1"),
+			)),
+	}, {
+		name: "allowed sources with an invalid CIDR",
+		el: bldr.EventListener("name", "namespace",
+			bldr.EventListenerSpec(
+				bldr.EventListenerAllowedSources([]string{"192.30.252.1"}),
+				bldr.EventListenerTrigger("tt", "v1alpha1"),
+			)),
+	}, {
+		name: "allowed sources with an invalid trusted proxy",
+		el: bldr.EventListener("name", "namespace",
+			bldr.EventListenerSpec(
+				bldr.EventListenerAllowedSources([]string{"192.30.252.0/22"}, "proxy"),
+				bldr.EventListenerTrigger("tt", "v1alpha1"),
+			)),
+	}, {
+		name: "admin without token secret",
+		el: bldr.EventListener("name", "namespace",
+			bldr.EventListenerSpec(
+				bldr.EventListenerAdmin("", 10),
+				bldr.EventListenerTrigger("tt", "v1alpha1"),
+			)),
+	}, {
+		name: "negative admin recent events",
+		el: bldr.EventListener("name", "namespace",
+			bldr.EventListenerSpec(
+				bldr.EventListenerAdmin("el-admin", -1),
+				bldr.EventListenerTrigger("tt", "v1alpha1"),
+			)),
+	}, {
+		name: "negative dead letter max events",
+		el: bldr.EventListener("name", "namespace",
+			bldr.EventListenerSpec(
+				bldr.EventListenerDeadLetter(-1),
+				bldr.EventListenerTrigger("tt", "v1alpha1"),
+			)),
+	}, {
+		name: "relative trigger path",
+		el: bldr.EventListener("name", "namespace",
+			bldr.EventListenerSpec(
+				bldr.EventListenerTrigger("tt", "v1alpha1",
+					bldr.EventListenerTriggerPath("github"),
+				),
+			)),
+	}, {
+		name: "trigger path of the probes",
+		el: bldr.EventListener("name", "namespace",
+			bldr.EventListenerSpec(
+				bldr.EventListenerTrigger("tt", "v1alpha1",
+					bldr.EventListenerTriggerPath("/live"),
+				),
+			)),
+	}, {
+		name: "trigger path of the readiness probe",
+		el: bldr.EventListener("name", "namespace",
+			bldr.EventListenerSpec(
+				bldr.EventListenerTrigger("tt", "v1alpha1",
+					bldr.EventListenerTriggerPath("/ready"),
+				),
+			)),
+	}, {
+		name: "trigger path of the batch endpoint",
+		el: bldr.EventListener("name", "namespace",
+			bldr.EventListenerSpec(
+				bldr.EventListenerTrigger("tt", "v1alpha1",
+					bldr.EventListenerTriggerPath("/batch/github"),
+				),
+			)),
+	}, {
+		name: "invalid trigger method",
+		el: bldr.EventListener("name", "namespace",
+			bldr.EventListenerSpec(
+				bldr.EventListenerTrigger("tt", "v1alpha1",
+					bldr.EventListenerTriggerMethod("post"),
+				),
+			)),
+	}, {
+		name: "relative CloudEvent URI",
+		el: bldr.EventListener("name", "namespace",
+			bldr.EventListenerSpec(
+				bldr.EventListenerCloudEventURI("/default/default"),
+				bldr.EventListenerTrigger("tt", "v1alpha1"),
+			)),
 	}, {
 		name: "user specify multiple containers",
 		el: bldr.EventListener("name", "namespace",
@@ -2,8 +2,10 @@ package v1alpha1
 
 import (
 	"context"
+	"encoding/json"
 	"strings"
 
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
@@ -25,7 +27,7 @@ type InterceptorRequest struct {
 	InterceptorParams map[string]interface{} `json:"interceptor_params,omitempty"`
 
 	// Context contains additional metadata about the event being processed
-	Context *TriggerContext
+	Context *TriggerContext `json:"context"`
 }
 
 type TriggerContext struct {
@@ -35,6 +37,8 @@ type TriggerContext struct {
 	EventID string `json:"event_id,omitempty"`
 	// TriggerID is of the form namespace/$ns/triggers/$name
 	TriggerID string `json:"trigger_id,omitempty"`
+	// SourceIP is the address of the client that sent the event
+	SourceIP string `json:"source_ip,omitempty"`
 }
 
 // Do not generate Deepcopy(). See #827
@@ -49,6 +53,53 @@ type InterceptorResponse struct {
 	Status *status.Status `json:"status,omitempty"`
 }
 
+// interceptorResponseJSON is the JSON encoding of an InterceptorResponse,
+// whose Status is otherwise opaque.
+type interceptorResponseJSON struct {
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
+	Continue   bool                   `json:"continue,omitempty"`
+	Status     *statusJSON            `json:"status,omitempty"`
+}
+
+// statusJSON is the JSON encoding of a Status. The code is encoded as a
+// number, but also decoded from its name, such as "FAILED_PRECONDITION".
+type statusJSON struct {
+	Code    codes.Code `json:"code"`
+	Message string     `json:"message,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler, for interceptors that respond over
+// HTTP.
+func (r InterceptorResponse) MarshalJSON() ([]byte, error) {
+	out := interceptorResponseJSON{
+		Extensions: r.Extensions,
+		Continue:   r.Continue,
+	}
+	if r.Status != nil {
+		out.Status = &statusJSON{
+			Code:    r.Status.Code(),
+			Message: r.Status.Message(),
+		}
+	}
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, for interceptors that respond
+// over HTTP.
+func (r *InterceptorResponse) UnmarshalJSON(b []byte) error {
+	var in interceptorResponseJSON
+	if err := json.Unmarshal(b, &in); err != nil {
+		return err
+	}
+	r.Extensions = in.Extensions
+	r.Continue = in.Continue
+	r.Status = nil
+	if in.Status != nil {
+		r.Status = status.New(in.Status.Code, in.Status.Message)
+	}
+	return nil
+}
+
 func ParseTriggerID(triggerID string) (namespace, name string) {
 	splits := strings.Split(triggerID, "/")
 	if len(splits) != 4 {
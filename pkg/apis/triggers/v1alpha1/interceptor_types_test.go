@@ -1,10 +1,13 @@
 package v1alpha1_test
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/tektoncd/triggers/pkg/apis/triggers/v1alpha1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func TestParseTriggerID(t *testing.T) {
@@ -26,3 +29,55 @@ func TestParseTriggerID(t *testing.T) {
 		})
 	}
 }
+
+func TestInterceptorResponse_JSON(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		in   v1alpha1.InterceptorResponse
+		want string
+	}{{
+		name: "continue",
+		in: v1alpha1.InterceptorResponse{
+			Continue:   true,
+			Extensions: map[string]interface{}{"foo": "bar"},
+		},
+		want: `{"extensions":{"foo":"bar"},"continue":true}`,
+	}, {
+		name: "status",
+		in: v1alpha1.InterceptorResponse{
+			Status: status.New(codes.FailedPrecondition, "event type not allowed"),
+		},
+		want: `{"status":{"code":9,"message":"event type not allowed"}}`,
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			b, err := json.Marshal(tc.in)
+			if err != nil {
+				t.Fatalf("json.Marshal() unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, string(b)); diff != "" {
+				t.Errorf("json.Marshal() (-want/+got): %s", diff)
+			}
+
+			var got v1alpha1.InterceptorResponse
+			if err := json.Unmarshal(b, &got); err != nil {
+				t.Fatalf("json.Unmarshal() unexpected error: %v", err)
+			}
+			if got.Continue != tc.in.Continue || got.Status.Code() != tc.in.Status.Code() || got.Status.Message() != tc.in.Status.Message() {
+				t.Errorf("json.Unmarshal() = %+v, want %+v", got, tc.in)
+			}
+			if diff := cmp.Diff(tc.in.Extensions, got.Extensions); diff != "" {
+				t.Errorf("json.Unmarshal() extensions (-want/+got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestInterceptorResponse_UnmarshalCodeName(t *testing.T) {
+	var got v1alpha1.InterceptorResponse
+	if err := json.Unmarshal([]byte(`{"status": {"code": "PERMISSION_DENIED", "message": "denied"}}`), &got); err != nil {
+		t.Fatalf("json.Unmarshal() unexpected error: %v", err)
+	}
+	if got.Continue || got.Status.Code() != codes.PermissionDenied || got.Status.Message() != "denied" {
+		t.Errorf("json.Unmarshal() = %+v, want a PermissionDenied status", got)
+	}
+}
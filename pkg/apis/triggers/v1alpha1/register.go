@@ -34,6 +34,14 @@ const (
 
 	// TriggerLabelKey is used as the label identifier for a Trigger
 	TriggerLabelKey = "/trigger"
+
+	// TraceIDAnnotationKey is used as the annotation identifier for the trace
+	// of an EventListener event.
+	TraceIDAnnotationKey = "/trace-id"
+
+	// FailedEventLabelKey is used as the label identifier for the ConfigMaps
+	// holding the events that failed to create resources.
+	FailedEventLabelKey = "/failed-event"
 )
 
 // SchemeGroupVersion is group version used to register these objects
@@ -59,6 +67,8 @@ var (
 // Adds the list of known types to Scheme.
 func addKnownTypes(scheme *runtime.Scheme) error {
 	scheme.AddKnownTypes(SchemeGroupVersion,
+		&ClusterInterceptor{},
+		&ClusterInterceptorList{},
 		&ClusterTriggerBinding{},
 		&ClusterTriggerBindingList{},
 		&EventListener{},
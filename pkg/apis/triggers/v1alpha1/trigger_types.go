@@ -20,6 +20,8 @@ import (
 	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"knative.dev/pkg/apis"
 )
 
 // TriggerSpec represents a connection between TriggerSpecBinding,
@@ -36,6 +38,19 @@ type TriggerSpec struct {
 	// as the Trigger itself
 	// +optional
 	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+	// RateLimit limits the rate and concurrency of the events the Trigger
+	// creates resources for. It applies once the Trigger's interceptors let
+	// the event continue.
+	// +optional
+	RateLimit *RateLimit `json:"rateLimit,omitempty"`
+	// Retry configures how creating the Trigger's resources is retried when
+	// it fails with a transient error.
+	// +optional
+	Retry *RetryPolicy `json:"retry,omitempty"`
+	// DryRun creates the Trigger's resources with a server-side dry run, so
+	// that they are validated without being persisted.
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
 }
 
 type TriggerSpecTemplate struct {
@@ -93,18 +108,135 @@ type TriggerInterceptor struct {
 	GitLab    *GitLabInterceptor    `json:"gitlab,omitempty"`
 	CEL       *CELInterceptor       `json:"cel,omitempty"`
 	Bitbucket *BitbucketInterceptor `json:"bitbucket,omitempty"`
+	// Ref refers to an interceptor that runs out of process, such as a
+	// ClusterInterceptor
+	Ref *InterceptorRef `json:"ref,omitempty"`
+	// Params are the params sent to the interceptor that Ref refers to
+	Params []InterceptorParams `json:"params,omitempty"`
+}
+
+// InterceptorKind is the kind of an interceptor that a Trigger refers to.
+type InterceptorKind string
+
+const (
+	// ClusterInterceptorKind indicates that the interceptor is a
+	// ClusterInterceptor.
+	ClusterInterceptorKind InterceptorKind = "ClusterInterceptor"
+)
+
+// InterceptorRef refers to an interceptor by name.
+type InterceptorRef struct {
+	// Name is the name of the interceptor
+	Name string `json:"name"`
+	// Kind is the kind of the interceptor, ClusterInterceptor by default
+	// +optional
+	Kind InterceptorKind `json:"kind,omitempty"`
+}
+
+// InterceptorParams is a param sent to an interceptor, whose value may be
+// any JSON value.
+type InterceptorParams struct {
+	Name  string               `json:"name"`
+	Value runtime.RawExtension `json:"value"`
 }
 
 // WebhookInterceptor provides a webhook to intercept and pre-process events
 type WebhookInterceptor struct {
 	// ObjectRef is a reference to an object that will resolve to a cluster DNS
-	// name to use as the EventInterceptor. Either objectRef or url can be specified
+	// name to use as the EventInterceptor. Exactly one of objectRef, url or
+	// service can be specified
 	// +optional
 	ObjectRef *corev1.ObjectReference `json:"objectRef,omitempty"`
+	// URL is the address of the interceptor
+	// +optional
+	URL *apis.URL `json:"url,omitempty"`
+	// Service is a reference to the Service of the interceptor, in the
+	// namespace of the Trigger unless its namespace is set
+	// +optional
+	Service *ServiceReference `json:"service,omitempty"`
 	// Header is a group of key-value pairs that can be appended to the
 	// interceptor request headers. This allows the interceptor to make
 	// decisions specific to an EventListenerTrigger.
 	Header []v1beta1.Param `json:"header,omitempty"`
+	// Timeout is the timeout of the requests to the interceptor. Defaults to
+	// 5 seconds.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+	// TLS configures the HTTPS connections to the interceptor. The Service
+	// or object of the interceptor is reached over HTTPS when it is set.
+	// +optional
+	TLS *WebhookTLS `json:"tls,omitempty"`
+	// Retry configures how the requests to the interceptor are retried when
+	// it is unavailable. Requests are not retried when it is not set, so it
+	// should only be set for idempotent interceptors.
+	// +optional
+	Retry *WebhookRetryPolicy `json:"retry,omitempty"`
+	// CircuitBreaker configures when the EventListener stops sending events
+	// to the interceptor after consecutive failures.
+	// +optional
+	CircuitBreaker *WebhookCircuitBreaker `json:"circuitBreaker,omitempty"`
+	// FailurePolicy defines whether the Trigger continues when the
+	// interceptor is unavailable. Defaults to Fail.
+	// +optional
+	FailurePolicy WebhookFailurePolicy `json:"failurePolicy,omitempty"`
+}
+
+// WebhookRetryPolicy configures how the requests to a WebhookInterceptor are
+// retried with an exponential backoff when it responds with a 5xx status
+// code, times out or cannot be reached.
+type WebhookRetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts at sending each event.
+	// Defaults to 3, and 1 disables retries.
+	// +optional
+	MaxAttempts int32 `json:"maxAttempts,omitempty"`
+	// Backoff is the delay before the first retry, which doubles before
+	// each next one. Defaults to 100 milliseconds.
+	// +optional
+	Backoff *metav1.Duration `json:"backoff,omitempty"`
+}
+
+// WebhookCircuitBreaker configures the circuit breaker of the endpoint of a
+// WebhookInterceptor. After FailureThreshold consecutive failures, events are
+// not sent to the endpoint for OpenDuration, after which a single event is
+// sent to find out whether it recovered.
+type WebhookCircuitBreaker struct {
+	// FailureThreshold is the number of consecutive failures opening the
+	// circuit breaker. Defaults to 5.
+	// +optional
+	FailureThreshold int32 `json:"failureThreshold,omitempty"`
+	// OpenDuration is how long the circuit breaker stays open. Defaults to 30
+	// seconds.
+	// +optional
+	OpenDuration *metav1.Duration `json:"openDuration,omitempty"`
+}
+
+// WebhookFailurePolicy defines whether a Trigger continues when its
+// WebhookInterceptor is unavailable.
+type WebhookFailurePolicy string
+
+const (
+	// WebhookFailurePolicyFail fails the Trigger when the interceptor is
+	// unavailable, also known as failing closed.
+	WebhookFailurePolicyFail WebhookFailurePolicy = "Fail"
+	// WebhookFailurePolicyIgnore lets the Trigger continue with the event
+	// unchanged when the interceptor is unavailable, also known as failing
+	// open.
+	WebhookFailurePolicyIgnore WebhookFailurePolicy = "Ignore"
+)
+
+// WebhookTLS holds the certificates of the HTTPS connections to a
+// WebhookInterceptor.
+type WebhookTLS struct {
+	// CASecretName is the name of a Secret in the Trigger's namespace holding
+	// a bundle of CA certificates (ca.crt) verifying the certificate of the
+	// interceptor. The system's CA certificates are used when it is empty.
+	// +optional
+	CASecretName string `json:"caSecretName,omitempty"`
+	// ClientCertSecretName is the name of a Secret of type kubernetes.io/tls
+	// in the Trigger's namespace holding the client certificate (tls.crt)
+	// and private key (tls.key) sent to the interceptor.
+	// +optional
+	ClientCertSecretName string `json:"clientCertSecretName,omitempty"`
 }
 
 // BitbucketInterceptor provides a webhook to intercept and pre-process events
@@ -18,6 +18,8 @@ package v1alpha1
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 
@@ -44,6 +46,13 @@ func (t *TriggerSpec) validate(ctx context.Context) *apis.FieldError {
 		errs = errs.Also(interceptor.validate(ctx).ViaField(fmt.Sprintf("interceptors[%d]", i)))
 	}
 
+	if t.RateLimit != nil {
+		errs = errs.Also(t.RateLimit.validate(ctx).ViaField("rateLimit"))
+	}
+	if t.Retry != nil {
+		errs = errs.Also(t.Retry.validate(ctx).ViaField("retry"))
+	}
+
 	return errs
 }
 
@@ -104,7 +113,7 @@ func (t triggerSpecBindingArray) validate(ctx context.Context) (errs *apis.Field
 }
 
 func (i *TriggerInterceptor) validate(ctx context.Context) (errs *apis.FieldError) {
-	if i.Webhook == nil && i.GitHub == nil && i.GitLab == nil && i.CEL == nil && i.Bitbucket == nil {
+	if i.Webhook == nil && i.GitHub == nil && i.GitLab == nil && i.CEL == nil && i.Bitbucket == nil && i.Ref == nil {
 		errs = errs.Also(apis.ErrMissingField("interceptor"))
 	}
 
@@ -122,41 +131,42 @@ func (i *TriggerInterceptor) validate(ctx context.Context) (errs *apis.FieldErro
 	if i.Bitbucket != nil {
 		numSet++
 	}
+	if i.Ref != nil {
+		numSet++
+	}
 
 	if numSet > 1 {
-		errs = errs.Also(apis.ErrMultipleOneOf("interceptor.webhook", "interceptor.github", "interceptor.gitlab"))
+		errs = errs.Also(apis.ErrMultipleOneOf("interceptor.webhook", "interceptor.github", "interceptor.gitlab", "interceptor.bitbucket", "interceptor.ref"))
 	}
 
-	if i.Webhook != nil {
-		if i.Webhook.ObjectRef == nil || i.Webhook.ObjectRef.Name == "" {
-			errs = errs.Also(apis.ErrMissingField("interceptor.webhook.objectRef"))
+	if i.Ref != nil {
+		if i.Ref.Name == "" {
+			errs = errs.Also(apis.ErrMissingField("interceptor.ref.name"))
 		}
-		w := i.Webhook
-		if w.ObjectRef.Kind != "Service" {
-			errs = errs.Also(apis.ErrInvalidValue(fmt.Errorf("invalid kind"), "interceptor.webhook.objectRef.kind"))
+		if i.Ref.Kind != "" && i.Ref.Kind != ClusterInterceptorKind {
+			errs = errs.Also(apis.ErrInvalidValue(fmt.Errorf("invalid kind"), "interceptor.ref.kind"))
 		}
-
-		// Optional explicit match
-		if w.ObjectRef.APIVersion != "v1" {
-			errs = errs.Also(apis.ErrInvalidValue(fmt.Errorf("invalid apiVersion"), "interceptor.webhook.objectRef.apiVersion"))
+	}
+	if len(i.Params) != 0 && i.Ref == nil {
+		errs = errs.Also(apis.ErrDisallowedFields("interceptor.params"))
+	}
+	names := map[string]bool{}
+	for j, p := range i.Params {
+		if p.Name == "" {
+			errs = errs.Also(apis.ErrMissingField(fmt.Sprintf("interceptor.params[%d].name", j)))
+		} else if names[p.Name] {
+			errs = errs.Also(apis.ErrInvalidValue(fmt.Errorf("duplicate param %s", p.Name), fmt.Sprintf("interceptor.params[%d].name", j)))
 		}
-
-		for i, header := range w.Header {
-			// Enforce non-empty canonical header keys
-			if len(header.Name) == 0 || http.CanonicalHeaderKey(header.Name) != header.Name {
-				errs = errs.Also(apis.ErrInvalidValue(fmt.Errorf("invalid header name"), fmt.Sprintf("interceptor.webhook.header[%d].name", i)))
-			}
-			// Enforce non-empty header values
-			if header.Value.Type == pipelinev1.ParamTypeString {
-				if len(header.Value.StringVal) == 0 {
-					errs = errs.Also(apis.ErrInvalidValue(fmt.Errorf("invalid header value"), fmt.Sprintf("interceptor.webhook.header[%d].value", i)))
-				}
-			} else if len(header.Value.ArrayVal) == 0 {
-				errs = errs.Also(apis.ErrInvalidValue(fmt.Errorf("invalid header value"), fmt.Sprintf("interceptor.webhook.header[%d].value", i)))
-			}
+		names[p.Name] = true
+		if len(p.Value.Raw) != 0 && !json.Valid(p.Value.Raw) {
+			errs = errs.Also(apis.ErrInvalidValue(fmt.Errorf("invalid JSON"), fmt.Sprintf("interceptor.params[%d].value", j)))
 		}
 	}
 
+	if i.Webhook != nil {
+		errs = errs.Also(i.Webhook.validate().ViaField("interceptor.webhook"))
+	}
+
 	// No github validation required yet.
 	// if i.GitHub != nil {
 	//
@@ -188,3 +198,88 @@ func (i *TriggerInterceptor) validate(ctx context.Context) (errs *apis.FieldErro
 	}
 	return errs
 }
+
+func (w *WebhookInterceptor) validate() (errs *apis.FieldError) {
+	numSet := 0
+	for _, set := range []bool{w.ObjectRef != nil, w.URL != nil, w.Service != nil} {
+		if set {
+			numSet++
+		}
+	}
+	switch {
+	case numSet == 0:
+		errs = errs.Also(apis.ErrMissingOneOf("objectRef", "url", "service"))
+	case numSet > 1:
+		errs = errs.Also(apis.ErrMultipleOneOf("objectRef", "url", "service"))
+	}
+
+	if w.ObjectRef != nil {
+		if w.ObjectRef.Name == "" {
+			errs = errs.Also(apis.ErrMissingField("objectRef.name"))
+		}
+		if w.ObjectRef.Kind != "Service" {
+			errs = errs.Also(apis.ErrInvalidValue(fmt.Errorf("invalid kind"), "objectRef.kind"))
+		}
+		// Optional explicit match
+		if w.ObjectRef.APIVersion != "v1" {
+			errs = errs.Also(apis.ErrInvalidValue(fmt.Errorf("invalid apiVersion"), "objectRef.apiVersion"))
+		}
+	}
+	if w.URL != nil {
+		if w.URL.Scheme == "" || w.URL.Host == "" {
+			errs = errs.Also(apis.ErrInvalidValue(errors.New("url must be absolute"), "url"))
+		} else if w.URL.Scheme != "http" && w.URL.Scheme != "https" {
+			errs = errs.Also(apis.ErrInvalidValue(fmt.Errorf("unsupported scheme %q", w.URL.Scheme), "url"))
+		} else if w.TLS != nil && w.URL.Scheme != "https" {
+			errs = errs.Also(apis.ErrInvalidValue(errors.New("url must use https with tls"), "url"))
+		}
+	}
+	if w.Service != nil {
+		if w.Service.Name == "" {
+			errs = errs.Also(apis.ErrMissingField("service.name"))
+		}
+		if p := w.Service.Port; p != nil && (*p < 1 || *p > 65535) {
+			errs = errs.Also(apis.ErrOutOfBoundsValue(*p, 1, 65535, "service.port"))
+		}
+	}
+	if w.Timeout != nil && w.Timeout.Duration <= 0 {
+		errs = errs.Also(apis.ErrInvalidValue(fmt.Errorf("timeout must be positive"), "timeout"))
+	}
+	if w.Retry != nil {
+		if w.Retry.MaxAttempts < 0 {
+			errs = errs.Also(apis.ErrInvalidValue(w.Retry.MaxAttempts, "retry.maxAttempts"))
+		}
+		if w.Retry.Backoff != nil && w.Retry.Backoff.Duration <= 0 {
+			errs = errs.Also(apis.ErrInvalidValue(w.Retry.Backoff.Duration.String(), "retry.backoff"))
+		}
+	}
+	if w.CircuitBreaker != nil {
+		if w.CircuitBreaker.FailureThreshold < 0 {
+			errs = errs.Also(apis.ErrInvalidValue(w.CircuitBreaker.FailureThreshold, "circuitBreaker.failureThreshold"))
+		}
+		if d := w.CircuitBreaker.OpenDuration; d != nil && d.Duration <= 0 {
+			errs = errs.Also(apis.ErrInvalidValue(d.Duration.String(), "circuitBreaker.openDuration"))
+		}
+	}
+	switch w.FailurePolicy {
+	case "", WebhookFailurePolicyFail, WebhookFailurePolicyIgnore:
+	default:
+		errs = errs.Also(apis.ErrInvalidValue(w.FailurePolicy, "failurePolicy"))
+	}
+
+	for i, header := range w.Header {
+		// Enforce non-empty canonical header keys
+		if len(header.Name) == 0 || http.CanonicalHeaderKey(header.Name) != header.Name {
+			errs = errs.Also(apis.ErrInvalidValue(fmt.Errorf("invalid header name"), fmt.Sprintf("header[%d].name", i)))
+		}
+		// Enforce non-empty header values
+		if header.Value.Type == pipelinev1.ParamTypeString {
+			if len(header.Value.StringVal) == 0 {
+				errs = errs.Also(apis.ErrInvalidValue(fmt.Errorf("invalid header value"), fmt.Sprintf("header[%d].value", i)))
+			}
+		} else if len(header.Value.ArrayVal) == 0 {
+			errs = errs.Also(apis.ErrInvalidValue(fmt.Errorf("invalid header value"), fmt.Sprintf("header[%d].value", i)))
+		}
+	}
+	return errs
+}
@@ -19,6 +19,7 @@ package v1alpha1_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	pipelinev1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 	"github.com/tektoncd/triggers/pkg/apis/triggers/v1alpha1"
@@ -26,6 +27,7 @@ import (
 	bldr "github.com/tektoncd/triggers/test/builder"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
 
 	"knative.dev/pkg/ptr"
 )
@@ -121,6 +123,16 @@ func Test_TriggerValidate(t *testing.T) {
 				bldr.TriggerSpecBinding("tb", "", "", "v1alpha1"),
 				bldr.TriggerSpecCELInterceptor("body.value == 'test'"),
 			)),
+	}, {
+		name: "Valid Trigger with ClusterInterceptor",
+		tr: bldr.Trigger("name", "namespace",
+			bldr.TriggerSpec(
+				bldr.TriggerSpecTemplate("tt", "v1alpha1"),
+				bldr.TriggerSpecBinding("tb", "", "", "v1alpha1"),
+				bldr.TriggerSpecInterceptorRef("scanner",
+					bldr.TriggerSpecInterceptorRefParam("severities", `["high", "critical"]`),
+					bldr.TriggerSpecInterceptorRefParam("strict", `true`)),
+			)),
 	}, {
 		name: "Valid Trigger with no trigger name",
 		tr: bldr.Trigger("name", "namespace",
@@ -179,6 +191,54 @@ func Test_TriggerValidate(t *testing.T) {
 				},
 			},
 		},
+	}, {
+		name: "Valid Trigger with webhook interceptor URL, timeout and TLS",
+		tr: bldr.Trigger("name", "namespace",
+			bldr.TriggerSpec(
+				bldr.TriggerSpecTemplate("tt", "v1alpha1"),
+				bldr.TriggerSpecBinding("tb", "", "", "v1alpha1"),
+				bldr.TriggerSpecInterceptor("", "", "", "", func(i *v1alpha1.TriggerInterceptor) {
+					i.Webhook = &v1alpha1.WebhookInterceptor{
+						URL:     apis.HTTPS("scanner.example.com"),
+						Timeout: &metav1.Duration{Duration: 30 * time.Second},
+						TLS: &v1alpha1.WebhookTLS{
+							CASecretName:         "scanner-ca",
+							ClientCertSecretName: "scanner-client",
+						},
+					}
+				}),
+			)),
+	}, {
+		name: "Valid Trigger with webhook interceptor Service",
+		tr: bldr.Trigger("name", "namespace",
+			bldr.TriggerSpec(
+				bldr.TriggerSpecTemplate("tt", "v1alpha1"),
+				bldr.TriggerSpecBinding("tb", "", "", "v1alpha1"),
+				bldr.TriggerSpecInterceptor("", "", "", "", func(i *v1alpha1.TriggerInterceptor) {
+					i.Webhook = &v1alpha1.WebhookInterceptor{
+						Service: &v1alpha1.ServiceReference{Name: "scanner", Port: ptr.Int32(8443), Path: "/scan"},
+						TLS:     &v1alpha1.WebhookTLS{},
+					}
+				}),
+			)),
+	}, {
+		name: "Valid Trigger with webhook interceptor retries, circuit breaker and failure policy",
+		tr: bldr.Trigger("name", "namespace",
+			bldr.TriggerSpec(
+				bldr.TriggerSpecTemplate("tt", "v1alpha1"),
+				bldr.TriggerSpecBinding("tb", "", "", "v1alpha1"),
+				bldr.TriggerSpecInterceptor("", "", "", "", func(i *v1alpha1.TriggerInterceptor) {
+					i.Webhook = &v1alpha1.WebhookInterceptor{
+						URL:   apis.HTTP("scanner.example.com"),
+						Retry: &v1alpha1.WebhookRetryPolicy{MaxAttempts: 5, Backoff: &metav1.Duration{Duration: time.Second}},
+						CircuitBreaker: &v1alpha1.WebhookCircuitBreaker{
+							FailureThreshold: 10,
+							OpenDuration:     &metav1.Duration{Duration: time.Minute},
+						},
+						FailurePolicy: v1alpha1.WebhookFailurePolicyIgnore,
+					}
+				}),
+			)),
 	}, {
 		name: "Trigger referenced with deprecated name field", // TODO(#FIXME): Remove when Name is removed.
 		tr: &v1alpha1.Trigger{
@@ -428,6 +488,209 @@ func TestTriggerValidate_error(t *testing.T) {
 				}},
 			},
 		},
+	}, {
+		name: "Interceptor ref without a name",
+		tr: bldr.Trigger("name", "namespace",
+			bldr.TriggerSpec(
+				bldr.TriggerSpecTemplate("tt", "v1alpha1"),
+				bldr.TriggerSpecBinding("tb", "", "", "v1alpha1"),
+				bldr.TriggerSpecInterceptorRef(""),
+			)),
+	}, {
+		name: "Interceptor ref with the wrong kind",
+		tr: bldr.Trigger("name", "namespace",
+			bldr.TriggerSpec(
+				bldr.TriggerSpecTemplate("tt", "v1alpha1"),
+				bldr.TriggerSpecBinding("tb", "", "", "v1alpha1"),
+				bldr.TriggerSpecInterceptorRef("scanner", func(i *v1alpha1.TriggerInterceptor) {
+					i.Ref.Kind = "Interceptor"
+				}),
+			)),
+	}, {
+		name: "Interceptor ref with duplicate params",
+		tr: bldr.Trigger("name", "namespace",
+			bldr.TriggerSpec(
+				bldr.TriggerSpecTemplate("tt", "v1alpha1"),
+				bldr.TriggerSpecBinding("tb", "", "", "v1alpha1"),
+				bldr.TriggerSpecInterceptorRef("scanner",
+					bldr.TriggerSpecInterceptorRefParam("strict", `true`),
+					bldr.TriggerSpecInterceptorRefParam("strict", `false`)),
+			)),
+	}, {
+		name: "Interceptor ref with invalid param value",
+		tr: bldr.Trigger("name", "namespace",
+			bldr.TriggerSpec(
+				bldr.TriggerSpecTemplate("tt", "v1alpha1"),
+				bldr.TriggerSpecBinding("tb", "", "", "v1alpha1"),
+				bldr.TriggerSpecInterceptorRef("scanner",
+					bldr.TriggerSpecInterceptorRefParam("strict", `{`)),
+			)),
+	}, {
+		name: "Params without an interceptor ref",
+		tr: bldr.Trigger("name", "namespace",
+			bldr.TriggerSpec(
+				bldr.TriggerSpecTemplate("tt", "v1alpha1"),
+				bldr.TriggerSpecBinding("tb", "", "", "v1alpha1"),
+				bldr.TriggerSpecCELInterceptor("body.value == 'test'",
+					bldr.TriggerSpecInterceptorRefParam("strict", `true`)),
+			)),
+	}, {
+		name: "Interceptor ref and webhook set",
+		tr: bldr.Trigger("name", "namespace",
+			bldr.TriggerSpec(
+				bldr.TriggerSpecTemplate("tt", "v1alpha1"),
+				bldr.TriggerSpecBinding("tb", "", "", "v1alpha1"),
+				bldr.TriggerSpecInterceptor("svc", "v1", "Service", "namespace", func(i *v1alpha1.TriggerInterceptor) {
+					i.Ref = &v1alpha1.InterceptorRef{Name: "scanner"}
+				}),
+			)),
+	}, {
+		name: "Webhook interceptor with no objectRef, url or service",
+		tr: bldr.Trigger("name", "namespace",
+			bldr.TriggerSpec(
+				bldr.TriggerSpecTemplate("tt", "v1alpha1"),
+				bldr.TriggerSpecBinding("tb", "", "", "v1alpha1"),
+				bldr.TriggerSpecInterceptor("", "", "", "", func(i *v1alpha1.TriggerInterceptor) {
+					i.Webhook = &v1alpha1.WebhookInterceptor{
+						Timeout: &metav1.Duration{Duration: time.Second},
+					}
+				}),
+			)),
+	}, {
+		name: "Webhook interceptor with url and service",
+		tr: bldr.Trigger("name", "namespace",
+			bldr.TriggerSpec(
+				bldr.TriggerSpecTemplate("tt", "v1alpha1"),
+				bldr.TriggerSpecBinding("tb", "", "", "v1alpha1"),
+				bldr.TriggerSpecInterceptor("", "", "", "", func(i *v1alpha1.TriggerInterceptor) {
+					i.Webhook = &v1alpha1.WebhookInterceptor{
+						URL:     apis.HTTP("scanner.example.com"),
+						Service: &v1alpha1.ServiceReference{Name: "scanner"},
+					}
+				}),
+			)),
+	}, {
+		name: "Webhook interceptor with relative url",
+		tr: bldr.Trigger("name", "namespace",
+			bldr.TriggerSpec(
+				bldr.TriggerSpecTemplate("tt", "v1alpha1"),
+				bldr.TriggerSpecBinding("tb", "", "", "v1alpha1"),
+				bldr.TriggerSpecInterceptor("", "", "", "", func(i *v1alpha1.TriggerInterceptor) {
+					i.Webhook = &v1alpha1.WebhookInterceptor{
+						URL: &apis.URL{Path: "/scan"},
+					}
+				}),
+			)),
+	}, {
+		name: "Webhook interceptor with http url and TLS",
+		tr: bldr.Trigger("name", "namespace",
+			bldr.TriggerSpec(
+				bldr.TriggerSpecTemplate("tt", "v1alpha1"),
+				bldr.TriggerSpecBinding("tb", "", "", "v1alpha1"),
+				bldr.TriggerSpecInterceptor("", "", "", "", func(i *v1alpha1.TriggerInterceptor) {
+					i.Webhook = &v1alpha1.WebhookInterceptor{
+						URL: apis.HTTP("scanner.example.com"),
+						TLS: &v1alpha1.WebhookTLS{CASecretName: "scanner-ca"},
+					}
+				}),
+			)),
+	}, {
+		name: "Webhook interceptor Service without name",
+		tr: bldr.Trigger("name", "namespace",
+			bldr.TriggerSpec(
+				bldr.TriggerSpecTemplate("tt", "v1alpha1"),
+				bldr.TriggerSpecBinding("tb", "", "", "v1alpha1"),
+				bldr.TriggerSpecInterceptor("", "", "", "", func(i *v1alpha1.TriggerInterceptor) {
+					i.Webhook = &v1alpha1.WebhookInterceptor{
+						Service: &v1alpha1.ServiceReference{Port: ptr.Int32(8080)},
+					}
+				}),
+			)),
+	}, {
+		name: "Webhook interceptor Service with invalid port",
+		tr: bldr.Trigger("name", "namespace",
+			bldr.TriggerSpec(
+				bldr.TriggerSpecTemplate("tt", "v1alpha1"),
+				bldr.TriggerSpecBinding("tb", "", "", "v1alpha1"),
+				bldr.TriggerSpecInterceptor("", "", "", "", func(i *v1alpha1.TriggerInterceptor) {
+					i.Webhook = &v1alpha1.WebhookInterceptor{
+						Service: &v1alpha1.ServiceReference{Name: "scanner", Port: ptr.Int32(70000)},
+					}
+				}),
+			)),
+	}, {
+		name: "Webhook interceptor with negative timeout",
+		tr: bldr.Trigger("name", "namespace",
+			bldr.TriggerSpec(
+				bldr.TriggerSpecTemplate("tt", "v1alpha1"),
+				bldr.TriggerSpecBinding("tb", "", "", "v1alpha1"),
+				bldr.TriggerSpecInterceptor("", "", "", "", func(i *v1alpha1.TriggerInterceptor) {
+					i.Webhook = &v1alpha1.WebhookInterceptor{
+						URL:     apis.HTTP("scanner.example.com"),
+						Timeout: &metav1.Duration{Duration: -time.Second},
+					}
+				}),
+			)),
+	}, {name: "Webhook interceptor with negative retry attempts",
+		tr: bldr.Trigger("name", "namespace",
+			bldr.TriggerSpec(
+				bldr.TriggerSpecTemplate("tt", "v1alpha1"),
+				bldr.TriggerSpecBinding("tb", "", "", "v1alpha1"),
+				bldr.TriggerSpecInterceptor("", "", "", "", func(i *v1alpha1.TriggerInterceptor) {
+					i.Webhook = &v1alpha1.WebhookInterceptor{
+						URL:   apis.HTTP("scanner.example.com"),
+						Retry: &v1alpha1.WebhookRetryPolicy{MaxAttempts: -1},
+					}
+				}),
+			)),
+	}, {name: "Webhook interceptor with zero retry backoff",
+		tr: bldr.Trigger("name", "namespace",
+			bldr.TriggerSpec(
+				bldr.TriggerSpecTemplate("tt", "v1alpha1"),
+				bldr.TriggerSpecBinding("tb", "", "", "v1alpha1"),
+				bldr.TriggerSpecInterceptor("", "", "", "", func(i *v1alpha1.TriggerInterceptor) {
+					i.Webhook = &v1alpha1.WebhookInterceptor{
+						URL:   apis.HTTP("scanner.example.com"),
+						Retry: &v1alpha1.WebhookRetryPolicy{Backoff: &metav1.Duration{}},
+					}
+				}),
+			)),
+	}, {name: "Webhook interceptor with negative circuit breaker threshold",
+		tr: bldr.Trigger("name", "namespace",
+			bldr.TriggerSpec(
+				bldr.TriggerSpecTemplate("tt", "v1alpha1"),
+				bldr.TriggerSpecBinding("tb", "", "", "v1alpha1"),
+				bldr.TriggerSpecInterceptor("", "", "", "", func(i *v1alpha1.TriggerInterceptor) {
+					i.Webhook = &v1alpha1.WebhookInterceptor{
+						URL:            apis.HTTP("scanner.example.com"),
+						CircuitBreaker: &v1alpha1.WebhookCircuitBreaker{FailureThreshold: -1},
+					}
+				}),
+			)),
+	}, {name: "Webhook interceptor with zero circuit breaker open duration",
+		tr: bldr.Trigger("name", "namespace",
+			bldr.TriggerSpec(
+				bldr.TriggerSpecTemplate("tt", "v1alpha1"),
+				bldr.TriggerSpecBinding("tb", "", "", "v1alpha1"),
+				bldr.TriggerSpecInterceptor("", "", "", "", func(i *v1alpha1.TriggerInterceptor) {
+					i.Webhook = &v1alpha1.WebhookInterceptor{
+						URL:            apis.HTTP("scanner.example.com"),
+						CircuitBreaker: &v1alpha1.WebhookCircuitBreaker{OpenDuration: &metav1.Duration{}},
+					}
+				}),
+			)),
+	}, {name: "Webhook interceptor with unknown failure policy",
+		tr: bldr.Trigger("name", "namespace",
+			bldr.TriggerSpec(
+				bldr.TriggerSpecTemplate("tt", "v1alpha1"),
+				bldr.TriggerSpecBinding("tb", "", "", "v1alpha1"),
+				bldr.TriggerSpecInterceptor("", "", "", "", func(i *v1alpha1.TriggerInterceptor) {
+					i.Webhook = &v1alpha1.WebhookInterceptor{
+						URL:           apis.HTTP("scanner.example.com"),
+						FailurePolicy: "Open",
+					}
+				}),
+			)),
 	}, {
 		name: "CEL interceptor with no filter or overlays",
 		tr: &v1alpha1.Trigger{
@@ -502,6 +765,24 @@ func TestTriggerValidate_error(t *testing.T) {
 				Template: v1alpha1.TriggerSpecTemplate{},
 			},
 		},
+	}, {
+		name: "Trigger with invalid rate limit",
+		tr: &v1alpha1.Trigger{
+			ObjectMeta: metav1.ObjectMeta{Name: "name"},
+			Spec: v1alpha1.TriggerSpec{
+				Template:  v1alpha1.TriggerSpecTemplate{Name: "tt"},
+				RateLimit: &v1alpha1.RateLimit{Burst: 5},
+			},
+		},
+	}, {
+		name: "Trigger with invalid retry policy",
+		tr: &v1alpha1.Trigger{
+			ObjectMeta: metav1.ObjectMeta{Name: "name"},
+			Spec: v1alpha1.TriggerSpec{
+				Template: v1alpha1.TriggerSpecTemplate{Name: "tt"},
+				Retry:    &v1alpha1.RetryPolicy{MaxAttempts: -1},
+			},
+		},
 	}, {
 		name: "Trigger template with invalid spec",
 		tr: &v1alpha1.Trigger{
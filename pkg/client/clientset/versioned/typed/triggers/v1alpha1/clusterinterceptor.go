@@ -0,0 +1,168 @@
+/*
+Copyright 2019 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"context"
+	"time"
+
+	v1alpha1 "github.com/tektoncd/triggers/pkg/apis/triggers/v1alpha1"
+	scheme "github.com/tektoncd/triggers/pkg/client/clientset/versioned/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// ClusterInterceptorsGetter has a method to return a ClusterInterceptorInterface.
+// A group's client should implement this interface.
+type ClusterInterceptorsGetter interface {
+	ClusterInterceptors() ClusterInterceptorInterface
+}
+
+// ClusterInterceptorInterface has methods to work with ClusterInterceptor resources.
+type ClusterInterceptorInterface interface {
+	Create(ctx context.Context, clusterInterceptor *v1alpha1.ClusterInterceptor, opts v1.CreateOptions) (*v1alpha1.ClusterInterceptor, error)
+	Update(ctx context.Context, clusterInterceptor *v1alpha1.ClusterInterceptor, opts v1.UpdateOptions) (*v1alpha1.ClusterInterceptor, error)
+	Delete(ctx context.Context, name string, opts v1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error
+	Get(ctx context.Context, name string, opts v1.GetOptions) (*v1alpha1.ClusterInterceptor, error)
+	List(ctx context.Context, opts v1.ListOptions) (*v1alpha1.ClusterInterceptorList, error)
+	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.ClusterInterceptor, err error)
+	ClusterInterceptorExpansion
+}
+
+// clusterInterceptors implements ClusterInterceptorInterface
+type clusterInterceptors struct {
+	client rest.Interface
+}
+
+// newClusterInterceptors returns a ClusterInterceptors
+func newClusterInterceptors(c *TriggersV1alpha1Client) *clusterInterceptors {
+	return &clusterInterceptors{
+		client: c.RESTClient(),
+	}
+}
+
+// Get takes name of the clusterInterceptor, and returns the corresponding clusterInterceptor object, and an error if there is any.
+func (c *clusterInterceptors) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.ClusterInterceptor, err error) {
+	result = &v1alpha1.ClusterInterceptor{}
+	err = c.client.Get().
+		Resource("clusterinterceptors").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of ClusterInterceptors that match those selectors.
+func (c *clusterInterceptors) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.ClusterInterceptorList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1alpha1.ClusterInterceptorList{}
+	err = c.client.Get().
+		Resource("clusterinterceptors").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested clusterInterceptors.
+func (c *clusterInterceptors) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Resource("clusterinterceptors").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a clusterInterceptor and creates it.  Returns the server's representation of the clusterInterceptor, and an error, if there is any.
+func (c *clusterInterceptors) Create(ctx context.Context, clusterInterceptor *v1alpha1.ClusterInterceptor, opts v1.CreateOptions) (result *v1alpha1.ClusterInterceptor, err error) {
+	result = &v1alpha1.ClusterInterceptor{}
+	err = c.client.Post().
+		Resource("clusterinterceptors").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(clusterInterceptor).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a clusterInterceptor and updates it. Returns the server's representation of the clusterInterceptor, and an error, if there is any.
+func (c *clusterInterceptors) Update(ctx context.Context, clusterInterceptor *v1alpha1.ClusterInterceptor, opts v1.UpdateOptions) (result *v1alpha1.ClusterInterceptor, err error) {
+	result = &v1alpha1.ClusterInterceptor{}
+	err = c.client.Put().
+		Resource("clusterinterceptors").
+		Name(clusterInterceptor.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(clusterInterceptor).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the clusterInterceptor and deletes it. Returns an error if one occurs.
+func (c *clusterInterceptors) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	return c.client.Delete().
+		Resource("clusterinterceptors").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *clusterInterceptors) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Resource("clusterinterceptors").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched clusterInterceptor.
+func (c *clusterInterceptors) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.ClusterInterceptor, err error) {
+	result = &v1alpha1.ClusterInterceptor{}
+	err = c.client.Patch(pt).
+		Resource("clusterinterceptors").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
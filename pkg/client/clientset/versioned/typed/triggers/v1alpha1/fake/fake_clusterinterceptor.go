@@ -0,0 +1,122 @@
+/*
+Copyright 2019 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	v1alpha1 "github.com/tektoncd/triggers/pkg/apis/triggers/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeClusterInterceptors implements ClusterInterceptorInterface
+type FakeClusterInterceptors struct {
+	Fake *FakeTriggersV1alpha1
+}
+
+var clusterinterceptorsResource = schema.GroupVersionResource{Group: "triggers.tekton.dev", Version: "v1alpha1", Resource: "clusterinterceptors"}
+
+var clusterinterceptorsKind = schema.GroupVersionKind{Group: "triggers.tekton.dev", Version: "v1alpha1", Kind: "ClusterInterceptor"}
+
+// Get takes name of the clusterInterceptor, and returns the corresponding clusterInterceptor object, and an error if there is any.
+func (c *FakeClusterInterceptors) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.ClusterInterceptor, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootGetAction(clusterinterceptorsResource, name), &v1alpha1.ClusterInterceptor{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.ClusterInterceptor), err
+}
+
+// List takes label and field selectors, and returns the list of ClusterInterceptors that match those selectors.
+func (c *FakeClusterInterceptors) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.ClusterInterceptorList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootListAction(clusterinterceptorsResource, clusterinterceptorsKind, opts), &v1alpha1.ClusterInterceptorList{})
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1alpha1.ClusterInterceptorList{ListMeta: obj.(*v1alpha1.ClusterInterceptorList).ListMeta}
+	for _, item := range obj.(*v1alpha1.ClusterInterceptorList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested clusterInterceptors.
+func (c *FakeClusterInterceptors) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewRootWatchAction(clusterinterceptorsResource, opts))
+}
+
+// Create takes the representation of a clusterInterceptor and creates it.  Returns the server's representation of the clusterInterceptor, and an error, if there is any.
+func (c *FakeClusterInterceptors) Create(ctx context.Context, clusterInterceptor *v1alpha1.ClusterInterceptor, opts v1.CreateOptions) (result *v1alpha1.ClusterInterceptor, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootCreateAction(clusterinterceptorsResource, clusterInterceptor), &v1alpha1.ClusterInterceptor{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.ClusterInterceptor), err
+}
+
+// Update takes the representation of a clusterInterceptor and updates it. Returns the server's representation of the clusterInterceptor, and an error, if there is any.
+func (c *FakeClusterInterceptors) Update(ctx context.Context, clusterInterceptor *v1alpha1.ClusterInterceptor, opts v1.UpdateOptions) (result *v1alpha1.ClusterInterceptor, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootUpdateAction(clusterinterceptorsResource, clusterInterceptor), &v1alpha1.ClusterInterceptor{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.ClusterInterceptor), err
+}
+
+// Delete takes name of the clusterInterceptor and deletes it. Returns an error if one occurs.
+func (c *FakeClusterInterceptors) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewRootDeleteAction(clusterinterceptorsResource, name), &v1alpha1.ClusterInterceptor{})
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeClusterInterceptors) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewRootDeleteCollectionAction(clusterinterceptorsResource, listOpts)
+
+	_, err := c.Fake.Invokes(action, &v1alpha1.ClusterInterceptorList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched clusterInterceptor.
+func (c *FakeClusterInterceptors) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.ClusterInterceptor, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootPatchSubresourceAction(clusterinterceptorsResource, name, pt, data, subresources...), &v1alpha1.ClusterInterceptor{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.ClusterInterceptor), err
+}
@@ -28,6 +28,10 @@ type FakeTriggersV1alpha1 struct {
 	*testing.Fake
 }
 
+func (c *FakeTriggersV1alpha1) ClusterInterceptors() v1alpha1.ClusterInterceptorInterface {
+	return &FakeClusterInterceptors{c}
+}
+
 func (c *FakeTriggersV1alpha1) ClusterTriggerBindings() v1alpha1.ClusterTriggerBindingInterface {
 	return &FakeClusterTriggerBindings{c}
 }
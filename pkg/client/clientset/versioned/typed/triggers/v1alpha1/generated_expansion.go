@@ -18,6 +18,8 @@ limitations under the License.
 
 package v1alpha1
 
+type ClusterInterceptorExpansion interface{}
+
 type ClusterTriggerBindingExpansion interface{}
 
 type EventListenerExpansion interface{}
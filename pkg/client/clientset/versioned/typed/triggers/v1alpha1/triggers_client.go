@@ -26,6 +26,7 @@ import (
 
 type TriggersV1alpha1Interface interface {
 	RESTClient() rest.Interface
+	ClusterInterceptorsGetter
 	ClusterTriggerBindingsGetter
 	EventListenersGetter
 	TriggersGetter
@@ -38,6 +39,10 @@ type TriggersV1alpha1Client struct {
 	restClient rest.Interface
 }
 
+func (c *TriggersV1alpha1Client) ClusterInterceptors() ClusterInterceptorInterface {
+	return newClusterInterceptors(c)
+}
+
 func (c *TriggersV1alpha1Client) ClusterTriggerBindings() ClusterTriggerBindingInterface {
 	return newClusterTriggerBindings(c)
 }
@@ -24,6 +24,8 @@ import (
 
 // Interface provides access to all the informers in this group version.
 type Interface interface {
+	// ClusterInterceptors returns a ClusterInterceptorInformer.
+	ClusterInterceptors() ClusterInterceptorInformer
 	// ClusterTriggerBindings returns a ClusterTriggerBindingInformer.
 	ClusterTriggerBindings() ClusterTriggerBindingInformer
 	// EventListeners returns a EventListenerInformer.
@@ -47,6 +49,11 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 	return &version{factory: f, namespace: namespace, tweakListOptions: tweakListOptions}
 }
 
+// ClusterInterceptors returns a ClusterInterceptorInformer.
+func (v *version) ClusterInterceptors() ClusterInterceptorInformer {
+	return &clusterInterceptorInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 // ClusterTriggerBindings returns a ClusterTriggerBindingInformer.
 func (v *version) ClusterTriggerBindings() ClusterTriggerBindingInformer {
 	return &clusterTriggerBindingInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
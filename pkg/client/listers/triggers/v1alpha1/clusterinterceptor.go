@@ -0,0 +1,65 @@
+/*
+Copyright 2019 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/tektoncd/triggers/pkg/apis/triggers/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// ClusterInterceptorLister helps list ClusterInterceptors.
+type ClusterInterceptorLister interface {
+	// List lists all ClusterInterceptors in the indexer.
+	List(selector labels.Selector) (ret []*v1alpha1.ClusterInterceptor, err error)
+	// Get retrieves the ClusterInterceptor from the index for a given name.
+	Get(name string) (*v1alpha1.ClusterInterceptor, error)
+	ClusterInterceptorListerExpansion
+}
+
+// clusterInterceptorLister implements the ClusterInterceptorLister interface.
+type clusterInterceptorLister struct {
+	indexer cache.Indexer
+}
+
+// NewClusterInterceptorLister returns a new ClusterInterceptorLister.
+func NewClusterInterceptorLister(indexer cache.Indexer) ClusterInterceptorLister {
+	return &clusterInterceptorLister{indexer: indexer}
+}
+
+// List lists all ClusterInterceptors in the indexer.
+func (s *clusterInterceptorLister) List(selector labels.Selector) (ret []*v1alpha1.ClusterInterceptor, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.ClusterInterceptor))
+	})
+	return ret, err
+}
+
+// Get retrieves the ClusterInterceptor from the index for a given name.
+func (s *clusterInterceptorLister) Get(name string) (*v1alpha1.ClusterInterceptor, error) {
+	obj, exists, err := s.indexer.GetByKey(name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha1.Resource("clusterinterceptor"), name)
+	}
+	return obj.(*v1alpha1.ClusterInterceptor), nil
+}
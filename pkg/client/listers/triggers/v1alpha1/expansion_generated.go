@@ -18,6 +18,10 @@ limitations under the License.
 
 package v1alpha1
 
+// ClusterInterceptorListerExpansion allows custom methods to be added to
+// ClusterInterceptorLister.
+type ClusterInterceptorListerExpansion interface{}
+
 // ClusterTriggerBindingListerExpansion allows custom methods to be added to
 // ClusterTriggerBindingLister.
 type ClusterTriggerBindingListerExpansion interface{}
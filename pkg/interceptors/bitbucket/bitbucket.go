@@ -17,78 +17,73 @@ limitations under the License.
 package bitbucket
 
 import (
-	"bytes"
-	"errors"
-	"fmt"
-	"io/ioutil"
+	"context"
 	"net/http"
 
 	gh "github.com/google/go-github/v31/github"
 	triggersv1 "github.com/tektoncd/triggers/pkg/apis/triggers/v1alpha1"
 	"github.com/tektoncd/triggers/pkg/interceptors"
 	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
 	"k8s.io/client-go/kubernetes"
 )
 
+var _ triggersv1.InterceptorInterface = (*Interceptor)(nil)
+
+// Interceptor validates the signature and event type of the webhooks sent
+// by Bitbucket Server.
 type Interceptor struct {
-	KubeClientSet          kubernetes.Interface
-	Logger                 *zap.SugaredLogger
-	Bitbucket              *triggersv1.BitbucketInterceptor
-	EventListenerNamespace string
+	KubeClientSet kubernetes.Interface
+	Logger        *zap.SugaredLogger
 }
 
-func NewInterceptor(bh *triggersv1.BitbucketInterceptor, k kubernetes.Interface, ns string, l *zap.SugaredLogger) interceptors.Interceptor {
+// NewInterceptor creates a prepopulated Interceptor.
+func NewInterceptor(k kubernetes.Interface, l *zap.SugaredLogger) *Interceptor {
 	return &Interceptor{
-		Logger:                 l,
-		Bitbucket:              bh,
-		KubeClientSet:          k,
-		EventListenerNamespace: ns,
+		Logger:        l,
+		KubeClientSet: k,
 	}
 }
 
-func (w *Interceptor) ExecuteTrigger(request *http.Request) (*http.Response, error) {
-	payload := []byte{}
-	var err error
-
-	if request.Body != nil {
-		defer request.Body.Close()
-		payload, err = ioutil.ReadAll(request.Body)
-		if err != nil {
-			return nil, fmt.Errorf("failed to read request body: %w", err)
-		}
+// Process validates the event against the BitbucketInterceptor of the
+// request's InterceptorParams. It adds the event type and request ID to the
+// extensions under the bitbucket key.
+func (w *Interceptor) Process(ctx context.Context, r *triggersv1.InterceptorRequest) *triggersv1.InterceptorResponse {
+	p := triggersv1.BitbucketInterceptor{}
+	if err := interceptors.UnmarshalParams(r.InterceptorParams, &p); err != nil {
+		return interceptors.Failf(codes.InvalidArgument, "failed to parse interceptor params: %v", err)
 	}
+	header := http.Header(r.Header)
 
 	// Validate secrets first before anything else, if set
-	if w.Bitbucket.SecretRef != nil {
-		header := request.Header.Get("X-Hub-Signature")
-		if header == "" {
-			return nil, errors.New("no X-Hub-Signature header set")
+	if p.SecretRef != nil {
+		signature := header.Get("X-Hub-Signature")
+		if signature == "" {
+			return interceptors.Fail(codes.Unauthenticated, "no X-Hub-Signature header set")
 		}
-		secretToken, err := interceptors.GetSecretToken(request, w.KubeClientSet, w.Bitbucket.SecretRef, w.EventListenerNamespace)
+		ns, _ := triggersv1.ParseTriggerID(r.Context.TriggerID)
+		secretToken, err := interceptors.GetSecretTokenFromContext(ctx, w.KubeClientSet, p.SecretRef, ns)
 		if err != nil {
-			return nil, err
+			return interceptors.Failf(codes.Internal, "error getting secret: %v", err)
 		}
-		if err := gh.ValidateSignature(header, payload, secretToken); err != nil {
-			return nil, err
+		if err := gh.ValidateSignature(signature, r.Body, secretToken); err != nil {
+			return interceptors.Failf(codes.Unauthenticated, "invalid X-Hub-Signature: %v", err)
 		}
 	}
 
 	// Next see if the event type is in the allow-list
-	if w.Bitbucket.EventTypes != nil {
-		actualEvent := request.Header.Get("X-Event-Key")
-		isAllowed := false
-		for _, allowedEvent := range w.Bitbucket.EventTypes {
-			if actualEvent == allowedEvent {
-				isAllowed = true
-				break
-			}
-		}
-		if !isAllowed {
-			return nil, fmt.Errorf("event type %s is not allowed", actualEvent)
-		}
+	eventType := header.Get("X-Event-Key")
+	if !interceptors.Allowed(eventType, p.EventTypes) {
+		return interceptors.Failf(codes.FailedPrecondition, "event type %s is not allowed", eventType)
+	}
+
+	return &triggersv1.InterceptorResponse{
+		Continue: true,
+		Extensions: map[string]interface{}{
+			"bitbucket": map[string]interface{}{
+				"event_type": eventType,
+				"request_id": header.Get("X-Request-Id"),
+			},
+		},
 	}
-	return &http.Response{
-		Header: request.Header,
-		Body:   ioutil.NopCloser(bytes.NewBuffer(payload)),
-	}, nil
 }
@@ -17,15 +17,14 @@ limitations under the License.
 package bitbucket
 
 import (
-	"bytes"
-	"io"
-	"io/ioutil"
 	"net/http"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 
 	triggersv1 "github.com/tektoncd/triggers/pkg/apis/triggers/v1alpha1"
+	"github.com/tektoncd/triggers/pkg/interceptors"
+	"google.golang.org/grpc/codes"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	fakekubeclient "knative.dev/pkg/client/injection/kube/client/fake"
@@ -33,9 +32,9 @@ import (
 	rtesting "knative.dev/pkg/reconciler/testing"
 )
 
-func TestInterceptor_ExecuteTrigger_Signature(t *testing.T) {
+func TestInterceptor_Process(t *testing.T) {
 	type args struct {
-		payload   io.ReadCloser
+		payload   []byte
 		secret    *corev1.Secret
 		signature string
 		eventType string
@@ -44,18 +43,16 @@ func TestInterceptor_ExecuteTrigger_Signature(t *testing.T) {
 		name      string
 		Bitbucket *triggersv1.BitbucketInterceptor
 		args      args
-		want      []byte
-		wantErr   bool
+		want      codes.Code
 	}{
 		{
 			name:      "no secret",
 			Bitbucket: &triggersv1.BitbucketInterceptor{},
 			args: args{
-				payload:   ioutil.NopCloser(bytes.NewBufferString("somepayload")),
+				payload:   []byte("somepayload"),
 				signature: "foo",
 			},
-			want:    []byte("somepayload"),
-			wantErr: false,
+			want: codes.OK,
 		},
 		{
 			name: "invalid header for secret",
@@ -75,9 +72,9 @@ func TestInterceptor_ExecuteTrigger_Signature(t *testing.T) {
 						"token": []byte("secrettoken"),
 					},
 				},
-				payload: ioutil.NopCloser(bytes.NewBufferString("somepayload")),
+				payload: []byte("somepayload"),
 			},
-			wantErr: true,
+			want: codes.Unauthenticated,
 		},
 		{
 			name: "valid header for secret",
@@ -99,10 +96,9 @@ func TestInterceptor_ExecuteTrigger_Signature(t *testing.T) {
 						"token": []byte("secret"),
 					},
 				},
-				payload: ioutil.NopCloser(bytes.NewBufferString("somepayload")),
+				payload: []byte("somepayload"),
 			},
-			wantErr: false,
-			want:    []byte("somepayload"),
+			want: codes.OK,
 		},
 		{
 			name: "matching event",
@@ -110,11 +106,10 @@ func TestInterceptor_ExecuteTrigger_Signature(t *testing.T) {
 				EventTypes: []string{"pr:opened", "repo:refs_changed"},
 			},
 			args: args{
-				payload:   ioutil.NopCloser(bytes.NewBufferString("somepayload")),
+				payload:   []byte("somepayload"),
 				eventType: "repo:refs_changed",
 			},
-			wantErr: false,
-			want:    []byte("somepayload"),
+			want: codes.OK,
 		},
 		{
 			name: "no matching event",
@@ -122,10 +117,10 @@ func TestInterceptor_ExecuteTrigger_Signature(t *testing.T) {
 				EventTypes: []string{"pr:opened", "repo:refs_changed"},
 			},
 			args: args{
-				payload:   ioutil.NopCloser(bytes.NewBufferString("somepayload")),
+				payload:   []byte("somepayload"),
 				eventType: "event",
 			},
-			wantErr: true,
+			want: codes.FailedPrecondition,
 		},
 		{
 			name: "valid header for secret and matching event",
@@ -149,10 +144,9 @@ func TestInterceptor_ExecuteTrigger_Signature(t *testing.T) {
 					},
 				},
 				eventType: "repo:refs_changed",
-				payload:   ioutil.NopCloser(bytes.NewBufferString("somepayload")),
+				payload:   []byte("somepayload"),
 			},
-			wantErr: false,
-			want:    []byte("somepayload"),
+			want: codes.OK,
 		},
 		{
 			name: "valid header for secret, but no matching event",
@@ -176,9 +170,9 @@ func TestInterceptor_ExecuteTrigger_Signature(t *testing.T) {
 					},
 				},
 				eventType: "event",
-				payload:   ioutil.NopCloser(bytes.NewBufferString("somepayload")),
+				payload:   []byte("somepayload"),
 			},
-			wantErr: true,
+			want: codes.FailedPrecondition,
 		},
 		{
 			name: "invalid header for secret, but matching event",
@@ -200,9 +194,9 @@ func TestInterceptor_ExecuteTrigger_Signature(t *testing.T) {
 					},
 				},
 				eventType: "pr:opened",
-				payload:   ioutil.NopCloser(bytes.NewBufferString("somepayload")),
+				payload:   []byte("somepayload"),
 			},
-			wantErr: true,
+			want: codes.Unauthenticated,
 		}, {
 			name:      "nil body does not panic",
 			Bitbucket: &triggersv1.BitbucketInterceptor{},
@@ -210,8 +204,7 @@ func TestInterceptor_ExecuteTrigger_Signature(t *testing.T) {
 				payload:   nil,
 				signature: "foo",
 			},
-			want:    []byte{},
-			wantErr: false,
+			want: codes.OK,
 		},
 	}
 	for _, tt := range tests {
@@ -219,44 +212,68 @@ func TestInterceptor_ExecuteTrigger_Signature(t *testing.T) {
 			ctx, _ := rtesting.SetupFakeContext(t)
 			logger, _ := logging.NewLogger("", "")
 			kubeClient := fakekubeclient.Get(ctx)
-			request := &http.Request{
-				Body: tt.args.payload,
-				Header: http.Header{
-					"Content-Type": []string{"application/json"},
-				},
+			header := http.Header{
+				"Content-Type": []string{"application/json"},
 			}
 			if tt.args.eventType != "" {
-				request.Header.Add("X-Event-Key", tt.args.eventType)
+				header.Add("X-Event-Key", tt.args.eventType)
 			}
 			if tt.args.signature != "" {
-				request.Header.Add("X-Hub-Signature", tt.args.signature)
+				header.Add("X-Hub-Signature", tt.args.signature)
 			}
 			if tt.args.secret != nil {
 				if _, err := kubeClient.CoreV1().Secrets(metav1.NamespaceDefault).Create(ctx, tt.args.secret, metav1.CreateOptions{}); err != nil {
 					t.Error(err)
 				}
 			}
-			w := &Interceptor{
-				KubeClientSet:          kubeClient,
-				Bitbucket:              tt.Bitbucket,
-				Logger:                 logger,
-				EventListenerNamespace: metav1.NamespaceDefault,
-			}
-			resp, err := w.ExecuteTrigger(request)
-			if err != nil {
-				if !tt.wantErr {
-					t.Errorf("Interceptor.ExecuteTrigger() error = %v, wantErr %v", err, tt.wantErr)
+			w := NewInterceptor(kubeClient, logger)
+			res := w.Process(ctx, &triggersv1.InterceptorRequest{
+				Body:              tt.args.payload,
+				Header:            header,
+				InterceptorParams: interceptors.GetInterceptorParams(&triggersv1.EventInterceptor{Bitbucket: tt.Bitbucket}),
+				Context: &triggersv1.TriggerContext{
+					TriggerID: "namespaces/default/triggers/example-trigger",
+				},
+			})
+			if tt.want == codes.OK {
+				if !res.Continue {
+					t.Fatalf("Interceptor.Process() unexpectedly stopped the Trigger: %v", res.Status.Err())
 				}
 				return
 			}
-
-			got, err := ioutil.ReadAll(resp.Body)
-			if err != nil {
-				t.Fatalf("error reading response body %v", err)
-			}
-			if diff := cmp.Diff(tt.want, got); diff != "" {
-				t.Errorf("Interceptor.ExecuteTrigger (-want, +got) = %s", diff)
+			if res.Continue || res.Status.Code() != tt.want {
+				t.Errorf("Interceptor.Process() = %+v, want a status with code %s", res, tt.want)
 			}
 		})
 	}
 }
+
+func TestInterceptor_Process_Extensions(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	logger, _ := logging.NewLogger("", "")
+	w := NewInterceptor(fakekubeclient.Get(ctx), logger)
+	res := w.Process(ctx, &triggersv1.InterceptorRequest{
+		Body: []byte(`{"eventKey": "repo:refs_changed"}`),
+		Header: http.Header{
+			"Content-Type": []string{"application/json"},
+			"X-Event-Key":  []string{"repo:refs_changed"},
+			"X-Request-Id": []string{"ce4b3f2a-5a44-4d2b-a36e-0cb1e6c3a4b7"},
+		},
+		InterceptorParams: map[string]interface{}{},
+		Context: &triggersv1.TriggerContext{
+			TriggerID: "namespaces/default/triggers/example-trigger",
+		},
+	})
+	if !res.Continue {
+		t.Fatalf("Interceptor.Process() unexpectedly stopped the Trigger: %v", res.Status.Err())
+	}
+	want := map[string]interface{}{
+		"bitbucket": map[string]interface{}{
+			"event_type": "repo:refs_changed",
+			"request_id": "ce4b3f2a-5a44-4d2b-a36e-0cb1e6c3a4b7",
+		},
+	}
+	if diff := cmp.Diff(want, res.Extensions); diff != "" {
+		t.Errorf("Interceptor.Process() extensions (-want, +got) = %s", diff)
+	}
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package interceptors
+
+import (
+	"sync"
+	"time"
+)
+
+// CircuitBreakerConfig configures a CircuitBreaker.
+type CircuitBreakerConfig struct {
+	// FailureThreshold is the number of consecutive failures opening the
+	// circuit breaker.
+	FailureThreshold int
+	// OpenDuration is how long the circuit breaker stays open before it lets
+	// a request through again.
+	OpenDuration time.Duration
+}
+
+// CircuitBreaker stops sending requests to an interceptor endpoint after
+// consecutive failures, so that an endpoint that is down is not sent every
+// event while it recovers. Once it has been open for its OpenDuration, it lets
+// a single request through, which closes it again when it succeeds.
+type CircuitBreaker struct {
+	now func() time.Time
+
+	config CircuitBreakerConfig
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+}
+
+func newCircuitBreaker(now func() time.Time, config CircuitBreakerConfig) *CircuitBreaker {
+	return &CircuitBreaker{now: now, config: config}
+}
+
+// Allow reports whether a request may be sent to the endpoint. The outcome
+// of an allowed request is reported with Success or Failure.
+func (b *CircuitBreaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failures < b.config.FailureThreshold {
+		return true
+	}
+	now := b.now()
+	if now.Before(b.openUntil) {
+		return false
+	}
+	// Let a single request through, and the next one only if it does not
+	// complete within OpenDuration.
+	b.openUntil = now.Add(b.config.OpenDuration)
+	return true
+}
+
+// Success records that the endpoint responded, which closes the breaker.
+func (b *CircuitBreaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures = 0
+}
+
+// Failure records that the endpoint was unavailable, which opens the breaker
+// once it fails FailureThreshold times in a row.
+func (b *CircuitBreaker) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	if b.failures >= b.config.FailureThreshold {
+		b.openUntil = b.now().Add(b.config.OpenDuration)
+	}
+}
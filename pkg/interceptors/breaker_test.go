@@ -0,0 +1,91 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package interceptors
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Now()
+	clients := NewClients(http.DefaultClient)
+	clients.now = func() time.Time { return now }
+	config := CircuitBreakerConfig{FailureThreshold: 2, OpenDuration: time.Minute}
+	b := clients.Breaker("http://scanner.default.svc", config)
+
+	if !b.Allow() {
+		t.Fatal("Allow() = false for a new breaker")
+	}
+	b.Failure()
+	b.Success()
+	b.Failure()
+	if !b.Allow() {
+		t.Fatal("Allow() = false after a single consecutive failure")
+	}
+	b.Failure()
+	if b.Allow() {
+		t.Fatal("Allow() = true after FailureThreshold consecutive failures")
+	}
+	if other := clients.Breaker("http://other.default.svc", config); !other.Allow() {
+		t.Error("Allow() = false for another endpoint")
+	}
+
+	now = now.Add(time.Minute)
+	if !b.Allow() {
+		t.Fatal("Allow() = false once OpenDuration elapsed")
+	}
+	if b.Allow() {
+		t.Fatal("Allow() = true while a request is let through the open breaker")
+	}
+	b.Failure()
+	now = now.Add(30 * time.Second)
+	if b.Allow() {
+		t.Fatal("Allow() = true after the request let through failed")
+	}
+
+	now = now.Add(time.Minute)
+	if !clients.Breaker("http://scanner.default.svc", config).Allow() {
+		t.Fatal("Allow() = false once OpenDuration elapsed again")
+	}
+	b.Success()
+	if !b.Allow() || !b.Allow() {
+		t.Error("Allow() = false after the request let through succeeded")
+	}
+}
+
+func TestClients_Breaker(t *testing.T) {
+	clients := NewClients(http.DefaultClient)
+	config := CircuitBreakerConfig{FailureThreshold: 1, OpenDuration: time.Minute}
+	b := clients.Breaker("http://scanner.default.svc", config)
+	if again := clients.Breaker("http://scanner.default.svc", config); again != b {
+		t.Error("Breaker() did not share the breaker of an endpoint configured alike")
+	}
+
+	b.Failure()
+	other := clients.Breaker("http://scanner.default.svc", CircuitBreakerConfig{FailureThreshold: 2, OpenDuration: time.Minute})
+	if other == b {
+		t.Fatal("Breaker() shared the breaker of an endpoint configured differently")
+	}
+	if b.Allow() {
+		t.Error("Allow() = true after the configured FailureThreshold, another configuration of the endpoint changed it")
+	}
+	if !other.Allow() {
+		t.Error("Allow() = false for the other configuration of the endpoint")
+	}
+}
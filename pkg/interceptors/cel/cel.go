@@ -40,6 +40,7 @@ import (
 	"k8s.io/client-go/kubernetes"
 
 	triggersv1 "github.com/tektoncd/triggers/pkg/apis/triggers/v1alpha1"
+	"github.com/tektoncd/triggers/pkg/template"
 )
 
 var _ triggersv1.InterceptorInterface = (*Interceptor)(nil)
@@ -70,11 +71,6 @@ func NewInterceptor(k kubernetes.Interface, l *zap.SugaredLogger) *Interceptor {
 	}
 }
 
-// We need to keep this method around till we migrate the other interceptors to the new interface
-func (w *Interceptor) ExecuteTrigger(_ *http.Request) (*http.Response, error) {
-	return nil, fmt.Errorf("executeTrigger() is deprecated. Call Process() instead")
-}
-
 func evaluate(expr string, env *cel.Env, data map[string]interface{}) (ref.Val, error) {
 	parsed, issues := env.Parse(expr)
 	if issues != nil && issues.Err() != nil {
@@ -98,6 +94,29 @@ func evaluate(expr string, env *cel.Env, data map[string]interface{}) (ref.Val,
 	return out, nil
 }
 
+// EvaluateString evaluates the CEL expression against the body, header, URL
+// and source address of an event and returns its result, which must be a
+// string.
+func EvaluateString(expr string, body []byte, h http.Header, url, sourceIP, ns string, k kubernetes.Interface) (string, error) {
+	env, err := makeCelEnv(ns, k)
+	if err != nil {
+		return "", fmt.Errorf("error creating cel environment: %w", err)
+	}
+	evalContext, err := makeEvalContext(body, h, url, sourceIP)
+	if err != nil {
+		return "", fmt.Errorf("error making the evaluation context: %w", err)
+	}
+	out, err := evaluate(expr, env, evalContext)
+	if err != nil {
+		return "", err
+	}
+	str, ok := out.(types.String)
+	if !ok {
+		return "", fmt.Errorf("expression %#v returned a %s, not a string", expr, out.Type().TypeName())
+	}
+	return string(str), nil
+}
+
 func makeCelEnv(ns string, k kubernetes.Interface) (*cel.Env, error) {
 	mapStrDyn := decls.NewMapType(decls.String, decls.Dyn)
 	return cel.NewEnv(
@@ -107,12 +126,18 @@ func makeCelEnv(ns string, k kubernetes.Interface) (*cel.Env, error) {
 			decls.NewVar("body", mapStrDyn),
 			decls.NewVar("header", mapStrDyn),
 			decls.NewVar("requestURL", decls.String),
+			decls.NewVar("sourceIP", decls.String),
+			decls.NewVar("context", decls.NewMapType(decls.String, decls.String)),
 		))
 }
 
-func makeEvalContext(body []byte, h http.Header, url string) (map[string]interface{}, error) {
+func makeEvalContext(body []byte, h http.Header, url, sourceIP string) (map[string]interface{}, error) {
+	body, err := template.DecodeBody(body, h)
+	if err != nil {
+		return nil, err
+	}
 	var jsonMap map[string]interface{}
-	err := json.Unmarshal(body, &jsonMap)
+	err = json.Unmarshal(body, &jsonMap)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse the body as JSON: %w", err)
 	}
@@ -120,6 +145,8 @@ func makeEvalContext(body []byte, h http.Header, url string) (map[string]interfa
 		"body":       jsonMap,
 		"header":     h,
 		"requestURL": url,
+		"sourceIP":   sourceIP,
+		"context":    template.CloudEventContext(h),
 	}, nil
 }
 
@@ -154,7 +181,7 @@ func (w *Interceptor) Process(ctx context.Context, r *triggersv1.InterceptorRequ
 		payload = r.Body
 	}
 
-	evalContext, err := makeEvalContext(payload, r.Header, r.Context.EventURL)
+	evalContext, err := makeEvalContext(payload, r.Header, r.Context.EventURL, r.Context.SourceIP)
 	if err != nil {
 		return &triggersv1.InterceptorResponse{
 			Continue: false,
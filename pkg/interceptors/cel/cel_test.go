@@ -200,6 +200,12 @@ func TestInterceptor_Process(t *testing.T) {
 			Filter: "header.canonical('X-Secret-Token').compareSecret('token', 'test-secret') && body.count == 1.0",
 		},
 		body: json.RawMessage(`{"count":1,"measure":1.7}`),
+	}, {
+		name: "source address check",
+		CEL: &triggersv1.CELInterceptor{
+			Filter: "sourceIP.inCIDR('192.30.252.0/22')",
+		},
+		body: json.RawMessage(`{}`),
 	}, {
 		name: "handling a list response",
 		CEL: &triggersv1.CELInterceptor{
@@ -255,6 +261,7 @@ func TestInterceptor_Process(t *testing.T) {
 					EventURL:  "https://testing.example.com",
 					EventID:   "abcde",
 					TriggerID: fmt.Sprintf("namespaces/%s/triggers/example-trigger", testNS),
+					SourceIP:  "192.30.252.1",
 				},
 			})
 			if !res.Continue {
@@ -420,7 +427,7 @@ func TestExpressionEvaluation(t *testing.T) {
 	header := http.Header{}
 	header.Add("X-Test-Header", "value")
 	req := httptest.NewRequest(http.MethodPost, "https://example.com/testing?param=value", nil)
-	evalEnv := map[string]interface{}{"body": jsonMap, "header": header, "requestURL": req.URL.String()}
+	evalEnv := map[string]interface{}{"body": jsonMap, "header": header, "requestURL": req.URL.String(), "sourceIP": "192.30.252.1"}
 	tests := []struct {
 		name   string
 		expr   string
@@ -540,6 +547,26 @@ func TestExpressionEvaluation(t *testing.T) {
 			expr: "body.upperMsg.lowerAscii()",
 			want: types.String("this is lower case"),
 		},
+		{
+			name: "source address in a CIDR",
+			expr: "sourceIP.inCIDR('192.30.252.0/22')",
+			want: types.Bool(true),
+		},
+		{
+			name: "source address not in a CIDR",
+			expr: "sourceIP.inCIDR('10.0.0.0/8')",
+			want: types.Bool(false),
+		},
+		{
+			name: "source address in a list of CIDRs",
+			expr: "sourceIP.inCIDR(['10.0.0.0/8', '192.30.252.0/22'])",
+			want: types.Bool(true),
+		},
+		{
+			name: "source address not in a list of CIDRs",
+			expr: "sourceIP.inCIDR(['10.0.0.0/8', '2001:db8::/32'])",
+			want: types.Bool(false),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(rt *testing.T) {
@@ -650,6 +677,16 @@ func TestExpressionEvaluation_Error(t *testing.T) {
 			expr: "body.valid_yaml.parseYAML().key3 == 'value3'",
 			want: "no such key: key3",
 		},
+		{
+			name: "inCIDR of an invalid address",
+			expr: "body.value.inCIDR('10.0.0.0/8')",
+			want: "failed to parse IP address 'testing' in inCIDR",
+		},
+		{
+			name: "inCIDR with an invalid CIDR",
+			expr: "'10.0.0.1'.inCIDR(['testing', '10.0.0.0/8'])",
+			want: "failed to parse CIDR 'testing' in inCIDR",
+		},
 		{
 			name: "invalid YAML body",
 			expr: "body.invalid_yaml.parseYAML().key1 == 'value1'",
@@ -705,11 +742,47 @@ func TestURLToMap(t *testing.T) {
 	}
 }
 
+func TestMakeEvalContextWithFormBody(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	payload := []byte(`payload=%7B%22ref%22%3A%22refs%2Fheads%2Fmain%22%7D`)
+
+	ctx, err := makeEvalContext(payload, req.Header, req.URL.String(), "")
+	if err != nil {
+		t.Fatalf("makeEvalContext() unexpected error: %v", err)
+	}
+	want := map[string]interface{}{"ref": "refs/heads/main"}
+	if diff := cmp.Diff(want, ctx["body"]); diff != "" {
+		t.Errorf("makeEvalContext() body (-want, +got) = %s", diff)
+	}
+}
+
+func TestMakeEvalContextWithCloudEvent(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.Header.Set("Ce-Specversion", "1.0")
+	req.Header.Set("Ce-Type", "dev.knative.example")
+	env, err := makeCelEnv(testNS, nil)
+	if err != nil {
+		t.Fatalf("makeCelEnv() unexpected error: %v", err)
+	}
+	ctx, err := makeEvalContext([]byte(`{}`), req.Header, req.URL.String(), "")
+	if err != nil {
+		t.Fatalf("makeEvalContext() unexpected error: %v", err)
+	}
+	got, err := evaluate("context.type == 'dev.knative.example' && context.specversion == '1.0'", env, ctx)
+	if err != nil {
+		t.Fatalf("evaluate() unexpected error: %v", err)
+	}
+	if got != types.True {
+		t.Errorf("evaluate() = %v, want true", got)
+	}
+}
+
 func TestMakeEvalContextWithError(t *testing.T) {
 	req := httptest.NewRequest(http.MethodPost, "/", nil)
 	payload := []byte(`{"tes`)
 
-	_, err := makeEvalContext(payload, req.Header, req.URL.String())
+	_, err := makeEvalContext(payload, req.Header, req.URL.String(), "")
 
 	if !matchError(t, "failed to parse the body as JSON: unexpected end of JSON input", err) {
 		t.Fatalf("failed to match the error: %s", err)
@@ -736,3 +809,55 @@ func makeSecret() *corev1.Secret {
 		},
 	}
 }
+
+func TestEvaluateString(t *testing.T) {
+	payload := []byte(`{"delivery": {"id": "abc-123", "attempt": 2}}`)
+	header := http.Header{"X-Delivery": []string{"def-456"}}
+	tests := []struct {
+		name    string
+		expr    string
+		want    string
+		wantErr string
+	}{{
+		name: "body value",
+		expr: "body.delivery.id",
+		want: "abc-123",
+	}, {
+		name: "header value",
+		expr: "header.canonical('X-Delivery')",
+		want: "def-456",
+	}, {
+		name: "converted value",
+		expr: "body.delivery.id + '-' + string(int(body.delivery.attempt))",
+		want: "abc-123-2",
+	}, {
+		name: "source address",
+		expr: "sourceIP",
+		want: "192.30.252.1",
+	}, {
+		name:    "not a string",
+		expr:    "body.delivery.attempt",
+		wantErr: `expression "body.delivery.attempt" returned a double, not a string`,
+	}, {
+		name:    "unknown value",
+		expr:    "body.missing",
+		wantErr: "no such key: missing",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := EvaluateString(tt.expr, payload, header, "https://example.com", "192.30.252.1", testNS, nil)
+			if tt.wantErr != "" {
+				if !matchError(t, tt.wantErr, err) {
+					t.Fatalf("EvaluateString() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("EvaluateString() unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("EvaluateString() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"crypto/subtle"
 	"encoding/base64"
 	"encoding/json"
+	"net"
 	"net/http"
 	"net/url"
 	"reflect"
@@ -28,6 +29,7 @@ import (
 	"github.com/google/cel-go/checker/decls"
 	"github.com/google/cel-go/common/types"
 	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/common/types/traits"
 	"github.com/google/cel-go/interpreter/functions"
 	"github.com/tektoncd/triggers/pkg/interceptors"
 	"k8s.io/client-go/kubernetes"
@@ -148,6 +150,19 @@ import (
 // Examples:
 //
 // 		body.field.parseYAML().item
+//
+// inCIDR
+//
+// Returns true if the string is an IP address within the CIDR, or within any
+// of the list of CIDRs.
+//
+//     <string>.inCIDR(<string>) -> <bool>
+//     <string>.inCIDR(<list<string>>) -> <bool>
+//
+// Examples:
+//
+//     sourceIP.inCIDR('192.30.252.0/22')
+//     sourceIP.inCIDR(['192.30.252.0/22', '185.199.108.0/22'])
 
 // Triggers creates and returns a new cel.Lib with the triggers extensions.
 func Triggers(ns string, k kubernetes.Interface) cel.EnvOption {
@@ -189,7 +204,12 @@ func (triggersLib) CompileOptions() []cel.EnvOption {
 					[]*exprpb.Type{decls.String}, mapStrDyn)),
 			decls.NewFunction("compareSecret",
 				decls.NewInstanceOverload("compareSecret_string_string",
-					[]*exprpb.Type{decls.String, decls.String, decls.String}, decls.Bool)))}
+					[]*exprpb.Type{decls.String, decls.String, decls.String}, decls.Bool)),
+			decls.NewFunction("inCIDR",
+				decls.NewInstanceOverload("inCIDR_string_string",
+					[]*exprpb.Type{decls.String, decls.String}, decls.Bool),
+				decls.NewInstanceOverload("inCIDR_string_list",
+					[]*exprpb.Type{decls.String, decls.NewListType(decls.String)}, decls.Bool)))}
 }
 
 func (t triggersLib) ProgramOptions() []cel.ProgramOption {
@@ -219,6 +239,9 @@ func (t triggersLib) ProgramOptions() []cel.ProgramOption {
 			&functions.Overload{
 				Operator: "compareSecret",
 				Function: makeCompareSecret(t.defaultNS, t.client)},
+			&functions.Overload{
+				Operator: "inCIDR",
+				Binary:   inCIDR},
 		)}
 }
 
@@ -318,6 +341,41 @@ func makeCompareSecret(defaultNS string, k kubernetes.Interface) functions.Funct
 	}
 }
 
+func inCIDR(lhs, rhs ref.Val) ref.Val {
+	str, ok := lhs.(types.String)
+	if !ok {
+		return types.ValOrErr(str, "unexpected type '%v' passed to inCIDR", lhs.Type())
+	}
+	ip := net.ParseIP(string(str))
+	if ip == nil {
+		return types.NewErr("failed to parse IP address '%v' in inCIDR", str)
+	}
+
+	var cidrs []string
+	switch v := rhs.(type) {
+	case types.String:
+		cidrs = []string{string(v)}
+	case traits.Lister:
+		native, err := v.ConvertToNative(reflect.TypeOf([]string{}))
+		if err != nil {
+			return types.NewErr("failed to convert CIDRs in inCIDR: %w", err)
+		}
+		cidrs = native.([]string)
+	default:
+		return types.ValOrErr(rhs, "unexpected type '%v' passed to inCIDR", rhs.Type())
+	}
+	for _, cidr := range cidrs {
+		_, n, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return types.NewErr("failed to parse CIDR '%v' in inCIDR: %w", cidr, err)
+		}
+		if n.Contains(ip) {
+			return types.True
+		}
+	}
+	return types.False
+}
+
 func parseJSONString(val ref.Val) ref.Val {
 	str, ok := val.(types.String)
 	if !ok {
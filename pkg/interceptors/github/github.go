@@ -17,85 +17,110 @@ limitations under the License.
 package github
 
 import (
-	"bytes"
-	"errors"
-	"fmt"
-	"io/ioutil"
+	"context"
+	"encoding/json"
 	"net/http"
+	"sort"
+	"strings"
 
 	gh "github.com/google/go-github/v31/github"
 	triggersv1 "github.com/tektoncd/triggers/pkg/apis/triggers/v1alpha1"
 	"github.com/tektoncd/triggers/pkg/interceptors"
+	"github.com/tektoncd/triggers/pkg/template"
 	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
 	"k8s.io/client-go/kubernetes"
 )
 
-// ErrInvalidContentType is returned when the content-type is not a JSON body.
-var ErrInvalidContentType = errors.New("form parameter encoding not supported, please change the hook to send JSON payloads")
+var _ triggersv1.InterceptorInterface = (*Interceptor)(nil)
 
+// Interceptor validates the signature and event type of the webhooks sent
+// by GitHub.
 type Interceptor struct {
-	KubeClientSet          kubernetes.Interface
-	Logger                 *zap.SugaredLogger
-	GitHub                 *triggersv1.GitHubInterceptor
-	EventListenerNamespace string
+	KubeClientSet kubernetes.Interface
+	Logger        *zap.SugaredLogger
 }
 
-func NewInterceptor(gh *triggersv1.GitHubInterceptor, k kubernetes.Interface, ns string, l *zap.SugaredLogger) interceptors.Interceptor {
+// NewInterceptor creates a prepopulated Interceptor.
+func NewInterceptor(k kubernetes.Interface, l *zap.SugaredLogger) *Interceptor {
 	return &Interceptor{
-		Logger:                 l,
-		GitHub:                 gh,
-		KubeClientSet:          k,
-		EventListenerNamespace: ns,
+		Logger:        l,
+		KubeClientSet: k,
 	}
 }
 
-func (w *Interceptor) ExecuteTrigger(request *http.Request) (*http.Response, error) {
-	payload := []byte{}
-	var err error
-	if v := request.Header.Get("Content-Type"); v == "application/x-www-form-urlencoded" {
-		return nil, ErrInvalidContentType
-	}
-
-	if request.Body != nil {
-		defer request.Body.Close()
-		payload, err = ioutil.ReadAll(request.Body)
-		if err != nil {
-			return nil, fmt.Errorf("failed to read request body: %w", err)
-		}
+// Process validates the event against the GitHubInterceptor of the request's
+// InterceptorParams. It adds the event type, delivery ID and, for push
+// events, the files changed by the pushed commits to the extensions under
+// the github key.
+func (w *Interceptor) Process(ctx context.Context, r *triggersv1.InterceptorRequest) *triggersv1.InterceptorResponse {
+	p := triggersv1.GitHubInterceptor{}
+	if err := interceptors.UnmarshalParams(r.InterceptorParams, &p); err != nil {
+		return interceptors.Failf(codes.InvalidArgument, "failed to parse interceptor params: %v", err)
 	}
+	header := http.Header(r.Header)
 
 	// Validate secrets first before anything else, if set
-	if w.GitHub.SecretRef != nil {
-		header := request.Header.Get("X-Hub-Signature")
-		if header == "" {
-			return nil, errors.New("no X-Hub-Signature header set")
+	if p.SecretRef != nil {
+		signature := header.Get("X-Hub-Signature")
+		if signature == "" {
+			return interceptors.Fail(codes.Unauthenticated, "no X-Hub-Signature header set")
 		}
-		secretToken, err := interceptors.GetSecretToken(request, w.KubeClientSet, w.GitHub.SecretRef, w.EventListenerNamespace)
+		ns, _ := triggersv1.ParseTriggerID(r.Context.TriggerID)
+		secretToken, err := interceptors.GetSecretTokenFromContext(ctx, w.KubeClientSet, p.SecretRef, ns)
 		if err != nil {
-			return nil, err
+			return interceptors.Failf(codes.Internal, "error getting secret: %v", err)
 		}
-		if err := gh.ValidateSignature(header, payload, secretToken); err != nil {
-			return nil, err
+		if err := gh.ValidateSignature(signature, r.Body, secretToken); err != nil {
+			return interceptors.Failf(codes.Unauthenticated, "invalid X-Hub-Signature: %v", err)
 		}
 	}
 
 	// Next see if the event type is in the allow-list
-	if w.GitHub.EventTypes != nil {
-		actualEvent := request.Header.Get("X-GitHub-Event")
-		isAllowed := false
-		for _, allowedEvent := range w.GitHub.EventTypes {
-			if actualEvent == allowedEvent {
-				isAllowed = true
-				break
-			}
-		}
-		if !isAllowed {
-			return nil, fmt.Errorf("event type %s is not allowed", actualEvent)
+	eventType := header.Get("X-GitHub-Event")
+	if !interceptors.Allowed(eventType, p.EventTypes) {
+		return interceptors.Failf(codes.FailedPrecondition, "event type %s is not allowed", eventType)
+	}
+
+	extensions := map[string]interface{}{
+		"event_type":  eventType,
+		"delivery_id": header.Get("X-GitHub-Delivery"),
+	}
+	if eventType == "push" {
+		if files, ok := changedFiles(r.Body, header); ok {
+			extensions["changed_files"] = files
 		}
 	}
+	return &triggersv1.InterceptorResponse{
+		Continue:   true,
+		Extensions: map[string]interface{}{"github": extensions},
+	}
+}
 
-	return &http.Response{
-		Header: request.Header,
-		Body:   ioutil.NopCloser(bytes.NewBuffer(payload)),
-	}, nil
+// changedFiles returns the files added, modified or removed by the commits
+// of a push event as a comma separated list, or false if the event cannot be
+// decoded.
+func changedFiles(body []byte, header http.Header) (string, bool) {
+	body, err := template.DecodeBody(body, header)
+	if err != nil {
+		return "", false
+	}
+	var push gh.PushEvent
+	if err := json.Unmarshal(body, &push); err != nil {
+		return "", false
+	}
+	files := map[string]bool{}
+	for _, c := range push.Commits {
+		for _, changed := range [][]string{c.Added, c.Modified, c.Removed} {
+			for _, f := range changed {
+				files[f] = true
+			}
+		}
+	}
+	list := make([]string, 0, len(files))
+	for f := range files {
+		list = append(list, f)
+	}
+	sort.Strings(list)
+	return strings.Join(list, ","), true
 }
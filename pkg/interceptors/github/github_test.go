@@ -17,15 +17,17 @@ limitations under the License.
 package github
 
 import (
-	"bytes"
-	"io"
-	"io/ioutil"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/hex"
 	"net/http"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 
 	triggersv1 "github.com/tektoncd/triggers/pkg/apis/triggers/v1alpha1"
+	"github.com/tektoncd/triggers/pkg/interceptors"
+	"google.golang.org/grpc/codes"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	fakekubeclient "knative.dev/pkg/client/injection/kube/client/fake"
@@ -33,29 +35,27 @@ import (
 	rtesting "knative.dev/pkg/reconciler/testing"
 )
 
-func TestInterceptor_ExecuteTrigger_Signature(t *testing.T) {
+func TestInterceptor_Process(t *testing.T) {
 	type args struct {
-		payload   io.ReadCloser
+		payload   []byte
 		secret    *corev1.Secret
 		signature string
 		eventType string
 	}
 	tests := []struct {
-		name    string
-		GitHub  *triggersv1.GitHubInterceptor
-		args    args
-		want    []byte
-		wantErr bool
+		name   string
+		GitHub *triggersv1.GitHubInterceptor
+		args   args
+		want   codes.Code
 	}{
 		{
 			name:   "no secret",
 			GitHub: &triggersv1.GitHubInterceptor{},
 			args: args{
-				payload:   ioutil.NopCloser(bytes.NewBufferString("somepayload")),
+				payload:   []byte("somepayload"),
 				signature: "foo",
 			},
-			want:    []byte("somepayload"),
-			wantErr: false,
+			want: codes.OK,
 		},
 		{
 			name: "invalid header for secret",
@@ -75,9 +75,9 @@ func TestInterceptor_ExecuteTrigger_Signature(t *testing.T) {
 						"token": []byte("secrettoken"),
 					},
 				},
-				payload: ioutil.NopCloser(bytes.NewBufferString("somepayload")),
+				payload: []byte("somepayload"),
 			},
-			wantErr: true,
+			want: codes.Unauthenticated,
 		},
 		{
 			name: "valid header for secret",
@@ -99,10 +99,9 @@ func TestInterceptor_ExecuteTrigger_Signature(t *testing.T) {
 						"token": []byte("secret"),
 					},
 				},
-				payload: ioutil.NopCloser(bytes.NewBufferString("somepayload")),
+				payload: []byte("somepayload"),
 			},
-			wantErr: false,
-			want:    []byte("somepayload"),
+			want: codes.OK,
 		},
 		{
 			name: "no secret, matching event",
@@ -110,11 +109,10 @@ func TestInterceptor_ExecuteTrigger_Signature(t *testing.T) {
 				EventTypes: []string{"MY_EVENT", "YOUR_EVENT"},
 			},
 			args: args{
-				payload:   ioutil.NopCloser(bytes.NewBufferString("somepayload")),
+				payload:   []byte("somepayload"),
 				eventType: "YOUR_EVENT",
 			},
-			wantErr: false,
-			want:    []byte("somepayload"),
+			want: codes.OK,
 		},
 		{
 			name: "no secret, failing event",
@@ -122,10 +120,10 @@ func TestInterceptor_ExecuteTrigger_Signature(t *testing.T) {
 				EventTypes: []string{"MY_EVENT", "YOUR_EVENT"},
 			},
 			args: args{
-				payload:   ioutil.NopCloser(bytes.NewBufferString("somepayload")),
+				payload:   []byte("somepayload"),
 				eventType: "OTHER_EVENT",
 			},
-			wantErr: true,
+			want: codes.FailedPrecondition,
 		},
 		{
 			name: "valid header for secret and matching event",
@@ -149,10 +147,9 @@ func TestInterceptor_ExecuteTrigger_Signature(t *testing.T) {
 					},
 				},
 				eventType: "MY_EVENT",
-				payload:   ioutil.NopCloser(bytes.NewBufferString("somepayload")),
+				payload:   []byte("somepayload"),
 			},
-			wantErr: false,
-			want:    []byte("somepayload"),
+			want: codes.OK,
 		},
 		{
 			name: "valid header for secret, failing event",
@@ -176,9 +173,9 @@ func TestInterceptor_ExecuteTrigger_Signature(t *testing.T) {
 					},
 				},
 				eventType: "OTHER_EVENT",
-				payload:   ioutil.NopCloser(bytes.NewBufferString("somepayload")),
+				payload:   []byte("somepayload"),
 			},
-			wantErr: true,
+			want: codes.FailedPrecondition,
 		},
 		{
 			name: "invalid header for secret, matching event",
@@ -200,9 +197,9 @@ func TestInterceptor_ExecuteTrigger_Signature(t *testing.T) {
 					},
 				},
 				eventType: "MY_EVENT",
-				payload:   ioutil.NopCloser(bytes.NewBufferString("somepayload")),
+				payload:   []byte("somepayload"),
 			},
-			wantErr: true,
+			want: codes.Unauthenticated,
 		}, {
 			name:   "nil body does not panic",
 			GitHub: &triggersv1.GitHubInterceptor{},
@@ -210,8 +207,21 @@ func TestInterceptor_ExecuteTrigger_Signature(t *testing.T) {
 				payload:   nil,
 				signature: "foo",
 			},
-			want:    []byte{},
-			wantErr: false,
+			want: codes.OK,
+		},
+		{
+			name: "missing secret",
+			GitHub: &triggersv1.GitHubInterceptor{
+				SecretRef: &triggersv1.SecretRef{
+					SecretName: "mysecret",
+					SecretKey:  "token",
+				},
+			},
+			args: args{
+				signature: "sha1=38e005ef7dd3faee13204505532011257023654e",
+				payload:   []byte("somepayload"),
+			},
+			want: codes.Internal,
 		},
 	}
 	for _, tt := range tests {
@@ -219,67 +229,146 @@ func TestInterceptor_ExecuteTrigger_Signature(t *testing.T) {
 			ctx, _ := rtesting.SetupFakeContext(t)
 			logger, _ := logging.NewLogger("", "")
 			kubeClient := fakekubeclient.Get(ctx)
-			request := &http.Request{
-				Body: tt.args.payload,
-				Header: http.Header{
-					"Content-Type": []string{"application/json"},
-				},
+			header := http.Header{
+				"Content-Type": []string{"application/json"},
 			}
 			if tt.args.eventType != "" {
-				request.Header.Add("X-GITHUB-EVENT", tt.args.eventType)
+				header.Add("X-GITHUB-EVENT", tt.args.eventType)
 			}
 			if tt.args.signature != "" {
-				request.Header.Add("X-Hub-Signature", tt.args.signature)
+				header.Add("X-Hub-Signature", tt.args.signature)
 			}
 			if tt.args.secret != nil {
 				if _, err := kubeClient.CoreV1().Secrets(metav1.NamespaceDefault).Create(ctx, tt.args.secret, metav1.CreateOptions{}); err != nil {
 					t.Error(err)
 				}
 			}
-			w := &Interceptor{
-				KubeClientSet:          kubeClient,
-				GitHub:                 tt.GitHub,
-				Logger:                 logger,
-				EventListenerNamespace: metav1.NamespaceDefault,
-			}
-			resp, err := w.ExecuteTrigger(request)
-			if err != nil {
-				if !tt.wantErr {
-					t.Errorf("Interceptor.ExecuteTrigger() error = %v, wantErr %v", err, tt.wantErr)
+			w := NewInterceptor(kubeClient, logger)
+			res := w.Process(ctx, &triggersv1.InterceptorRequest{
+				Body:              tt.args.payload,
+				Header:            header,
+				InterceptorParams: interceptors.GetInterceptorParams(&triggersv1.EventInterceptor{GitHub: tt.GitHub}),
+				Context: &triggersv1.TriggerContext{
+					EventURL:  "https://testing.example.com",
+					EventID:   "abcde",
+					TriggerID: "namespaces/default/triggers/example-trigger",
+				},
+			})
+			if tt.want == codes.OK {
+				if !res.Continue {
+					t.Fatalf("Interceptor.Process() unexpectedly stopped the Trigger: %v", res.Status.Err())
 				}
 				return
 			}
-
-			got, err := ioutil.ReadAll(resp.Body)
-			if err != nil {
-				t.Fatalf("error reading response body %v", err)
-			}
-			if diff := cmp.Diff(tt.want, got); diff != "" {
-				t.Errorf("Interceptor.ExecuteTrigger (-want, +got) = %s", diff)
+			if res.Continue || res.Status.Code() != tt.want {
+				t.Errorf("Interceptor.Process() = %+v, want a status with code %s", res, tt.want)
 			}
 		})
 	}
 }
 
-func TestInterceptor_ExecuteTrigger_with_invalid_content_type(t *testing.T) {
+func TestInterceptor_Process_with_form_content_type(t *testing.T) {
 	ctx, _ := rtesting.SetupFakeContext(t)
 	logger, _ := logging.NewLogger("", "")
 	kubeClient := fakekubeclient.Get(ctx)
-	request := &http.Request{
-		Body: ioutil.NopCloser(bytes.NewBufferString("somepayload")),
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "mysecret",
+		},
+		Data: map[string][]byte{
+			"token": []byte("secret"),
+		},
+	}
+	if _, err := kubeClient.CoreV1().Secrets(metav1.NamespaceDefault).Create(ctx, secret, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error creating secret: %v", err)
+	}
+	// The signature is computed over the form encoded body, and the files
+	// changed by a push event are read from its payload parameter.
+	payload := []byte(`payload=%7B%22commits%22%3A%5B%7B%22added%22%3A%5B%22README.md%22%5D%7D%5D%7D`)
+	mac := hmac.New(sha1.New, []byte("secret"))
+	mac.Write(payload)
+	w := NewInterceptor(kubeClient, logger)
+	res := w.Process(ctx, &triggersv1.InterceptorRequest{
+		Body: payload,
 		Header: http.Header{
 			"Content-Type":    []string{"application/x-www-form-urlencoded"},
-			"X-Hub-Signature": []string{"foo"},
+			"X-Hub-Signature": []string{"sha1=" + hex.EncodeToString(mac.Sum(nil))},
+			"X-Github-Event":  []string{"push"},
 		},
+		InterceptorParams: interceptors.GetInterceptorParams(&triggersv1.EventInterceptor{
+			GitHub: &triggersv1.GitHubInterceptor{
+				SecretRef: &triggersv1.SecretRef{
+					SecretName: "mysecret",
+					SecretKey:  "token",
+				},
+			},
+		}),
+		Context: &triggersv1.TriggerContext{
+			TriggerID: "namespaces/default/triggers/example-trigger",
+		},
+	})
+	if !res.Continue {
+		t.Fatalf("Interceptor.Process() unexpectedly stopped the Trigger: %v", res.Status.Err())
+	}
+	want := map[string]interface{}{
+		"event_type":    "push",
+		"delivery_id":   "",
+		"changed_files": "README.md",
 	}
-	w := &Interceptor{
-		KubeClientSet:          kubeClient,
-		GitHub:                 &triggersv1.GitHubInterceptor{},
-		Logger:                 logger,
-		EventListenerNamespace: metav1.NamespaceDefault,
+	if diff := cmp.Diff(want, res.Extensions["github"]); diff != "" {
+		t.Errorf("Interceptor.Process() extensions (-want, +got) = %s", diff)
 	}
-	_, err := w.ExecuteTrigger(request)
-	if err != ErrInvalidContentType {
-		t.Fatalf("got error %v, want %v", err, ErrInvalidContentType)
+}
+
+func TestInterceptor_Process_Extensions(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	logger, _ := logging.NewLogger("", "")
+	w := NewInterceptor(fakekubeclient.Get(ctx), logger)
+	for _, tc := range []struct {
+		name      string
+		eventType string
+		body      string
+		want      map[string]interface{}
+	}{{
+		name:      "push",
+		eventType: "push",
+		body: `{"commits": [
+			{"added": ["docs/new.md"], "modified": ["README.md"], "removed": []},
+			{"added": [], "modified": ["README.md", "main.go"], "removed": ["old.go"]}
+		]}`,
+		want: map[string]interface{}{
+			"event_type":    "push",
+			"delivery_id":   "72d3162e-cc78-11e3-81ab-4c9367dc0958",
+			"changed_files": "README.md,docs/new.md,main.go,old.go",
+		},
+	}, {
+		name:      "pull request",
+		eventType: "pull_request",
+		body:      `{"action": "opened"}`,
+		want: map[string]interface{}{
+			"event_type":  "pull_request",
+			"delivery_id": "72d3162e-cc78-11e3-81ab-4c9367dc0958",
+		},
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			res := w.Process(ctx, &triggersv1.InterceptorRequest{
+				Body: []byte(tc.body),
+				Header: http.Header{
+					"Content-Type":      []string{"application/json"},
+					"X-Github-Event":    []string{tc.eventType},
+					"X-Github-Delivery": []string{"72d3162e-cc78-11e3-81ab-4c9367dc0958"},
+				},
+				InterceptorParams: map[string]interface{}{},
+				Context: &triggersv1.TriggerContext{
+					TriggerID: "namespaces/default/triggers/example-trigger",
+				},
+			})
+			if !res.Continue {
+				t.Fatalf("Interceptor.Process() unexpectedly stopped the Trigger: %v", res.Status.Err())
+			}
+			if diff := cmp.Diff(map[string]interface{}{"github": tc.want}, res.Extensions); diff != "" {
+				t.Errorf("Interceptor.Process() extensions (-want, +got) = %s", diff)
+			}
+		})
 	}
 }
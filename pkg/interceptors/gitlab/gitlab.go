@@ -17,9 +17,8 @@ limitations under the License.
 package gitlab
 
 import (
+	"context"
 	"crypto/subtle"
-	"errors"
-	"fmt"
 	"net/http"
 
 	"github.com/tektoncd/triggers/pkg/interceptors"
@@ -27,59 +26,68 @@ import (
 	triggersv1 "github.com/tektoncd/triggers/pkg/apis/triggers/v1alpha1"
 
 	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
 	"k8s.io/client-go/kubernetes"
 )
 
+var _ triggersv1.InterceptorInterface = (*Interceptor)(nil)
+
+// Interceptor validates the token and event type of the webhooks sent by
+// GitLab.
 type Interceptor struct {
-	KubeClientSet          kubernetes.Interface
-	Logger                 *zap.SugaredLogger
-	GitLab                 *triggersv1.GitLabInterceptor
-	EventListenerNamespace string
+	KubeClientSet kubernetes.Interface
+	Logger        *zap.SugaredLogger
 }
 
-func NewInterceptor(gl *triggersv1.GitLabInterceptor, k kubernetes.Interface, ns string, l *zap.SugaredLogger) interceptors.Interceptor {
+// NewInterceptor creates a prepopulated Interceptor.
+func NewInterceptor(k kubernetes.Interface, l *zap.SugaredLogger) *Interceptor {
 	return &Interceptor{
-		Logger:                 l,
-		GitLab:                 gl,
-		KubeClientSet:          k,
-		EventListenerNamespace: ns,
+		Logger:        l,
+		KubeClientSet: k,
 	}
 }
 
-func (w *Interceptor) ExecuteTrigger(request *http.Request) (*http.Response, error) {
+// Process validates the event against the GitLabInterceptor of the request's
+// InterceptorParams. It adds the event type and UUID to the extensions under
+// the gitlab key.
+func (w *Interceptor) Process(ctx context.Context, r *triggersv1.InterceptorRequest) *triggersv1.InterceptorResponse {
+	p := triggersv1.GitLabInterceptor{}
+	if err := interceptors.UnmarshalParams(r.InterceptorParams, &p); err != nil {
+		return interceptors.Failf(codes.InvalidArgument, "failed to parse interceptor params: %v", err)
+	}
+	header := http.Header(r.Header)
+
 	// Validate the secret first, if set.
-	if w.GitLab.SecretRef != nil {
-		header := request.Header.Get("X-GitLab-Token")
-		if header == "" {
-			return nil, errors.New("no X-GitLab-Token header set")
+	if p.SecretRef != nil {
+		token := header.Get("X-GitLab-Token")
+		if token == "" {
+			return interceptors.Fail(codes.Unauthenticated, "no X-GitLab-Token header set")
 		}
 
-		secretToken, err := interceptors.GetSecretToken(request, w.KubeClientSet, w.GitLab.SecretRef, w.EventListenerNamespace)
+		ns, _ := triggersv1.ParseTriggerID(r.Context.TriggerID)
+		secretToken, err := interceptors.GetSecretTokenFromContext(ctx, w.KubeClientSet, p.SecretRef, ns)
 		if err != nil {
-			return nil, err
+			return interceptors.Failf(codes.Internal, "error getting secret: %v", err)
 		}
 
 		// Make sure to use a constant time comparison here.
-		if subtle.ConstantTimeCompare([]byte(header), secretToken) == 0 {
-			return nil, errors.New("Invalid X-GitLab-Token")
+		if subtle.ConstantTimeCompare([]byte(token), secretToken) == 0 {
+			return interceptors.Fail(codes.Unauthenticated, "Invalid X-GitLab-Token")
 		}
 	}
-	if w.GitLab.EventTypes != nil {
-		actualEvent := request.Header.Get("X-GitLab-Event")
-		isAllowed := false
-		for _, allowedEvent := range w.GitLab.EventTypes {
-			if actualEvent == allowedEvent {
-				isAllowed = true
-				break
-			}
-		}
-		if !isAllowed {
-			return nil, fmt.Errorf("event type %s is not allowed", actualEvent)
-		}
+
+	eventType := header.Get("X-GitLab-Event")
+	if !interceptors.Allowed(eventType, p.EventTypes) {
+		return interceptors.Failf(codes.FailedPrecondition, "event type %s is not allowed", eventType)
 	}
 
-	return &http.Response{
-		Header: request.Header,
-		Body:   request.Body,
-	}, nil
+	return &triggersv1.InterceptorResponse{
+		Continue: true,
+		Extensions: map[string]interface{}{
+			"gitlab": map[string]interface{}{
+				"event_type": eventType,
+				"event_uuid": header.Get("X-Gitlab-Event-UUID"),
+			},
+		},
+	}
 }
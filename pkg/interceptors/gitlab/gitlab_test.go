@@ -17,15 +17,14 @@ limitations under the License.
 package gitlab
 
 import (
-	"bytes"
-	"context"
-	"io/ioutil"
 	"net/http"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 
 	triggersv1 "github.com/tektoncd/triggers/pkg/apis/triggers/v1alpha1"
+	"github.com/tektoncd/triggers/pkg/interceptors"
+	"google.golang.org/grpc/codes"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	fakekubeclient "knative.dev/pkg/client/injection/kube/client/fake"
@@ -33,7 +32,7 @@ import (
 	rtesting "knative.dev/pkg/reconciler/testing"
 )
 
-func TestInterceptor_ExecuteTrigger(t *testing.T) {
+func TestInterceptor_Process(t *testing.T) {
 	type args struct {
 		payload   []byte
 		secret    *corev1.Secret
@@ -41,11 +40,10 @@ func TestInterceptor_ExecuteTrigger(t *testing.T) {
 		eventType string
 	}
 	tests := []struct {
-		name    string
-		GitLab  *triggersv1.GitLabInterceptor
-		args    args
-		want    []byte
-		wantErr bool
+		name   string
+		GitLab *triggersv1.GitLabInterceptor
+		args   args
+		want   codes.Code
 	}{
 		{
 			name:   "no secret",
@@ -54,8 +52,7 @@ func TestInterceptor_ExecuteTrigger(t *testing.T) {
 				payload: []byte("somepayload"),
 				token:   "foo",
 			},
-			want:    []byte("somepayload"),
-			wantErr: false,
+			want: codes.OK,
 		},
 		{
 			name: "invalid header for secret",
@@ -77,7 +74,7 @@ func TestInterceptor_ExecuteTrigger(t *testing.T) {
 				},
 				payload: []byte("somepayload"),
 			},
-			wantErr: true,
+			want: codes.Unauthenticated,
 		},
 		{
 			name: "valid header for secret",
@@ -99,8 +96,7 @@ func TestInterceptor_ExecuteTrigger(t *testing.T) {
 				},
 				payload: []byte("somepayload"),
 			},
-			wantErr: false,
-			want:    []byte("somepayload"),
+			want: codes.OK,
 		},
 		{
 			name: "valid event",
@@ -111,8 +107,7 @@ func TestInterceptor_ExecuteTrigger(t *testing.T) {
 				eventType: "foo",
 				payload:   []byte("somepayload"),
 			},
-			wantErr: false,
-			want:    []byte("somepayload"),
+			want: codes.OK,
 		},
 		{
 			name: "invalid event",
@@ -123,7 +118,7 @@ func TestInterceptor_ExecuteTrigger(t *testing.T) {
 				eventType: "baz",
 				payload:   []byte("somepayload"),
 			},
-			wantErr: true,
+			want: codes.FailedPrecondition,
 		},
 		{
 			name: "valid event, invalid secret",
@@ -147,7 +142,7 @@ func TestInterceptor_ExecuteTrigger(t *testing.T) {
 					},
 				},
 			},
-			wantErr: true,
+			want: codes.Unauthenticated,
 		},
 		{
 			name: "invalid event, valid secret",
@@ -171,7 +166,7 @@ func TestInterceptor_ExecuteTrigger(t *testing.T) {
 					},
 				},
 			},
-			wantErr: true,
+			want: codes.FailedPrecondition,
 		},
 		{
 			name: "valid event, valid secret",
@@ -195,7 +190,7 @@ func TestInterceptor_ExecuteTrigger(t *testing.T) {
 					},
 				},
 			},
-			want: []byte("somepayload"),
+			want: codes.OK,
 		},
 	}
 	for _, tt := range tests {
@@ -203,45 +198,68 @@ func TestInterceptor_ExecuteTrigger(t *testing.T) {
 			ctx, _ := rtesting.SetupFakeContext(t)
 			logger, _ := logging.NewLogger("", "")
 			kubeClient := fakekubeclient.Get(ctx)
-			request := &http.Request{
-				Body: ioutil.NopCloser(bytes.NewReader(tt.args.payload)),
-				Header: http.Header{
-					"Content-Type": []string{"application/json"},
-				},
+			header := http.Header{
+				"Content-Type": []string{"application/json"},
 			}
 			if tt.args.token != "" {
-				request.Header.Add("X-GitLab-Token", tt.args.token)
+				header.Add("X-GitLab-Token", tt.args.token)
 			}
 			if tt.args.eventType != "" {
-				request.Header.Add("X-GitLab-Event", tt.args.eventType)
+				header.Add("X-GitLab-Event", tt.args.eventType)
 			}
 			if tt.args.secret != nil {
-				if _, err := kubeClient.CoreV1().Secrets(metav1.NamespaceDefault).Create(context.Background(), tt.args.secret, metav1.CreateOptions{}); err != nil {
+				if _, err := kubeClient.CoreV1().Secrets(metav1.NamespaceDefault).Create(ctx, tt.args.secret, metav1.CreateOptions{}); err != nil {
 					t.Error(err)
 				}
 			}
-			w := &Interceptor{
-				KubeClientSet:          kubeClient,
-				GitLab:                 tt.GitLab,
-				Logger:                 logger,
-				EventListenerNamespace: metav1.NamespaceDefault,
-			}
-			resp, err := w.ExecuteTrigger(request)
-			if err != nil {
-				if !tt.wantErr {
-					t.Errorf("Interceptor.ExecuteTrigger() error = %v, wantErr %v", err, tt.wantErr)
+			w := NewInterceptor(kubeClient, logger)
+			res := w.Process(ctx, &triggersv1.InterceptorRequest{
+				Body:              tt.args.payload,
+				Header:            header,
+				InterceptorParams: interceptors.GetInterceptorParams(&triggersv1.EventInterceptor{GitLab: tt.GitLab}),
+				Context: &triggersv1.TriggerContext{
+					TriggerID: "namespaces/default/triggers/example-trigger",
+				},
+			})
+			if tt.want == codes.OK {
+				if !res.Continue {
+					t.Fatalf("Interceptor.Process() unexpectedly stopped the Trigger: %v", res.Status.Err())
 				}
 				return
 			}
-
-			got, err := ioutil.ReadAll(resp.Body)
-			if err != nil {
-				t.Fatalf("error reading response: %v", err)
-			}
-			defer resp.Body.Close()
-			if diff := cmp.Diff(tt.want, got); diff != "" {
-				t.Errorf("Interceptor.ExecuteTrigger (-want, +got) = %s", diff)
+			if res.Continue || res.Status.Code() != tt.want {
+				t.Errorf("Interceptor.Process() = %+v, want a status with code %s", res, tt.want)
 			}
 		})
 	}
 }
+
+func TestInterceptor_Process_Extensions(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	logger, _ := logging.NewLogger("", "")
+	w := NewInterceptor(fakekubeclient.Get(ctx), logger)
+	res := w.Process(ctx, &triggersv1.InterceptorRequest{
+		Body: []byte(`{"object_kind": "push"}`),
+		Header: http.Header{
+			"Content-Type":        []string{"application/json"},
+			"X-Gitlab-Event":      []string{"Push Hook"},
+			"X-Gitlab-Event-Uuid": []string{"13792a34-cac6-4fda-95a8-c58e00a3954e"},
+		},
+		InterceptorParams: map[string]interface{}{},
+		Context: &triggersv1.TriggerContext{
+			TriggerID: "namespaces/default/triggers/example-trigger",
+		},
+	})
+	if !res.Continue {
+		t.Fatalf("Interceptor.Process() unexpectedly stopped the Trigger: %v", res.Status.Err())
+	}
+	want := map[string]interface{}{
+		"gitlab": map[string]interface{}{
+			"event_type": "Push Hook",
+			"event_uuid": "13792a34-cac6-4fda-95a8-c58e00a3954e",
+		},
+	}
+	if diff := cmp.Diff(want, res.Extensions); diff != "" {
+		t.Errorf("Interceptor.Process() extensions (-want, +got) = %s", diff)
+	}
+}
@@ -18,56 +18,113 @@ package interceptors
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"path"
 
 	triggersv1 "github.com/tektoncd/triggers/pkg/apis/triggers/v1alpha1"
+	"github.com/tektoncd/triggers/pkg/tracing"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 )
 
-// Interceptor is the interface that all interceptors implement.
-type Interceptor interface {
-	ExecuteTrigger(req *http.Request) (*http.Response, error)
-}
-
 type key string
 
 const requestCacheKey key = "interceptors.RequestCache"
 
-func getCache(req *http.Request) map[string]interface{} {
-	if cache, ok := req.Context().Value(requestCacheKey).(map[string]interface{}); ok {
+func getCache(ctx context.Context) map[string]interface{} {
+	if cache, ok := ctx.Value(requestCacheKey).(map[string]interface{}); ok {
 		return cache
 	}
 
 	return make(map[string]interface{})
 }
 
+// Fail returns the response of an interceptor that stops processing the
+// Trigger with the status code and message.
+func Fail(code codes.Code, msg string) *triggersv1.InterceptorResponse {
+	return &triggersv1.InterceptorResponse{
+		Continue: false,
+		Status:   status.New(code, msg),
+	}
+}
+
+// Failf returns the response of an interceptor that stops processing the
+// Trigger with the status code and formatted message.
+func Failf(code codes.Code, format string, a ...interface{}) *triggersv1.InterceptorResponse {
+	return Fail(code, fmt.Sprintf(format, a...))
+}
+
+// UnmarshalParams decodes the InterceptorParams of a request into p, which
+// is the spec of the interceptor in the Trigger.
+func UnmarshalParams(ip map[string]interface{}, p interface{}) error {
+	b, err := json.Marshal(ip)
+	if err != nil {
+		return fmt.Errorf("failed to marshal json: %w", err)
+	}
+	if err := json.Unmarshal(b, p); err != nil {
+		return fmt.Errorf("invalid json: %w", err)
+	}
+	return nil
+}
+
+// Allowed reports whether the event type is one of the allowed event types.
+// Any event type is allowed when there are none.
+func Allowed(eventType string, allowed []string) bool {
+	if allowed == nil {
+		return true
+	}
+	for _, a := range allowed {
+		if a == eventType {
+			return true
+		}
+	}
+	return false
+}
+
 // GetSecretToken queries Kubernetes for the given secret reference. We use this function
 // to resolve secret material like GitHub webhook secrets, and call it once for every
 // trigger that references it.
 //
 // As we may have many triggers that all use the same secret, we cache the secret values
-// in the request cache.
+// in the request cache, and across requests in the SecretCache of the context, if any.
 func GetSecretToken(req *http.Request, cs kubernetes.Interface, sr *triggersv1.SecretRef, eventListenerNamespace string) ([]byte, error) {
-	var cache map[string]interface{}
+	ctx := context.Background()
+	if req != nil {
+		ctx = req.Context()
+	}
+	return GetSecretTokenFromContext(ctx, cs, sr, eventListenerNamespace)
+}
 
+// GetSecretTokenFromContext is GetSecretToken for the interceptors that are
+// passed the context of the request rather than the request itself.
+func GetSecretTokenFromContext(ctx context.Context, cs kubernetes.Interface, sr *triggersv1.SecretRef, eventListenerNamespace string) ([]byte, error) {
 	cacheKey := path.Join("secret", eventListenerNamespace, sr.SecretName, sr.SecretKey)
-	if req != nil {
-		cache = getCache(req)
-		if secretValue, ok := cache[cacheKey]; ok {
-			return secretValue.([]byte), nil
+	cache := getCache(ctx)
+	if secretValue, ok := cache[cacheKey]; ok {
+		return secretValue.([]byte), nil
+	}
+	secrets := getSecretCache(ctx)
+	if secrets != nil {
+		if secretValue, ok := secrets.get(cacheKey); ok {
+			return secretValue, nil
 		}
 	}
 
-	secret, err := cs.CoreV1().Secrets(eventListenerNamespace).Get(context.Background(), sr.SecretName, metav1.GetOptions{})
+	ctx, span := tracing.StartSpan(ctx, "GetSecretToken")
+	secret, err := cs.CoreV1().Secrets(eventListenerNamespace).Get(ctx, sr.SecretName, metav1.GetOptions{})
+	tracing.EndSpan(span, err)
 	if err != nil {
 		return nil, err
 	}
 
 	secretValue := secret.Data[sr.SecretKey]
-	if req != nil {
-		cache[cacheKey] = secret.Data[sr.SecretKey]
+	cache[cacheKey] = secretValue
+	if secrets != nil {
+		secrets.set(cacheKey, secretValue)
 	}
 
 	return secretValue, nil
@@ -83,6 +140,27 @@ func GetInterceptorParams(i *triggersv1.EventInterceptor) map[string]interface{}
 		if i.Webhook != nil {
 			ip["objectRef"] = i.Webhook.ObjectRef
 			ip["header"] = i.Webhook.Header
+			if i.Webhook.URL != nil {
+				ip["url"] = i.Webhook.URL
+			}
+			if i.Webhook.Service != nil {
+				ip["service"] = i.Webhook.Service
+			}
+			if i.Webhook.Timeout != nil {
+				ip["timeout"] = i.Webhook.Timeout
+			}
+			if i.Webhook.TLS != nil {
+				ip["tls"] = i.Webhook.TLS
+			}
+			if i.Webhook.Retry != nil {
+				ip["retry"] = i.Webhook.Retry
+			}
+			if i.Webhook.CircuitBreaker != nil {
+				ip["circuitBreaker"] = i.Webhook.CircuitBreaker
+			}
+			if i.Webhook.FailurePolicy != "" {
+				ip["failurePolicy"] = i.Webhook.FailurePolicy
+			}
 		}
 	case i.GitHub != nil:
 		if i.GitHub.EventTypes != nil {
@@ -114,6 +192,13 @@ func GetInterceptorParams(i *triggersv1.EventInterceptor) map[string]interface{}
 		if i.Bitbucket.SecretRef != nil {
 			ip["secretRef"] = i.Bitbucket.SecretRef
 		}
+	case i.Ref != nil:
+		for _, p := range i.Params {
+			var v interface{}
+			// Values are validated to be JSON
+			_ = json.Unmarshal(p.Value.Raw, &v)
+			ip[p.Name] = v
+		}
 	}
 
 	return ip
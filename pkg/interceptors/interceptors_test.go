@@ -21,12 +21,15 @@ import (
 	"fmt"
 	"net/http"
 	"testing"
+	"time"
 
 	pipelinev1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 
 	"github.com/google/go-cmp/cmp"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"knative.dev/pkg/apis"
 	fakekubeclient "knative.dev/pkg/client/injection/kube/client/fake"
 	rtesting "knative.dev/pkg/reconciler/testing"
 
@@ -146,6 +149,59 @@ func TestGetInterceptorParams(t *testing.T) {
 				},
 			}},
 		},
+	}, {
+		name: "webhook url, timeout and tls",
+		in: triggersv1.EventInterceptor{
+			Webhook: &triggersv1.WebhookInterceptor{
+				URL:     apis.HTTPS("scanner.example.com"),
+				Timeout: &metav1.Duration{Duration: 30 * time.Second},
+				TLS:     &triggersv1.WebhookTLS{CASecretName: "scanner-ca"},
+			},
+		},
+		want: map[string]interface{}{
+			"objectRef": (*corev1.ObjectReference)(nil),
+			"header":    []pipelinev1.Param(nil),
+			"url":       apis.HTTPS("scanner.example.com"),
+			"timeout":   &metav1.Duration{Duration: 30 * time.Second},
+			"tls":       &triggersv1.WebhookTLS{CASecretName: "scanner-ca"},
+		},
+	}, {
+		name: "webhook retry, circuit breaker and failure policy",
+		in: triggersv1.EventInterceptor{
+			Webhook: &triggersv1.WebhookInterceptor{
+				URL:            apis.HTTP("scanner.example.com"),
+				Retry:          &triggersv1.WebhookRetryPolicy{MaxAttempts: 5},
+				CircuitBreaker: &triggersv1.WebhookCircuitBreaker{FailureThreshold: 10},
+				FailurePolicy:  triggersv1.WebhookFailurePolicyIgnore,
+			},
+		},
+		want: map[string]interface{}{
+			"objectRef":      (*corev1.ObjectReference)(nil),
+			"header":         []pipelinev1.Param(nil),
+			"url":            apis.HTTP("scanner.example.com"),
+			"retry":          &triggersv1.WebhookRetryPolicy{MaxAttempts: 5},
+			"circuitBreaker": &triggersv1.WebhookCircuitBreaker{FailureThreshold: 10},
+			"failurePolicy":  triggersv1.WebhookFailurePolicyIgnore,
+		},
+	}, {
+		name: "ref",
+		in: triggersv1.EventInterceptor{
+			Ref: &triggersv1.InterceptorRef{Name: "scanner"},
+			Params: []triggersv1.InterceptorParams{{
+				Name:  "severities",
+				Value: runtime.RawExtension{Raw: []byte(`["high", "critical"]`)},
+			}, {
+				Name:  "options",
+				Value: runtime.RawExtension{Raw: []byte(`{"strict": true, "retries": 2}`)},
+			}},
+		},
+		want: map[string]interface{}{
+			"severities": []interface{}{"high", "critical"},
+			"options": map[string]interface{}{
+				"strict":  true,
+				"retries": float64(2),
+			},
+		},
 	}} {
 		t.Run(tc.name, func(t *testing.T) {
 			got := GetInterceptorParams(&tc.in)
@@ -156,6 +212,44 @@ func TestGetInterceptorParams(t *testing.T) {
 	}
 }
 
+func TestUnmarshalParams(t *testing.T) {
+	in := &triggersv1.EventInterceptor{
+		GitHub: &triggersv1.GitHubInterceptor{
+			SecretRef:  &triggersv1.SecretRef{SecretName: "foo", SecretKey: "bar"},
+			EventTypes: []string{"push"},
+		},
+	}
+	var got triggersv1.GitHubInterceptor
+	if err := UnmarshalParams(GetInterceptorParams(in), &got); err != nil {
+		t.Fatalf("UnmarshalParams() unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(*in.GitHub, got); diff != "" {
+		t.Errorf("UnmarshalParams() (-want/+got): %s", diff)
+	}
+
+	if err := UnmarshalParams(map[string]interface{}{"eventTypes": "push"}, &got); err == nil {
+		t.Error("UnmarshalParams() expected an error for params of the wrong type")
+	}
+}
+
+func TestAllowed(t *testing.T) {
+	tests := []struct {
+		eventType string
+		allowed   []string
+		want      bool
+	}{
+		{eventType: "push", allowed: nil, want: true},
+		{eventType: "push", allowed: []string{"pull_request", "push"}, want: true},
+		{eventType: "push", allowed: []string{"pull_request"}, want: false},
+		{eventType: "", allowed: []string{"pull_request"}, want: false},
+	}
+	for _, tt := range tests {
+		if got := Allowed(tt.eventType, tt.allowed); got != tt.want {
+			t.Errorf("Allowed(%q, %v) = %t, want %t", tt.eventType, tt.allowed, got, tt.want)
+		}
+	}
+}
+
 func Test_GetSecretToken(t *testing.T) {
 	tests := []struct {
 		name   string
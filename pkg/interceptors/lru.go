@@ -0,0 +1,72 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package interceptors
+
+import "container/list"
+
+// lru holds up to max values, dropping the least recently used ones beyond
+// it. It is not safe for concurrent use.
+type lru struct {
+	max     int
+	entries map[interface{}]*list.Element
+	order   *list.List
+	// evicted, if set, is called with the values that are dropped
+	evicted func(value interface{})
+}
+
+type lruEntry struct {
+	key   interface{}
+	value interface{}
+}
+
+func newLRU(max int, evicted func(value interface{})) *lru {
+	return &lru{
+		max:     max,
+		entries: map[interface{}]*list.Element{},
+		order:   list.New(),
+		evicted: evicted,
+	}
+}
+
+func (c *lru) get(key interface{}) (interface{}, bool) {
+	e, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(e)
+	return e.Value.(*lruEntry).value, true
+}
+
+func (c *lru) add(key, value interface{}) {
+	if e, ok := c.entries[key]; ok {
+		e.Value.(*lruEntry).value = value
+		c.order.MoveToFront(e)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&lruEntry{key: key, value: value})
+	for c.order.Len() > c.max {
+		oldest := c.order.Remove(c.order.Back()).(*lruEntry)
+		delete(c.entries, oldest.key)
+		if c.evicted != nil {
+			c.evicted(oldest.value)
+		}
+	}
+}
+
+func (c *lru) len() int {
+	return c.order.Len()
+}
@@ -0,0 +1,242 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package interceptors
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	triggersv1 "github.com/tektoncd/triggers/pkg/apis/triggers/v1alpha1"
+	"github.com/tektoncd/triggers/pkg/tracing"
+	"google.golang.org/grpc/codes"
+)
+
+const (
+	// remoteTimeout is the timeout of the requests to remote interceptors.
+	remoteTimeout = 5 * time.Second
+	// TokenAudience is the audience of the ServiceAccount tokens that
+	// EventListeners send to remote interceptors, so that the tokens cannot
+	// be used against the Kubernetes API server.
+	TokenAudience = "triggers.tekton.dev/interceptors"
+	// maxClients and maxBreakers are the numbers of HTTP clients and circuit
+	// breakers kept by Clients, beyond which the least recently used ones are
+	// dropped.
+	maxClients  = 64
+	maxBreakers = 256
+)
+
+var _ triggersv1.InterceptorInterface = (*Remote)(nil)
+
+// Remote is an interceptor that runs out of process, such as a
+// ClusterInterceptor. Events are sent to it as JSON encoded
+// InterceptorRequests, and it responds with a JSON encoded
+// InterceptorResponse.
+type Remote struct {
+	HTTPClient *http.Client
+	URL        *url.URL
+	// TokenFile, if set, holds the bearer token sent to the interceptor. It
+	// is read for each request so that the token can be rotated.
+	TokenFile string
+}
+
+// NewRemote creates a Remote interceptor reached at u with the client c.
+func NewRemote(c *http.Client, u *url.URL) *Remote {
+	return &Remote{
+		HTTPClient: &http.Client{
+			Transport: c.Transport,
+			Timeout:   remoteTimeout,
+		},
+		URL: u,
+	}
+}
+
+// Process sends the request to the interceptor and returns its response.
+// Interceptors that cannot be reached, or respond with anything but 200 OK
+// and an InterceptorResponse, fail the Trigger.
+func (r *Remote) Process(ctx context.Context, req *triggersv1.InterceptorRequest) *triggersv1.InterceptorResponse {
+	b, err := json.Marshal(req)
+	if err != nil {
+		return Failf(codes.Internal, "failed to encode the interceptor request: %v", err)
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, r.URL.String(), bytes.NewReader(b))
+	if err != nil {
+		return Failf(codes.Internal, "failed to create the request to the interceptor: %v", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+	if r.TokenFile != "" {
+		token, err := ioutil.ReadFile(r.TokenFile)
+		if err != nil {
+			return Failf(codes.Internal, "failed to read the token sent to the interceptor: %v", err)
+		}
+		request.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	}
+	tracing.Inject(ctx, request)
+
+	resp, err := r.HTTPClient.Do(request)
+	if err != nil {
+		return Failf(codes.Unavailable, "failed to send the event to the interceptor: %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return Failf(codes.Unavailable, "failed to read the response of the interceptor: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		code := codes.Internal
+		if resp.StatusCode >= http.StatusInternalServerError {
+			code = codes.Unavailable
+		}
+		return Failf(code, "interceptor responded with status: %s; message: %s", resp.Status, body)
+	}
+	var res triggersv1.InterceptorResponse
+	if err := json.Unmarshal(body, &res); err != nil {
+		return Failf(codes.Internal, "failed to decode the interceptor response: %v", err)
+	}
+	return &res
+}
+
+// Clients holds the HTTP clients used to reach remote interceptors, one for
+// each set of certificates that their connections use, so that their
+// connections are reused across events. It also holds the circuit breakers
+// of their endpoints. Only the most recently used clients and breakers are
+// kept.
+type Clients struct {
+	base *http.Client
+	now  func() time.Time
+
+	mu sync.Mutex
+	// clients are keyed by the hash of their certificates, so that private
+	// keys are not kept as keys
+	clients *lru
+	// breakers are keyed by their breakerKey
+	breakers *lru
+}
+
+// breakerKey identifies the circuit breakers of Clients, so that
+// interceptors sharing an endpoint with different configurations do not
+// share a breaker.
+type breakerKey struct {
+	endpoint string
+	config   CircuitBreakerConfig
+}
+
+// NewClients returns the Clients derived from base, which reaches
+// interceptors without a CA bundle.
+func NewClients(base *http.Client) *Clients {
+	return &Clients{
+		base: base,
+		now:  time.Now,
+		clients: newLRU(maxClients, func(v interface{}) {
+			v.(*http.Client).CloseIdleConnections()
+		}),
+		breakers: newLRU(maxBreakers, nil),
+	}
+}
+
+// Breaker returns the circuit breaker of the endpoint configured with
+// config. Interceptors sharing an endpoint share its breaker when they
+// configure it alike.
+func (c *Clients) Breaker(endpoint string, config CircuitBreakerConfig) *CircuitBreaker {
+	key := breakerKey{endpoint: endpoint, config: config}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if b, ok := c.breakers.get(key); ok {
+		return b.(*CircuitBreaker)
+	}
+	b := newCircuitBreaker(c.now, config)
+	c.breakers.add(key, b)
+	return b
+}
+
+// Get returns the client verifying certificates with the PEM encoded CA
+// bundle, or the base client when there is none.
+func (c *Clients) Get(caBundle []byte) (*http.Client, error) {
+	return c.ForTLS(TLS{CABundle: caBundle})
+}
+
+// TLS holds the PEM encoded certificates of the HTTPS connections to an
+// interceptor.
+type TLS struct {
+	// CABundle verifies the certificate of the interceptor. The system's CA
+	// certificates are used when it is empty.
+	CABundle []byte
+	// ClientCert and ClientKey are the client certificate and private key
+	// sent to the interceptor, if any.
+	ClientCert []byte
+	ClientKey  []byte
+}
+
+// ForTLS returns the client connecting to interceptors with the
+// certificates of t, or the base client when t holds none.
+func (c *Clients) ForTLS(t TLS) (*http.Client, error) {
+	if len(t.CABundle) == 0 && len(t.ClientCert) == 0 && len(t.ClientKey) == 0 {
+		return c.base, nil
+	}
+	key := sha256.Sum256([]byte(strings.Join([]string{string(t.CABundle), string(t.ClientCert), string(t.ClientKey)}, "\x00")))
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if client, ok := c.clients.get(key); ok {
+		return client.(*http.Client), nil
+	}
+
+	base := c.base.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	transport, ok := base.(*http.Transport)
+	if !ok {
+		return nil, fmt.Errorf("cannot set the TLS configuration of a %T", base)
+	}
+	transport = transport.Clone()
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+	if transport.TLSClientConfig != nil {
+		config = transport.TLSClientConfig.Clone()
+	}
+	if len(t.CABundle) != 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(t.CABundle) {
+			return nil, errors.New("caBundle holds no PEM encoded certificates")
+		}
+		config.RootCAs = pool
+	}
+	if len(t.ClientCert) != 0 || len(t.ClientKey) != 0 {
+		cert, err := tls.X509KeyPair(t.ClientCert, t.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	transport.TLSClientConfig = config
+	client := &http.Client{
+		Transport: transport,
+		Timeout:   c.base.Timeout,
+	}
+	c.clients.add(key, client)
+	return client, nil
+}
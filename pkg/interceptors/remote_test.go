@@ -0,0 +1,218 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package interceptors
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	triggersv1 "github.com/tektoncd/triggers/pkg/apis/triggers/v1alpha1"
+	"google.golang.org/grpc/codes"
+)
+
+func TestRemote_Process(t *testing.T) {
+	var got triggersv1.InterceptorRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if got.InterceptorParams["reject"] == true {
+			_, _ = w.Write([]byte(`{"status": {"code": "PERMISSION_DENIED", "message": "repository not allowed"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"continue": true, "extensions": {"scanner": {"severity": "low"}}}`))
+	}))
+	defer srv.Close()
+	u, _ := url.Parse(srv.URL)
+	r := NewRemote(srv.Client(), u)
+
+	req := &triggersv1.InterceptorRequest{
+		Body:              []byte(`{"repository": "triggers"}`),
+		Header:            map[string][]string{"X-Event": {"push"}},
+		Extensions:        map[string]interface{}{},
+		InterceptorParams: map[string]interface{}{"severities": []interface{}{"high"}},
+		Context: &triggersv1.TriggerContext{
+			EventID:   "abcde",
+			TriggerID: "namespaces/default/triggers/scan",
+		},
+	}
+	res := r.Process(context.Background(), req)
+	if !res.Continue {
+		t.Fatalf("Process() unexpectedly stopped the Trigger: %v", res.Status.Err())
+	}
+	if diff := cmp.Diff(map[string]interface{}{"scanner": map[string]interface{}{"severity": "low"}}, res.Extensions); diff != "" {
+		t.Errorf("Process() extensions (-want +got): %s", diff)
+	}
+	if diff := cmp.Diff(req, &got, cmpopts.EquateEmpty()); diff != "" {
+		t.Errorf("Process() sent request (-want +got): %s", diff)
+	}
+
+	req.InterceptorParams = map[string]interface{}{"reject": true}
+	res = r.Process(context.Background(), req)
+	if res.Continue || res.Status.Code() != codes.PermissionDenied || res.Status.Message() != "repository not allowed" {
+		t.Errorf("Process() = %+v, want a PermissionDenied status", res)
+	}
+}
+
+func TestRemote_Process_Token(t *testing.T) {
+	var got string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get("Authorization")
+		_, _ = w.Write([]byte(`{"continue": true}`))
+	}))
+	defer srv.Close()
+	u, _ := url.Parse(srv.URL)
+	dir, err := ioutil.TempDir("", "token")
+	if err != nil {
+		t.Fatalf("Error creating the token directory: %s", err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+	tokenFile := filepath.Join(dir, "token")
+	if err := ioutil.WriteFile(tokenFile, []byte("abcde\n"), 0600); err != nil {
+		t.Fatalf("Error writing the token: %s", err)
+	}
+	r := NewRemote(srv.Client(), u)
+	r.TokenFile = tokenFile
+
+	if res := r.Process(context.Background(), &triggersv1.InterceptorRequest{}); !res.Continue {
+		t.Fatalf("Process() unexpectedly stopped the Trigger: %v", res.Status.Err())
+	}
+	if got != "Bearer abcde" {
+		t.Errorf("Process() sent Authorization %q, want the bearer token of the token file", got)
+	}
+
+	r.TokenFile = filepath.Join(dir, "missing")
+	if res := r.Process(context.Background(), &triggersv1.InterceptorRequest{}); res.Continue || res.Status.Code() != codes.Internal {
+		t.Errorf("Process() = %+v without a token, want an Internal status", res)
+	}
+}
+
+func TestRemote_Process_Error(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		want    codes.Code
+	}{{
+		name: "server error",
+		handler: func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "overloaded", http.StatusServiceUnavailable)
+		},
+		want: codes.Unavailable,
+	}, {
+		name: "not found",
+		handler: func(w http.ResponseWriter, r *http.Request) {
+			http.NotFound(w, r)
+		},
+		want: codes.Internal,
+	}, {
+		name: "invalid response",
+		handler: func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`continue`))
+		},
+		want: codes.Internal,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(tt.handler)
+			defer srv.Close()
+			u, _ := url.Parse(srv.URL)
+			res := NewRemote(srv.Client(), u).Process(context.Background(), &triggersv1.InterceptorRequest{})
+			if res.Continue || res.Status.Code() != tt.want {
+				t.Errorf("Process() = %+v, want a status with code %s", res, tt.want)
+			}
+		})
+	}
+
+	t.Run("unreachable", func(t *testing.T) {
+		srv := httptest.NewServer(http.NotFoundHandler())
+		u, _ := url.Parse(srv.URL)
+		srv.Close()
+		res := NewRemote(http.DefaultClient, u).Process(context.Background(), &triggersv1.InterceptorRequest{})
+		if res.Continue || res.Status.Code() != codes.Unavailable {
+			t.Errorf("Process() = %+v, want a status with code %s", res, codes.Unavailable)
+		}
+	})
+}
+
+func TestClients(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"continue": true}`))
+	}))
+	defer srv.Close()
+	u, _ := url.Parse(srv.URL)
+	caBundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+
+	clients := NewClients(http.DefaultClient)
+	if c, err := clients.Get(nil); err != nil || c != http.DefaultClient {
+		t.Errorf("Get() without a CA bundle = %v, %v, want the base client", c, err)
+	}
+	if res := NewRemote(http.DefaultClient, u).Process(context.Background(), &triggersv1.InterceptorRequest{}); res.Continue {
+		t.Error("Process() continued without verifying the certificate of the interceptor")
+	}
+
+	c, err := clients.Get(caBundle)
+	if err != nil {
+		t.Fatalf("Get() unexpected error: %v", err)
+	}
+	if res := NewRemote(c, u).Process(context.Background(), &triggersv1.InterceptorRequest{}); !res.Continue {
+		t.Errorf("Process() with the CA bundle unexpectedly stopped the Trigger: %v", res.Status.Err())
+	}
+	if again, _ := clients.Get(caBundle); again != c {
+		t.Error("Get() did not reuse the client of the CA bundle")
+	}
+	if _, err := clients.Get([]byte("not a certificate")); err == nil {
+		t.Error("Get() expected an error for an invalid CA bundle")
+	}
+}
+
+func TestClients_Eviction(t *testing.T) {
+	srv := httptest.NewTLSServer(http.NotFoundHandler())
+	defer srv.Close()
+	caBundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	// The same certificate with a trailing newline is a different bundle
+	bundles := [][]byte{caBundle, append(append([]byte{}, caBundle...), '\n')}
+
+	clients := NewClients(http.DefaultClient)
+	clients.clients.max = 1
+	first, err := clients.Get(bundles[0])
+	if err != nil {
+		t.Fatalf("Get() unexpected error: %v", err)
+	}
+	if again, _ := clients.Get(bundles[0]); again != first {
+		t.Error("Get() did not reuse the client of the CA bundle")
+	}
+	if _, err := clients.Get(bundles[1]); err != nil {
+		t.Fatalf("Get() unexpected error: %v", err)
+	}
+	if n := clients.clients.len(); n != 1 {
+		t.Errorf("Clients kept %d clients, want 1", n)
+	}
+	if again, _ := clients.Get(bundles[0]); again == first {
+		t.Error("Get() reused the client of a CA bundle that should have been evicted")
+	}
+}
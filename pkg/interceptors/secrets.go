@@ -0,0 +1,85 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package interceptors
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const secretCacheKey key = "interceptors.SecretCache"
+
+// SecretCache keeps the secret values that interceptors read across
+// requests, so that servers intercepting many events do not get the same
+// secrets from Kubernetes for each of them.
+type SecretCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]secretEntry
+}
+
+type secretEntry struct {
+	value   []byte
+	expires time.Time
+}
+
+// NewSecretCache returns a SecretCache keeping values for ttl, so that
+// rotated secrets are picked up once their values expire.
+func NewSecretCache(ttl time.Duration) *SecretCache {
+	return &SecretCache{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: map[string]secretEntry{},
+	}
+}
+
+// WithSecretCache returns a context whose interceptors read secrets through
+// the cache c.
+func WithSecretCache(ctx context.Context, c *SecretCache) context.Context {
+	return context.WithValue(ctx, secretCacheKey, c)
+}
+
+func getSecretCache(ctx context.Context) *SecretCache {
+	c, _ := ctx.Value(secretCacheKey).(*SecretCache)
+	return c
+}
+
+func (c *SecretCache) get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !c.now().Before(e.expires) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return e.value, true
+}
+
+func (c *SecretCache) set(key string, value []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = secretEntry{
+		value:   value,
+		expires: c.now().Add(c.ttl),
+	}
+}
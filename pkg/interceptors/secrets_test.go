@@ -0,0 +1,63 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package interceptors
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakekubeclient "knative.dev/pkg/client/injection/kube/client/fake"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestSecretCache(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	kubeClient := fakekubeclient.Get(ctx)
+	secretRef := makeSecretRef()
+	if _, err := kubeClient.CoreV1().Secrets(testNS).Create(ctx, makeSecret("first"), metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	secrets := NewSecretCache(time.Minute)
+	secrets.now = func() time.Time { return now }
+	getSecret := func() string {
+		t.Helper()
+		// Each request has its own request cache
+		v, err := GetSecretTokenFromContext(WithSecretCache(context.Background(), secrets), kubeClient, &secretRef, testNS)
+		if err != nil {
+			t.Fatalf("GetSecretTokenFromContext() unexpected error: %v", err)
+		}
+		return string(v)
+	}
+
+	if got := getSecret(); got != "first" {
+		t.Errorf("GetSecretTokenFromContext() = %q, want %q", got, "first")
+	}
+	if _, err := kubeClient.CoreV1().Secrets(testNS).Update(ctx, makeSecret("rotated"), metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if got := getSecret(); got != "first" {
+		t.Errorf("GetSecretTokenFromContext() before the value expired = %q, want %q", got, "first")
+	}
+	now = now.Add(time.Minute)
+	if got := getSecret(); got != "rotated" {
+		t.Errorf("GetSecretTokenFromContext() after the value expired = %q, want %q", got, "rotated")
+	}
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/tektoncd/triggers/pkg/interceptors"
+	"k8s.io/client-go/kubernetes"
+)
+
+// serviceAccountPrefix prefixes the usernames of ServiceAccounts.
+const serviceAccountPrefix = "system:serviceaccount:"
+
+// errUnauthenticated is returned when the caller of an interceptor could not
+// be authenticated.
+var errUnauthenticated = errors.New("unauthenticated")
+
+// Authenticator authenticates the callers of the interceptors.
+type Authenticator interface {
+	// Authenticate returns the namespace of the ServiceAccount that sent the
+	// request.
+	Authenticate(r *http.Request) (string, error)
+}
+
+// TokenReviewAuthenticator authenticates callers by the ServiceAccount token
+// that they send as a bearer token, which must be bound to its audience. The
+// outcome of reviewing a token is kept for a while, so that a TokenReview is
+// not created for each event.
+type TokenReviewAuthenticator struct {
+	audience string
+	reviews  *interceptors.TokenReviewCache
+}
+
+// NewTokenReviewAuthenticator returns a TokenReviewAuthenticator reviewing
+// tokens bound to audience with k, and keeping their namespace for ttl.
+func NewTokenReviewAuthenticator(k kubernetes.Interface, audience string, ttl time.Duration) *TokenReviewAuthenticator {
+	return &TokenReviewAuthenticator{
+		audience: audience,
+		reviews:  interceptors.NewTokenReviewCache(k, ttl),
+	}
+}
+
+// Authenticate returns the namespace of the ServiceAccount whose token the
+// request sends.
+func (a *TokenReviewAuthenticator) Authenticate(r *http.Request) (string, error) {
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "Bearer ") {
+		return "", fmt.Errorf("%w: missing bearer token", errUnauthenticated)
+	}
+	token := strings.TrimSpace(strings.TrimPrefix(auth, "Bearer "))
+	status, err := a.reviews.Review(r.Context(), token, []string{a.audience})
+	if err != nil {
+		return "", err
+	}
+	if !status.Authenticated {
+		if status.Error != "" {
+			return "", fmt.Errorf("%w: %s", errUnauthenticated, status.Error)
+		}
+		return "", fmt.Errorf("%w: token not authenticated", errUnauthenticated)
+	}
+	// The username of a ServiceAccount is system:serviceaccount:<namespace>:<name>
+	parts := strings.Split(strings.TrimPrefix(status.User.Username, serviceAccountPrefix), ":")
+	if !strings.HasPrefix(status.User.Username, serviceAccountPrefix) || len(parts) != 2 || parts[0] == "" {
+		return "", fmt.Errorf("%w: user %s is not a ServiceAccount", errUnauthenticated, status.User.Username)
+	}
+	return parts[0], nil
+}
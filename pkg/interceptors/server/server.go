@@ -0,0 +1,174 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package server serves interceptors over HTTP, so that EventListeners reach
+// them as ClusterInterceptors.
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	triggersv1 "github.com/tektoncd/triggers/pkg/apis/triggers/v1alpha1"
+	"github.com/tektoncd/triggers/pkg/interceptors"
+	"github.com/tektoncd/triggers/pkg/interceptors/bitbucket"
+	"github.com/tektoncd/triggers/pkg/interceptors/cel"
+	"github.com/tektoncd/triggers/pkg/interceptors/github"
+	"github.com/tektoncd/triggers/pkg/interceptors/gitlab"
+	"github.com/tektoncd/triggers/pkg/tracing"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Server serves each of its interceptors under the path of its name. The
+// events to intercept are POSTed as JSON encoded InterceptorRequests, and
+// the interceptors respond with JSON encoded InterceptorResponses.
+type Server struct {
+	Logger *zap.SugaredLogger
+	// Secrets keeps the secrets that the interceptors read across requests.
+	// Secrets are read for each request when it is nil.
+	Secrets *interceptors.SecretCache
+	// Authenticator, if set, authenticates the callers of the interceptors,
+	// which then only intercept the events of the Triggers in the namespace
+	// of their caller, since they read secrets from that namespace.
+	Authenticator Authenticator
+
+	interceptors map[string]triggersv1.InterceptorInterface
+}
+
+// NewWithCoreInterceptors returns a Server serving the github, gitlab,
+// bitbucket and cel interceptors.
+func NewWithCoreInterceptors(k kubernetes.Interface, secrets *interceptors.SecretCache, l *zap.SugaredLogger) *Server {
+	s := &Server{
+		Logger:       l,
+		Secrets:      secrets,
+		interceptors: map[string]triggersv1.InterceptorInterface{},
+	}
+	s.RegisterInterceptor("bitbucket", bitbucket.NewInterceptor(k, l))
+	s.RegisterInterceptor("cel", cel.NewInterceptor(k, l))
+	s.RegisterInterceptor("github", github.NewInterceptor(k, l))
+	s.RegisterInterceptor("gitlab", gitlab.NewInterceptor(k, l))
+	return s
+}
+
+// RegisterInterceptor serves the interceptor i under /name.
+func (s *Server) RegisterInterceptor(name string, i triggersv1.InterceptorInterface) {
+	if s.interceptors == nil {
+		s.interceptors = map[string]triggersv1.InterceptorInterface{}
+	}
+	s.interceptors[name] = i
+}
+
+// ServeHTTP runs the interceptor of the request path on the
+// InterceptorRequest of the body.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, "/")
+	i, ok := s.interceptors[name]
+	if !ok {
+		http.Error(w, fmt.Sprintf("no interceptor %q", name), http.StatusNotFound)
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "interceptors only accept POST requests", http.StatusMethodNotAllowed)
+		return
+	}
+	var callerNamespace string
+	if s.Authenticator != nil {
+		var err error
+		callerNamespace, err = s.Authenticator.Authenticate(r)
+		if err != nil {
+			s.Logger.Infof("Rejected request to interceptor %s: %s", name, err)
+			code := http.StatusInternalServerError
+			if errors.Is(err, errUnauthenticated) {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				code = http.StatusUnauthorized
+			}
+			http.Error(w, err.Error(), code)
+			return
+		}
+	}
+
+	var req triggersv1.InterceptorRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("failed to decode the interceptor request: %v", err), http.StatusBadRequest)
+		return
+	}
+	if req.Context == nil {
+		req.Context = &triggersv1.TriggerContext{}
+	}
+	if s.Authenticator != nil {
+		if ns, _ := triggersv1.ParseTriggerID(req.Context.TriggerID); ns != callerNamespace {
+			http.Error(w, fmt.Sprintf("callers in namespace %s cannot intercept the events of Trigger %q", callerNamespace, req.Context.TriggerID), http.StatusForbidden)
+			return
+		}
+	}
+
+	ctx, span := tracing.StartSpanFromRequest(r, "Interceptor")
+	defer span.End()
+	if s.Secrets != nil {
+		ctx = interceptors.WithSecretCache(ctx, s.Secrets)
+	}
+	res := i.Process(ctx, &req)
+	if !res.Continue {
+		s.Logger.Debugf("Interceptor %s stopped Trigger %s of event %s: %v", name, req.Context.TriggerID, req.Context.EventID, res.Status.Err())
+	}
+
+	b, err := json.Marshal(res)
+	if err != nil {
+		s.Logger.Errorf("Failed to encode the response of interceptor %s: %v", name, err)
+		http.Error(w, fmt.Sprintf("failed to encode the interceptor response: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write(b); err != nil {
+		s.Logger.Errorf("Failed to write the response of interceptor %s: %v", name, err)
+	}
+}
+
+// Readiness reports the interceptors ready once the Kubernetes API server,
+// which they read secrets from, has been reached.
+type Readiness struct {
+	ready int32
+}
+
+// Check polls the Kubernetes API server with k every interval until it
+// responds or ctx is done.
+func (r *Readiness) Check(ctx context.Context, k kubernetes.Interface, interval time.Duration) {
+	_ = wait.PollImmediateUntil(interval, func() (bool, error) {
+		if _, err := k.Discovery().ServerVersion(); err != nil {
+			return false, nil
+		}
+		atomic.StoreInt32(&r.ready, 1)
+		return true, nil
+	}, ctx.Done())
+}
+
+func (r *Readiness) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	if atomic.LoadInt32(&r.ready) == 0 {
+		http.Error(w, "Kubernetes API server not reached", http.StatusServiceUnavailable)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, "ok")
+}
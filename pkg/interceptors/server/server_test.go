@@ -0,0 +1,293 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	triggersv1 "github.com/tektoncd/triggers/pkg/apis/triggers/v1alpha1"
+	"github.com/tektoncd/triggers/pkg/interceptors"
+	"go.uber.org/zap/zaptest"
+	"google.golang.org/grpc/codes"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ktesting "k8s.io/client-go/testing"
+	fakekubeclient "knative.dev/pkg/client/injection/kube/client/fake"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestServer(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	kubeClient := fakekubeclient.Get(ctx)
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "github-secret"},
+		Data:       map[string][]byte{"token": []byte("secret")},
+	}
+	if _, err := kubeClient.CoreV1().Secrets("default").Create(ctx, secret, metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(NewWithCoreInterceptors(kubeClient, interceptors.NewSecretCache(time.Minute), zaptest.NewLogger(t).Sugar()))
+	defer srv.Close()
+
+	body := []byte(`{"ref": "refs/heads/main"}`)
+	mac := hmac.New(sha1.New, []byte("secret"))
+	_, _ = mac.Write(body)
+	triggerContext := &triggersv1.TriggerContext{TriggerID: "namespaces/default/triggers/push"}
+
+	tests := []struct {
+		name           string
+		path           string
+		req            *triggersv1.InterceptorRequest
+		wantContinue   bool
+		wantCode       codes.Code
+		wantExtensions map[string]interface{}
+	}{{
+		name: "github",
+		path: "/github",
+		req: &triggersv1.InterceptorRequest{
+			Body: body,
+			Header: map[string][]string{
+				"X-Github-Event":  {"push"},
+				"X-Hub-Signature": {"sha1=" + hex.EncodeToString(mac.Sum(nil))},
+			},
+			InterceptorParams: map[string]interface{}{
+				"secretRef":  map[string]interface{}{"secretName": "github-secret", "secretKey": "token"},
+				"eventTypes": []interface{}{"push"},
+			},
+			Context: triggerContext,
+		},
+		wantContinue: true,
+		wantCode:     codes.OK,
+		wantExtensions: map[string]interface{}{
+			"github": map[string]interface{}{"event_type": "push", "delivery_id": "", "changed_files": ""},
+		},
+	}, {
+		name: "github with an invalid signature",
+		path: "/github",
+		req: &triggersv1.InterceptorRequest{
+			Body: body,
+			Header: map[string][]string{
+				"X-Github-Event":  {"push"},
+				"X-Hub-Signature": {"sha1=invalid"},
+			},
+			InterceptorParams: map[string]interface{}{
+				"secretRef": map[string]interface{}{"secretName": "github-secret", "secretKey": "token"},
+			},
+			Context: triggerContext,
+		},
+		wantCode: codes.Unauthenticated,
+	}, {
+		name: "cel",
+		path: "/cel",
+		req: &triggersv1.InterceptorRequest{
+			Body: body,
+			InterceptorParams: map[string]interface{}{
+				"filter": `body.ref == "refs/heads/main"`,
+				"overlays": []interface{}{
+					map[string]interface{}{"key": "branch", "expression": `body.ref.split("/")[2]`},
+				},
+			},
+			Context: triggerContext,
+		},
+		wantContinue:   true,
+		wantCode:       codes.OK,
+		wantExtensions: map[string]interface{}{"branch": "main"},
+	}, {
+		name: "cel without a context",
+		path: "/cel",
+		req: &triggersv1.InterceptorRequest{
+			Body:              body,
+			InterceptorParams: map[string]interface{}{"filter": `body.ref == "refs/heads/release"`},
+		},
+		wantCode: codes.FailedPrecondition,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := json.Marshal(tt.req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := http.Post(srv.URL+tt.path, "application/json", bytes.NewReader(b))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("POST %s status = %d, want %d", tt.path, resp.StatusCode, http.StatusOK)
+			}
+			var res triggersv1.InterceptorResponse
+			if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+				t.Fatalf("Error decoding the interceptor response: %v", err)
+			}
+			if res.Continue != tt.wantContinue || res.Status.Code() != tt.wantCode {
+				t.Errorf("POST %s response = %+v, want continue %t and code %s", tt.path, res, tt.wantContinue, tt.wantCode)
+			}
+			if diff := cmp.Diff(tt.wantExtensions, res.Extensions); diff != "" {
+				t.Errorf("POST %s extensions (-want +got): %s", tt.path, diff)
+			}
+		})
+	}
+}
+
+func TestServer_Error(t *testing.T) {
+	s := &Server{Logger: zaptest.NewLogger(t).Sugar()}
+	s.RegisterInterceptor("cel", nil)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		want   int
+	}{{
+		name:   "unknown interceptor",
+		method: http.MethodPost,
+		path:   "/webhook",
+		body:   `{}`,
+		want:   http.StatusNotFound,
+	}, {
+		name:   "not a POST",
+		method: http.MethodGet,
+		path:   "/cel",
+		want:   http.StatusMethodNotAllowed,
+	}, {
+		name:   "invalid request",
+		method: http.MethodPost,
+		path:   "/cel",
+		body:   `{"body": "not base64"}`,
+		want:   http.StatusBadRequest,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			s.ServeHTTP(rec, httptest.NewRequest(tt.method, (&url.URL{Path: tt.path}).String(), strings.NewReader(tt.body)))
+			if rec.Code != tt.want {
+				t.Errorf("ServeHTTP() status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestServer_Authentication(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	kubeClient := fakekubeclient.Get(ctx)
+	reviews := 0
+	kubeClient.PrependReactor("create", "tokenreviews", func(action ktesting.Action) (bool, runtime.Object, error) {
+		reviews++
+		review := action.(ktesting.CreateAction).GetObject().(*authenticationv1.TokenReview)
+		if len(review.Spec.Audiences) != 1 || review.Spec.Audiences[0] != interceptors.TokenAudience {
+			t.Errorf("TokenReview audiences = %v, want [%s]", review.Spec.Audiences, interceptors.TokenAudience)
+		}
+		switch review.Spec.Token {
+		case "default":
+			review.Status.Authenticated = true
+			review.Status.User.Username = "system:serviceaccount:default:el"
+		case "other":
+			review.Status.Authenticated = true
+			review.Status.User.Username = "system:serviceaccount:other:el"
+		case "user":
+			review.Status.Authenticated = true
+			review.Status.User.Username = "jane"
+		default:
+			review.Status.Error = "invalid token"
+		}
+		return true, review, nil
+	})
+	logger := zaptest.NewLogger(t).Sugar()
+	s := NewWithCoreInterceptors(kubeClient, nil, logger)
+	s.Authenticator = NewTokenReviewAuthenticator(kubeClient, interceptors.TokenAudience, time.Minute)
+
+	body := `{"body": "e30=", "interceptor_params": {"filter": "true"}, "context": {"trigger_id": "namespaces/default/triggers/push"}}`
+	tests := []struct {
+		name  string
+		token string
+		want  int
+	}{{
+		name: "no token",
+		want: http.StatusUnauthorized,
+	}, {
+		name:  "invalid token",
+		token: "invalid",
+		want:  http.StatusUnauthorized,
+	}, {
+		name:  "not a ServiceAccount",
+		token: "user",
+		want:  http.StatusUnauthorized,
+	}, {
+		name:  "Trigger in another namespace",
+		token: "other",
+		want:  http.StatusForbidden,
+	}, {
+		name:  "Trigger in the namespace of the caller",
+		token: "default",
+		want:  http.StatusOK,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/cel", strings.NewReader(body))
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			rec := httptest.NewRecorder()
+			s.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("ServeHTTP() status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+		})
+	}
+
+	// The namespace of a token is kept once reviewed
+	reviews = 0
+	req := httptest.NewRequest(http.MethodPost, "/cel", strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer default")
+	s.ServeHTTP(httptest.NewRecorder(), req)
+	if reviews != 0 {
+		t.Errorf("reviewed the token %d times, want it kept from its first review", reviews)
+	}
+}
+
+func TestReadiness(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	kubeClient := fakekubeclient.Get(ctx)
+	readiness := &Readiness{}
+
+	rec := httptest.NewRecorder()
+	readiness.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("ServeHTTP() status = %d before reaching the API server, want %d", rec.Code, http.StatusServiceUnavailable)
+	}
+
+	readiness.Check(ctx, kubeClient, time.Millisecond)
+	rec = httptest.NewRecorder()
+	readiness.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("ServeHTTP() status = %d once the API server was reached, want %d", rec.Code, http.StatusOK)
+	}
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package interceptors
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tektoncd/triggers/pkg/tracing"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// maxTokenReviews bounds the number of reviews that a TokenReviewCache keeps,
+// so that requests with many different tokens do not grow it without limit.
+const maxTokenReviews = 10000
+
+// TokenReviewCache reviews tokens with TokenReviews, keeping the outcome of
+// each review for a while so that a TokenReview is not created for every
+// request sending the same token.
+type TokenReviewCache struct {
+	kubeClient kubernetes.Interface
+	ttl        time.Duration
+	now        func() time.Time
+
+	mu      sync.Mutex
+	reviews *lru
+}
+
+type tokenReview struct {
+	status  authenticationv1.TokenReviewStatus
+	expires time.Time
+}
+
+// NewTokenReviewCache returns a TokenReviewCache reviewing tokens with k,
+// and keeping the outcome of their reviews for ttl.
+func NewTokenReviewCache(k kubernetes.Interface, ttl time.Duration) *TokenReviewCache {
+	return &TokenReviewCache{
+		kubeClient: k,
+		ttl:        ttl,
+		now:        time.Now,
+		reviews:    newLRU(maxTokenReviews, nil),
+	}
+}
+
+// Review returns the status of the review of the token for the audiences.
+// It only returns an error when the token could not be reviewed, and the
+// status tells whether the token is authenticated.
+func (c *TokenReviewCache) Review(ctx context.Context, token string, audiences []string) (authenticationv1.TokenReviewStatus, error) {
+	// Only the hash of the token is kept
+	key := sha256.Sum256([]byte(strings.Join(audiences, ",") + "\n" + token))
+	now := c.now()
+	c.mu.Lock()
+	if v, ok := c.reviews.get(key); ok && now.Before(v.(tokenReview).expires) {
+		c.mu.Unlock()
+		return v.(tokenReview).status, nil
+	}
+	c.mu.Unlock()
+
+	ctx, span := tracing.StartSpan(ctx, "TokenReview")
+	tr, err := c.kubeClient.AuthenticationV1().TokenReviews().Create(ctx, &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{
+			Token:     token,
+			Audiences: audiences,
+		},
+	}, metav1.CreateOptions{})
+	tracing.EndSpan(span, err)
+	if err != nil {
+		return authenticationv1.TokenReviewStatus{}, fmt.Errorf("failed to review the token: %w", err)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.reviews.add(key, tokenReview{status: tr.Status, expires: now.Add(c.ttl)})
+	return tr.Status, nil
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package interceptors
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakekubeclient "k8s.io/client-go/kubernetes/fake"
+	ktesting "k8s.io/client-go/testing"
+)
+
+func TestTokenReviewCache(t *testing.T) {
+	kubeClient := fakekubeclient.NewSimpleClientset()
+	reviews := 0
+	kubeClient.PrependReactor("create", "tokenreviews", func(action ktesting.Action) (bool, runtime.Object, error) {
+		reviews++
+		review := action.(ktesting.CreateAction).GetObject().(*authenticationv1.TokenReview)
+		review.Status.Authenticated = review.Spec.Token == "valid"
+		return true, review, nil
+	})
+
+	now := time.Now()
+	c := NewTokenReviewCache(kubeClient, time.Minute)
+	c.now = func() time.Time { return now }
+	review := func(token string, audiences ...string) bool {
+		t.Helper()
+		status, err := c.Review(context.Background(), token, audiences)
+		if err != nil {
+			t.Fatalf("Review() unexpected error: %v", err)
+		}
+		return status.Authenticated
+	}
+
+	for i := 0; i < 3; i++ {
+		if !review("valid", "el") {
+			t.Errorf("Review() of a valid token is not authenticated")
+		}
+		if review("invalid", "el") {
+			t.Errorf("Review() of an invalid token is authenticated")
+		}
+	}
+	if reviews != 2 {
+		t.Errorf("created %d TokenReviews, want each token reviewed once", reviews)
+	}
+	// The outcome of a review only holds for its audiences
+	review("valid", "other")
+	if reviews != 3 {
+		t.Errorf("created %d TokenReviews, want the token reviewed again for another audience", reviews)
+	}
+	now = now.Add(time.Minute)
+	review("valid", "el")
+	if reviews != 4 {
+		t.Errorf("created %d TokenReviews, want the token reviewed again once its review expired", reviews)
+	}
+}
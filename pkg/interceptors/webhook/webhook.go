@@ -17,16 +17,23 @@ limitations under the License.
 package webhook
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"net/url"
 	"time"
 
 	pipelinev1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 	"github.com/tektoncd/triggers/pkg/interceptors"
+	"github.com/tektoncd/triggers/pkg/tracing"
+	"google.golang.org/grpc/codes"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
 
 	triggersv1 "github.com/tektoncd/triggers/pkg/apis/triggers/v1alpha1"
 
@@ -36,52 +43,306 @@ import (
 const (
 	// Timeout for outgoing requests to interceptor services
 	interceptorTimeout = 5 * time.Second
+	// defaultMaxAttempts is the number of attempts at sending an event when
+	// the interceptor has a retry policy that does not set it.
+	defaultMaxAttempts = 3
+	// defaultRetryBackoff is the delay before retrying to send an event the
+	// first time.
+	defaultRetryBackoff = 100 * time.Millisecond
+	// maxRetryBackoff is the longest delay between two attempts at sending
+	// an event.
+	maxRetryBackoff = 5 * time.Second
+	// defaultFailureThreshold is the number of consecutive failures opening
+	// the circuit breaker of an interceptor.
+	defaultFailureThreshold = 5
+	// defaultOpenDuration is how long the circuit breaker of an interceptor
+	// stays open.
+	defaultOpenDuration = 30 * time.Second
 	// the incoming request URL is passed through to the webhook in this header.
 	webhookURLHeader = "EventListener-Request-URL"
+
+	// The keys of the certificates in the Secrets of the TLS configuration
+	caBundleKey   = "ca.crt"
+	clientCertKey = corev1.TLSCertKey
+	clientKeyKey  = corev1.TLSPrivateKeyKey
 )
 
+var _ triggersv1.InterceptorInterface = (*Interceptor)(nil)
+
+// Interceptor sends events to the URL or Kubernetes Service of a
+// WebhookInterceptor.
 type Interceptor struct {
-	HTTPClient             *http.Client
-	EventListenerNamespace string
-	Logger                 *zap.SugaredLogger
-	Webhook                *triggersv1.WebhookInterceptor
+	Clients       *interceptors.Clients
+	KubeClientSet kubernetes.Interface
+	Logger        *zap.SugaredLogger
 }
 
-func NewInterceptor(wh *triggersv1.WebhookInterceptor, c *http.Client, ns string, l *zap.SugaredLogger) interceptors.Interceptor {
-	timeoutClient := &http.Client{
-		Transport: c.Transport,
-		Timeout:   interceptorTimeout,
-	}
+// NewInterceptor creates a prepopulated Interceptor.
+func NewInterceptor(clients *interceptors.Clients, k kubernetes.Interface, l *zap.SugaredLogger) *Interceptor {
 	return &Interceptor{
-		HTTPClient:             timeoutClient,
-		EventListenerNamespace: ns,
-		Logger:                 l,
-		Webhook:                wh,
+		Clients:       clients,
+		KubeClientSet: k,
+		Logger:        l,
 	}
 }
 
-func (w *Interceptor) ExecuteTrigger(request *http.Request) (*http.Response, error) {
-	u, err := getURI(w.Webhook.ObjectRef, w.EventListenerNamespace) // TODO: Cache this result or do this on initialization
+// Process sends the event to the WebhookInterceptor of the request's
+// InterceptorParams as Intercept does, dropping the body and header that the
+// interceptor responds with.
+func (w *Interceptor) Process(ctx context.Context, r *triggersv1.InterceptorRequest) *triggersv1.InterceptorResponse {
+	_, _, res := w.Intercept(ctx, r)
+	return res
+}
+
+// Intercept sends the event to the WebhookInterceptor of the request's
+// InterceptorParams, which lets the Trigger continue by responding with 200
+// OK. Unlike other interceptors, the body and header that the interceptor
+// responds with are returned to replace those of the event, so that the rest
+// of the chain and the bindings see the event as the interceptor returned it.
+//
+// The interceptor is unavailable when it responds with a 5xx status code,
+// times out or cannot be reached. The event is then sent again as its retry
+// policy allows, and the Trigger fails unless its failure policy is Ignore,
+// in which case the body and header of the request are returned unchanged.
+// Events are not sent to interceptors whose circuit breaker is open.
+func (w *Interceptor) Intercept(ctx context.Context, r *triggersv1.InterceptorRequest) ([]byte, http.Header, *triggersv1.InterceptorResponse) {
+	if r.Context == nil {
+		return nil, nil, interceptors.Failf(codes.InvalidArgument, "no trigger context in the interceptor request")
+	}
+	p := triggersv1.WebhookInterceptor{}
+	if err := interceptors.UnmarshalParams(r.InterceptorParams, &p); err != nil {
+		return nil, nil, interceptors.Failf(codes.InvalidArgument, "failed to parse interceptor params: %v", err)
+	}
+	ns, _ := triggersv1.ParseTriggerID(r.Context.TriggerID)
+	u, err := getURL(&p, ns) // TODO: Cache this result or do this on initialization
+	if err != nil {
+		return nil, nil, interceptors.Failf(codes.InvalidArgument, "failed to get the URI of the interceptor: %v", err)
+	}
+	client, err := w.client(ctx, p.TLS, ns)
 	if err != nil {
-		return nil, err
+		return nil, nil, interceptors.Failf(codes.Internal, "failed to configure the TLS connection to the interceptor: %v", err)
 	}
-	request.Header.Set(webhookURLHeader, request.URL.String())
-	request.URL = u
-	request.Host = u.Host
-	addInterceptorHeaders(request.Header, w.Webhook.Header)
+	timeout := interceptorTimeout
+	if p.Timeout != nil {
+		timeout = p.Timeout.Duration
+	}
+	client = &http.Client{
+		Transport: client.Transport,
+		Timeout:   timeout,
+	}
+
+	breaker := w.Clients.Breaker(u.String(), circuitBreakerConfig(p.CircuitBreaker))
+	var body []byte
+	var header http.Header
+	var res *triggersv1.InterceptorResponse
+	if !breaker.Allow() {
+		res = interceptors.Failf(codes.Unavailable, "circuit breaker of the interceptor %s is open after consecutive failures", u.Host)
+	} else {
+		body, header, res = w.sendWithRetries(ctx, client, u, r, &p, timeout, breaker)
+	}
+	if res != nil {
+		if unavailable(res) && p.FailurePolicy == triggersv1.WebhookFailurePolicyIgnore {
+			w.Logger.Warnf("Ignoring the unavailable interceptor %s: %s", u.Host, res.Status.Message())
+			return r.Body, http.Header(r.Header), &triggersv1.InterceptorResponse{Continue: true}
+		}
+		return nil, nil, res
+	}
+	return body, header, &triggersv1.InterceptorResponse{Continue: true}
+}
 
-	resp, err := w.HTTPClient.Do(request)
+// sendWithRetries sends the event of r to the interceptor at u as the retry
+// policy of p allows, and records the outcome of the last attempt with
+// breaker. It returns the body and header of the interceptor's 200 OK
+// response, or the response failing the request.
+func (w *Interceptor) sendWithRetries(ctx context.Context, client *http.Client, u *url.URL, r *triggersv1.InterceptorRequest, p *triggersv1.WebhookInterceptor, timeout time.Duration, breaker *interceptors.CircuitBreaker) ([]byte, http.Header, *triggersv1.InterceptorResponse) {
+	backoff := retryBackoff(p.Retry)
+	maxAttempts := backoff.Steps
+	for attempt := 1; ; attempt++ {
+		body, header, res := w.send(ctx, client, u, r, p.Header, timeout)
+		if ctx.Err() != nil {
+			// The event was abandoned, which says nothing of the interceptor
+			return body, header, res
+		}
+		if res == nil || !unavailable(res) {
+			breaker.Success()
+			return body, header, res
+		}
+		if attempt >= maxAttempts {
+			breaker.Failure()
+			return nil, nil, res
+		}
+		delay := backoff.Step()
+		w.Logger.Infof("Retrying to send the event to the interceptor %s after %s (attempt %d of %d): %s", u.Host, delay, attempt+1, maxAttempts, res.Status.Message())
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, nil, res
+		case <-timer.C:
+		}
+	}
+}
+
+// send sends the event of r to the interceptor at u once. It returns the body
+// and header of the interceptor's 200 OK response, or the response failing
+// the request.
+func (w *Interceptor) send(ctx context.Context, client *http.Client, u *url.URL, r *triggersv1.InterceptorRequest, headers []pipelinev1.Param, timeout time.Duration) ([]byte, http.Header, *triggersv1.InterceptorResponse) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), bytes.NewReader(r.Body))
+	if err != nil {
+		return nil, nil, interceptors.Failf(codes.Internal, "failed to create the request to the interceptor: %v", err)
+	}
+	// The event is sent without a Content-Length, as it always was, so that
+	// webhooks echoing the header of the request do not declare the wrong
+	// length for their response.
+	request.ContentLength = -1
+	if r.Header != nil {
+		request.Header = http.Header(r.Header).Clone()
+	}
+	request.Header.Set(webhookURLHeader, r.Context.EventURL)
+	addInterceptorHeaders(request.Header, headers)
+	// Continue the trace in the webhook without passing the traceparent on
+	// to the rest of the chain
+	tracing.Inject(ctx, request)
+
+	resp, err := client.Do(request)
+	if err != nil {
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			return nil, nil, interceptors.Failf(codes.DeadlineExceeded, "interceptor did not respond within %s: %v", timeout, err)
+		}
+		return nil, nil, interceptors.Failf(codes.Unavailable, "failed to send the event to the interceptor: %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return resp, err
+		return nil, nil, interceptors.Failf(codes.Unavailable, "failed to read the response of the interceptor: %v", err)
+	}
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return nil, nil, interceptors.Failf(codes.Unavailable, "interceptor responded with status: %s; message: %s", resp.Status, body)
 	}
 	if resp.StatusCode != http.StatusOK {
-		respBody, err := ioutil.ReadAll(resp.Body)
+		return nil, nil, interceptors.Failf(codes.FailedPrecondition, "request rejected; status: %s; message: %s", resp.Status, body)
+	}
+	return body, resp.Header.Clone(), nil
+}
+
+// unavailable reports whether res failed a request because the interceptor
+// was unavailable, in which case the request may be retried.
+func unavailable(res *triggersv1.InterceptorResponse) bool {
+	code := res.Status.Code()
+	return code == codes.Unavailable || code == codes.DeadlineExceeded
+}
+
+// retryBackoff returns the backoff of the attempts at sending an event under
+// policy. Its Steps are the maximum number of attempts, which is 1 when
+// policy is nil.
+func retryBackoff(policy *triggersv1.WebhookRetryPolicy) wait.Backoff {
+	backoff := wait.Backoff{
+		Duration: defaultRetryBackoff,
+		Factor:   2,
+		Jitter:   0.1,
+		Steps:    1,
+		Cap:      maxRetryBackoff,
+	}
+	if policy != nil {
+		backoff.Steps = defaultMaxAttempts
+		if policy.MaxAttempts > 0 {
+			backoff.Steps = int(policy.MaxAttempts)
+		}
+		if policy.Backoff != nil {
+			backoff.Duration = policy.Backoff.Duration
+		}
+	}
+	return backoff
+}
+
+// circuitBreakerConfig returns the configuration of the circuit breaker c,
+// which may be nil for the defaults.
+func circuitBreakerConfig(c *triggersv1.WebhookCircuitBreaker) interceptors.CircuitBreakerConfig {
+	config := interceptors.CircuitBreakerConfig{
+		FailureThreshold: defaultFailureThreshold,
+		OpenDuration:     defaultOpenDuration,
+	}
+	if c != nil {
+		if c.FailureThreshold > 0 {
+			config.FailureThreshold = int(c.FailureThreshold)
+		}
+		if c.OpenDuration != nil {
+			config.OpenDuration = c.OpenDuration.Duration
+		}
+	}
+	return config
+}
+
+// client returns the client connecting to the interceptor with the
+// certificates of the Secrets of t in the namespace ns.
+func (w *Interceptor) client(ctx context.Context, t *triggersv1.WebhookTLS, ns string) (*http.Client, error) {
+	certs := interceptors.TLS{}
+	if t != nil && t.CASecretName != "" {
+		v, err := w.secretValue(ctx, t.CASecretName, caBundleKey, ns)
 		if err != nil {
-			return resp, errors.New("failed to parse response body")
+			return nil, err
 		}
-		return resp, fmt.Errorf("request rejected; status: %s; message: %s", resp.Status, respBody)
+		certs.CABundle = v
+	}
+	if t != nil && t.ClientCertSecretName != "" {
+		cert, err := w.secretValue(ctx, t.ClientCertSecretName, clientCertKey, ns)
+		if err != nil {
+			return nil, err
+		}
+		key, err := w.secretValue(ctx, t.ClientCertSecretName, clientKeyKey, ns)
+		if err != nil {
+			return nil, err
+		}
+		certs.ClientCert, certs.ClientKey = cert, key
+	}
+	return w.Clients.ForTLS(certs)
+}
+
+func (w *Interceptor) secretValue(ctx context.Context, name, key, ns string) ([]byte, error) {
+	v, err := interceptors.GetSecretTokenFromContext(ctx, w.KubeClientSet, &triggersv1.SecretRef{SecretName: name, SecretKey: key}, ns)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Secret %s: %w", name, err)
+	}
+	if len(v) == 0 {
+		return nil, fmt.Errorf("no %s in Secret %s", key, name)
+	}
+	return v, nil
+}
+
+// getURL returns the URL of the interceptor p, whose Service is in the
+// namespace ns unless its reference sets another one. Services are reached
+// over HTTPS when p configures TLS.
+func getURL(p *triggersv1.WebhookInterceptor, ns string) (*url.URL, error) {
+	var u *url.URL
+	switch {
+	case p.URL != nil:
+		v := url.URL(*p.URL)
+		return &v, nil
+	case p.Service != nil:
+		if p.Service.Namespace != "" {
+			ns = p.Service.Namespace
+		}
+		u = &url.URL{
+			Scheme: "http",
+			Host:   fmt.Sprintf("%s.%s.svc", p.Service.Name, ns),
+			Path:   p.Service.Path,
+		}
+		if p.Service.Port != nil {
+			u.Host = fmt.Sprintf("%s:%d", u.Host, *p.Service.Port)
+		}
+	case p.ObjectRef != nil:
+		var err error
+		if u, err = getURI(p.ObjectRef, ns); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.New("no objectRef, url or service set")
+	}
+	if p.TLS != nil {
+		u.Scheme = "https"
 	}
-	return resp, err
+	return u, nil
 }
 
 // getURI retrieves the ObjectReference to URI.
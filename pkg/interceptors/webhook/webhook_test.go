@@ -18,23 +18,39 @@ package webhook
 
 import (
 	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/json"
+	"encoding/pem"
 	"io/ioutil"
+	"math/big"
 	"net/http"
 	"net/http/httptest"
-	"net/http/httputil"
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	pipelinev1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 	"github.com/tektoncd/triggers/pkg/apis/triggers/v1alpha1"
+	"github.com/tektoncd/triggers/pkg/interceptors"
+	"go.uber.org/zap/zaptest"
+	"google.golang.org/grpc/codes"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+	fakekubeclient "knative.dev/pkg/client/injection/kube/client/fake"
+	"knative.dev/pkg/ptr"
+	rtesting "knative.dev/pkg/reconciler/testing"
 )
 
 func TestWebHookInterceptor(t *testing.T) {
-	payload := new(bytes.Buffer)
-	_ = json.NewEncoder(payload).Encode(map[string]string{
+	payload, _ := json.Marshal(map[string]string{
 		"eventType": "push",
 		"foo":       "bar",
 	})
@@ -54,6 +70,10 @@ func TestWebHookInterceptor(t *testing.T) {
 			http.Error(w, "Expected webhookURLHeader does not match", http.StatusBadRequest)
 			return
 		}
+		if body, _ := ioutil.ReadAll(r.Body); !bytes.Equal(body, payload) {
+			http.Error(w, "Expected body does not match", http.StatusBadRequest)
+			return
+		}
 		// Return new values back in the response. It is expected for interceptors
 		// to be able to mutate the request.
 		w.Header().Set("Foo", "bar")
@@ -81,39 +101,55 @@ func TestWebHookInterceptor(t *testing.T) {
 			}},
 		},
 	}
-	i := NewInterceptor(webhook, client, "default", nil)
+	i := NewInterceptor(interceptors.NewClients(client), nil, nil)
 
-	incoming, _ := http.NewRequest("POST", "http://doesnotmatter.example.com", payload)
-	incoming.Header.Add("Content-type", "application/json")
-	resp, err := i.ExecuteTrigger(incoming)
-	if err != nil {
-		t.Fatalf("ExecuteTrigger: %v", err)
+	req := &v1alpha1.InterceptorRequest{
+		Body: payload,
+		Header: http.Header{
+			"Content-Type": []string{"application/json"},
+		},
+		InterceptorParams: interceptors.GetInterceptorParams(&v1alpha1.EventInterceptor{Webhook: webhook}),
+		Context: &v1alpha1.TriggerContext{
+			EventURL:  "http://doesnotmatter.example.com",
+			TriggerID: "namespaces/default/triggers/example-trigger",
+		},
 	}
-
-	resPayload, _ := ioutil.ReadAll(resp.Body)
-	defer resp.Body.Close()
-	if diff := cmp.Diff(wantPayload, resPayload); diff != "" {
-		t.Errorf("response payload (-want, +got) = %s", diff)
+	body, header, res := i.Intercept(context.Background(), req)
+	if !res.Continue {
+		t.Fatalf("Intercept() unexpectedly stopped the Trigger: %v", res.Status.Err())
 	}
 
-	// Check headers.
+	// The body and header of the response are returned to replace those of
+	// the event.
+	if diff := cmp.Diff(wantPayload, body); diff != "" {
+		t.Errorf("intercepted payload (-want, +got) = %s", diff)
+	}
+	if diff := cmp.Diff(payload, req.Body); diff != "" {
+		t.Errorf("Intercept() changed the payload of the request (-want, +got) = %s", diff)
+	}
 	for k, v := range map[string]string{
+		"Content-Type": "text/plain; charset=utf-8",
 		"Param-Header": "",
 		"Foo":          "bar",
 	} {
-		if s := resp.Header.Get(k); s != v {
+		if s := header.Get(k); s != v {
 			t.Errorf("Header[%s] = %s, want %s", k, s, v)
 		}
 	}
 }
 
-func TestWebHookInterceptor_NotOK(t *testing.T) {
-	payload := new(bytes.Buffer)
-	_ = json.NewEncoder(payload).Encode(map[string]string{
-		"eventType": "push",
-		"foo":       "bar",
-	})
+func TestWebHookInterceptor_NoContext(t *testing.T) {
+	u, _ := apis.ParseURL("http://doesnotmatter.example.com")
+	req := &v1alpha1.InterceptorRequest{
+		InterceptorParams: interceptors.GetInterceptorParams(&v1alpha1.EventInterceptor{Webhook: &v1alpha1.WebhookInterceptor{URL: u}}),
+	}
+	res := NewInterceptor(interceptors.NewClients(http.DefaultClient), nil, nil).Process(context.Background(), req)
+	if res.Continue || res.Status.Code() != codes.InvalidArgument {
+		t.Errorf("Process() = %+v, want a status with code %s", res, codes.InvalidArgument)
+	}
+}
 
+func TestWebHookInterceptor_NotOK(t *testing.T) {
 	// Create test server
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusAccepted)
@@ -126,22 +162,419 @@ func TestWebHookInterceptor_NotOK(t *testing.T) {
 			Proxy: http.ProxyURL(interceptorURL),
 		},
 	}
+	i := NewInterceptor(interceptors.NewClients(client), nil, nil)
+
+	for _, tc := range []struct {
+		name    string
+		webhook *v1alpha1.WebhookInterceptor
+		want    codes.Code
+	}{{
+		name: "rejected",
+		webhook: &v1alpha1.WebhookInterceptor{
+			ObjectRef: &corev1.ObjectReference{
+				APIVersion: "v1",
+				Kind:       "Service",
+				Name:       "foo",
+			},
+		},
+		want: codes.FailedPrecondition,
+	}, {
+		name:    "no objectRef",
+		webhook: &v1alpha1.WebhookInterceptor{},
+		want:    codes.InvalidArgument,
+	}, {
+		name: "not a Service",
+		webhook: &v1alpha1.WebhookInterceptor{
+			ObjectRef: &corev1.ObjectReference{
+				APIVersion: "v1",
+				Kind:       "Pod",
+				Name:       "foo",
+			},
+		},
+		want: codes.InvalidArgument,
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			req := &v1alpha1.InterceptorRequest{
+				Body:              []byte(`{"eventType": "push"}`),
+				Header:            http.Header{},
+				InterceptorParams: interceptors.GetInterceptorParams(&v1alpha1.EventInterceptor{Webhook: tc.webhook}),
+				Context: &v1alpha1.TriggerContext{
+					EventURL:  "http://doesnotmatter.example.com",
+					TriggerID: "namespaces/default/triggers/example-trigger",
+				},
+			}
+			body, _, res := i.Intercept(context.Background(), req)
+			if res.Continue || res.Status.Code() != tc.want {
+				t.Fatalf("Intercept() = %+v, want a status with code %s", res, tc.want)
+			}
+			if body != nil {
+				t.Errorf("Intercept() returned the payload %s for a rejected request", body)
+			}
+		})
+	}
+}
+
+func TestWebHookInterceptor_URL(t *testing.T) {
+	tests := []struct {
+		name    string
+		webhook *v1alpha1.WebhookInterceptor
+		want    string
+	}{{
+		name: "url",
+		webhook: &v1alpha1.WebhookInterceptor{
+			URL: apis.HTTP("scanner.example.com:8080").ResolveReference(&apis.URL{Path: "/scan"}),
+		},
+		want: "http://scanner.example.com:8080/scan",
+	}, {
+		name: "service",
+		webhook: &v1alpha1.WebhookInterceptor{
+			Service: &v1alpha1.ServiceReference{Name: "scanner", Port: ptr.Int32(8080), Path: "/scan"},
+		},
+		want: "http://scanner.default.svc:8080/scan",
+	}, {
+		name: "service in another namespace",
+		webhook: &v1alpha1.WebhookInterceptor{
+			Service: &v1alpha1.ServiceReference{Name: "scanner", Namespace: "scanners"},
+		},
+		want: "http://scanner.scanners.svc/",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.URL.String()
+			}))
+			defer ts.Close()
+			interceptorURL, _ := url.Parse(ts.URL)
+			// Proxy all requests through test server.
+			client := &http.Client{
+				Transport: &http.Transport{
+					Proxy: http.ProxyURL(interceptorURL),
+				},
+			}
+			req := &v1alpha1.InterceptorRequest{
+				InterceptorParams: interceptors.GetInterceptorParams(&v1alpha1.EventInterceptor{Webhook: tt.webhook}),
+				Context: &v1alpha1.TriggerContext{
+					TriggerID: "namespaces/default/triggers/example-trigger",
+				},
+			}
+			res := NewInterceptor(interceptors.NewClients(client), nil, nil).Process(context.Background(), req)
+			if !res.Continue {
+				t.Fatalf("Process() unexpectedly stopped the Trigger: %v", res.Status.Err())
+			}
+			if got != tt.want {
+				t.Errorf("Process() sent the event to %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWebHookInterceptor_Timeout(t *testing.T) {
+	done := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	defer ts.Close()
+	defer close(done)
+
+	u, _ := apis.ParseURL(ts.URL)
 	webhook := &v1alpha1.WebhookInterceptor{
-		ObjectRef: &corev1.ObjectReference{
-			APIVersion: "v1",
-			Kind:       "Service",
-			Name:       "foo",
+		URL:     u,
+		Timeout: &metav1.Duration{Duration: 10 * time.Millisecond},
+	}
+	req := &v1alpha1.InterceptorRequest{
+		InterceptorParams: interceptors.GetInterceptorParams(&v1alpha1.EventInterceptor{Webhook: webhook}),
+		Context: &v1alpha1.TriggerContext{
+			TriggerID: "namespaces/default/triggers/example-trigger",
 		},
 	}
-	i := NewInterceptor(webhook, client, "default", nil)
+	res := NewInterceptor(interceptors.NewClients(ts.Client()), nil, nil).Process(context.Background(), req)
+	if res.Continue || res.Status.Code() != codes.DeadlineExceeded {
+		t.Errorf("Process() = %+v, want a status with code %s", res, codes.DeadlineExceeded)
+	}
+}
+
+func TestWebHookInterceptor_Retry(t *testing.T) {
+	tests := []struct {
+		name      string
+		retry     *v1alpha1.WebhookRetryPolicy
+		status    []int
+		wantCalls int
+		wantCode  codes.Code
+	}{{
+		name:      "no retry policy",
+		status:    []int{http.StatusServiceUnavailable, http.StatusOK},
+		wantCalls: 1,
+		wantCode:  codes.Unavailable,
+	}, {
+		name:      "recovered",
+		retry:     &v1alpha1.WebhookRetryPolicy{Backoff: &metav1.Duration{Duration: time.Millisecond}},
+		status:    []int{http.StatusInternalServerError, http.StatusBadGateway, http.StatusOK},
+		wantCalls: 3,
+		wantCode:  codes.OK,
+	}, {
+		name:      "attempts exhausted",
+		retry:     &v1alpha1.WebhookRetryPolicy{MaxAttempts: 2, Backoff: &metav1.Duration{Duration: time.Millisecond}},
+		status:    []int{http.StatusInternalServerError, http.StatusInternalServerError, http.StatusOK},
+		wantCalls: 2,
+		wantCode:  codes.Unavailable,
+	}, {
+		name:      "rejected",
+		retry:     &v1alpha1.WebhookRetryPolicy{Backoff: &metav1.Duration{Duration: time.Millisecond}},
+		status:    []int{http.StatusForbidden, http.StatusOK},
+		wantCalls: 1,
+		wantCode:  codes.FailedPrecondition,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status[calls])
+				calls++
+			}))
+			defer ts.Close()
 
-	incoming, _ := http.NewRequest("POST", "http://doesnotmatter.example.com", payload)
-	resp, err := i.ExecuteTrigger(incoming)
-	if err == nil || resp.StatusCode != http.StatusAccepted {
-		got, _ := httputil.DumpResponse(resp, true)
-		t.Fatalf("ExecuteTrigger: expected (Accepted, err), got: %s", string(got))
+			u, _ := apis.ParseURL(ts.URL)
+			webhook := &v1alpha1.WebhookInterceptor{
+				URL:   u,
+				Retry: tt.retry,
+			}
+			req := &v1alpha1.InterceptorRequest{
+				InterceptorParams: interceptors.GetInterceptorParams(&v1alpha1.EventInterceptor{Webhook: webhook}),
+				Context: &v1alpha1.TriggerContext{
+					TriggerID: "namespaces/default/triggers/example-trigger",
+				},
+			}
+			res := NewInterceptor(interceptors.NewClients(ts.Client()), nil, zaptest.NewLogger(t).Sugar()).Process(context.Background(), req)
+			if res.Continue != (tt.wantCode == codes.OK) || res.Status.Code() != tt.wantCode {
+				t.Errorf("Process() = %+v, want a status with code %s", res, tt.wantCode)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("Process() sent the event %d times, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestWebHookInterceptor_CircuitBreaker(t *testing.T) {
+	calls := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	u, _ := apis.ParseURL(ts.URL)
+	webhook := &v1alpha1.WebhookInterceptor{
+		URL:   u,
+		Retry: &v1alpha1.WebhookRetryPolicy{MaxAttempts: 2, Backoff: &metav1.Duration{Duration: time.Millisecond}},
+		CircuitBreaker: &v1alpha1.WebhookCircuitBreaker{
+			FailureThreshold: 2,
+			OpenDuration:     &metav1.Duration{Duration: time.Hour},
+		},
+	}
+	// Interceptors are created for each event and share the breakers of the
+	// clients
+	clients := interceptors.NewClients(ts.Client())
+	process := func() *v1alpha1.InterceptorResponse {
+		req := &v1alpha1.InterceptorRequest{
+			InterceptorParams: interceptors.GetInterceptorParams(&v1alpha1.EventInterceptor{Webhook: webhook}),
+			Context: &v1alpha1.TriggerContext{
+				TriggerID: "namespaces/default/triggers/example-trigger",
+			},
+		}
+		return NewInterceptor(clients, nil, zaptest.NewLogger(t).Sugar()).Process(context.Background(), req)
+	}
+
+	if res := process(); res.Continue || res.Status.Code() != codes.Unavailable || calls != 2 {
+		t.Fatalf("Process() = %+v after %d calls, want a status with code %s after 2 calls", res, calls, codes.Unavailable)
+	}
+	// The breaker records a single failure for each event once its retries
+	// are exhausted, and opens on the second one
+	if res := process(); res.Continue || res.Status.Code() != codes.Unavailable || calls != 4 {
+		t.Fatalf("Process() = %+v after %d calls, want a status with code %s after 4 calls", res, calls, codes.Unavailable)
 	}
+	if res := process(); res.Continue || res.Status.Code() != codes.Unavailable || calls != 4 {
+		t.Errorf("Process() = %+v after %d calls, want a status with code %s without calling the open interceptor", res, calls, codes.Unavailable)
+	}
+}
+
+func TestWebHookInterceptor_FailurePolicy(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        int
+		failurePolicy v1alpha1.WebhookFailurePolicy
+		wantCode      codes.Code
+	}{{
+		name:     "unavailable",
+		status:   http.StatusServiceUnavailable,
+		wantCode: codes.Unavailable,
+	}, {
+		name:          "unavailable with Fail",
+		status:        http.StatusServiceUnavailable,
+		failurePolicy: v1alpha1.WebhookFailurePolicyFail,
+		wantCode:      codes.Unavailable,
+	}, {
+		name:          "unavailable with Ignore",
+		status:        http.StatusServiceUnavailable,
+		failurePolicy: v1alpha1.WebhookFailurePolicyIgnore,
+		wantCode:      codes.OK,
+	}, {
+		name:          "rejected with Ignore",
+		status:        http.StatusForbidden,
+		failurePolicy: v1alpha1.WebhookFailurePolicyIgnore,
+		wantCode:      codes.FailedPrecondition,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(`{"eventType": "replaced"}`))
+			}))
+			defer ts.Close()
 
+			u, _ := apis.ParseURL(ts.URL)
+			webhook := &v1alpha1.WebhookInterceptor{
+				URL:           u,
+				FailurePolicy: tt.failurePolicy,
+			}
+			req := &v1alpha1.InterceptorRequest{
+				Body:              []byte(`{"eventType": "push"}`),
+				Header:            http.Header{"X-Event": []string{"push"}},
+				InterceptorParams: interceptors.GetInterceptorParams(&v1alpha1.EventInterceptor{Webhook: webhook}),
+				Context: &v1alpha1.TriggerContext{
+					TriggerID: "namespaces/default/triggers/example-trigger",
+				},
+			}
+			body, header, res := NewInterceptor(interceptors.NewClients(ts.Client()), nil, zaptest.NewLogger(t).Sugar()).Intercept(context.Background(), req)
+			if res.Continue != (tt.wantCode == codes.OK) || res.Status.Code() != tt.wantCode {
+				t.Errorf("Intercept() = %+v, want a status with code %s", res, tt.wantCode)
+			}
+			if !res.Continue {
+				return
+			}
+			// Ignored interceptors leave the event unchanged
+			if diff := cmp.Diff([]byte(`{"eventType": "push"}`), body); diff != "" {
+				t.Errorf("Intercept() changed the payload (-want, +got) = %s", diff)
+			}
+			if diff := cmp.Diff(http.Header{"X-Event": {"push"}}, header); diff != "" {
+				t.Errorf("Intercept() changed the header (-want, +got) = %s", diff)
+			}
+		})
+	}
+}
+
+func TestWebHookInterceptor_TLS(t *testing.T) {
+	clientCert, clientKey := generateCertificate(t)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AppendCertsFromPEM(clientCert)
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("intercepted"))
+	}))
+	ts.TLS = &tls.Config{
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  clientCAs,
+	}
+	ts.StartTLS()
+	defer ts.Close()
+	caBundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw})
+
+	ctx, _ := rtesting.SetupFakeContext(t)
+	kubeClient := fakekubeclient.Get(ctx)
+	for _, secret := range []*corev1.Secret{{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "scanner-ca"},
+		Data:       map[string][]byte{"ca.crt": caBundle},
+	}, {
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "scanner-client"},
+		Type:       corev1.SecretTypeTLS,
+		Data:       map[string][]byte{"tls.crt": clientCert, "tls.key": clientKey},
+	}} {
+		if _, err := kubeClient.CoreV1().Secrets("default").Create(ctx, secret, metav1.CreateOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	u, _ := apis.ParseURL(ts.URL)
+	i := NewInterceptor(interceptors.NewClients(http.DefaultClient), kubeClient, nil)
+
+	tests := []struct {
+		name         string
+		tls          *v1alpha1.WebhookTLS
+		wantContinue bool
+		wantCode     codes.Code
+	}{{
+		name: "CA bundle and client certificate",
+		tls: &v1alpha1.WebhookTLS{
+			CASecretName:         "scanner-ca",
+			ClientCertSecretName: "scanner-client",
+		},
+		wantContinue: true,
+		wantCode:     codes.OK,
+	}, {
+		name:     "no client certificate",
+		tls:      &v1alpha1.WebhookTLS{CASecretName: "scanner-ca"},
+		wantCode: codes.Unavailable,
+	}, {
+		name:     "certificate not verified",
+		tls:      &v1alpha1.WebhookTLS{ClientCertSecretName: "scanner-client"},
+		wantCode: codes.Unavailable,
+	}, {
+		name:     "missing Secret",
+		tls:      &v1alpha1.WebhookTLS{CASecretName: "missing"},
+		wantCode: codes.Internal,
+	}, {
+		name:     "Secret without a CA bundle",
+		tls:      &v1alpha1.WebhookTLS{CASecretName: "scanner-client"},
+		wantCode: codes.Internal,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			webhook := &v1alpha1.WebhookInterceptor{URL: u, TLS: tt.tls}
+			req := &v1alpha1.InterceptorRequest{
+				InterceptorParams: interceptors.GetInterceptorParams(&v1alpha1.EventInterceptor{Webhook: webhook}),
+				Context: &v1alpha1.TriggerContext{
+					TriggerID: "namespaces/default/triggers/example-trigger",
+				},
+			}
+			body, _, res := i.Intercept(context.Background(), req)
+			if res.Continue != tt.wantContinue || res.Status.Code() != tt.wantCode {
+				t.Fatalf("Intercept() = %+v, want continue %t and code %s", res, tt.wantContinue, tt.wantCode)
+			}
+			if tt.wantContinue && string(body) != "intercepted" {
+				t.Errorf("Intercept() body = %s, want the body of the interceptor", body)
+			}
+		})
+	}
+}
+
+// generateCertificate returns a PEM encoded self-signed client certificate
+// and its private key.
+func generateCertificate(t *testing.T) ([]byte, []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "eventlistener"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
 }
 
 func TestGetURI(t *testing.T) {
@@ -0,0 +1,198 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package metrics records the metrics of the EventListener sink and exports
+// them in the Prometheus format.
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"contrib.go.opencensus.io/exporter/prometheus"
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+)
+
+// Namespace prefixes the names of the metrics exported by the sink.
+const Namespace = "eventlistener"
+
+// The outcomes of processing an event for a Trigger.
+const (
+	OutcomeCreated  = "created"
+	OutcomeFiltered = "filtered"
+	OutcomeFailed   = "failed"
+	OutcomeDryRun   = "dryrun"
+)
+
+var (
+	eventListenerKey = tag.MustNewKey("eventlistener")
+	triggerKey       = tag.MustNewKey("trigger")
+	outcomeKey       = tag.MustNewKey("outcome")
+	interceptorKey   = tag.MustNewKey("interceptor")
+	resourceKey      = tag.MustNewKey("resource")
+	codeKey          = tag.MustNewKey("code")
+
+	eventCount = stats.Int64("event_count",
+		"Number of events received by the EventListener", stats.UnitDimensionless)
+	triggerCount = stats.Int64("trigger_count",
+		"Number of times an event was processed for a Trigger", stats.UnitDimensionless)
+	interceptorLatency = stats.Float64("interceptor_latency",
+		"Time taken by an interceptor to process an event", stats.UnitMilliseconds)
+	resourceCreateLatency = stats.Float64("resource_create_latency",
+		"Time taken to create a resource for a Trigger", stats.UnitMilliseconds)
+	httpResponseCount = stats.Int64("http_response_count",
+		"Number of HTTP responses sent to events by the EventListener", stats.UnitDimensionless)
+	rateLimitedCount = stats.Int64("rate_limited_count",
+		"Number of events rejected by a rate limit", stats.UnitDimensionless)
+
+	latencyDistribution = view.Distribution(1, 5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000)
+
+	views = []*view.View{{
+		Description: eventCount.Description(),
+		Measure:     eventCount,
+		Aggregation: view.Count(),
+		TagKeys:     []tag.Key{eventListenerKey},
+	}, {
+		Description: triggerCount.Description(),
+		Measure:     triggerCount,
+		Aggregation: view.Count(),
+		TagKeys:     []tag.Key{eventListenerKey, triggerKey, outcomeKey},
+	}, {
+		Description: interceptorLatency.Description(),
+		Measure:     interceptorLatency,
+		Aggregation: latencyDistribution,
+		TagKeys:     []tag.Key{eventListenerKey, triggerKey, interceptorKey},
+	}, {
+		Description: resourceCreateLatency.Description(),
+		Measure:     resourceCreateLatency,
+		Aggregation: latencyDistribution,
+		TagKeys:     []tag.Key{eventListenerKey, triggerKey, resourceKey},
+	}, {
+		Description: httpResponseCount.Description(),
+		Measure:     httpResponseCount,
+		Aggregation: view.Count(),
+		TagKeys:     []tag.Key{eventListenerKey, codeKey},
+	}, {
+		Description: rateLimitedCount.Description(),
+		Measure:     rateLimitedCount,
+		Aggregation: view.Count(),
+		TagKeys:     []tag.Key{eventListenerKey, triggerKey},
+	}}
+)
+
+// RegisterViews registers the views aggregating the recorded metrics. Metrics
+// are only aggregated, and so exported, once their views are registered.
+func RegisterViews() error {
+	return view.Register(views...)
+}
+
+// NewHandler returns the handler serving the metrics in the Prometheus
+// format.
+func NewHandler() (http.Handler, error) {
+	return prometheus.NewExporter(prometheus.Options{Namespace: Namespace})
+}
+
+// RecordEvent records an event received by the EventListener.
+func RecordEvent(eventListener string) {
+	record(eventCount.M(1), tag.Upsert(eventListenerKey, eventListener))
+}
+
+// RecordTriggerOutcome records the outcome of processing an event for a
+// Trigger.
+func RecordTriggerOutcome(eventListener, trigger, outcome string) {
+	record(triggerCount.M(1),
+		tag.Upsert(eventListenerKey, eventListener),
+		tag.Upsert(triggerKey, trigger),
+		tag.Upsert(outcomeKey, outcome))
+}
+
+// RecordInterceptorLatency records the time taken by an interceptor of the
+// given type to process an event for a Trigger.
+func RecordInterceptorLatency(eventListener, trigger, interceptor string, d time.Duration) {
+	record(interceptorLatency.M(milliseconds(d)),
+		tag.Upsert(eventListenerKey, eventListener),
+		tag.Upsert(triggerKey, trigger),
+		tag.Upsert(interceptorKey, interceptor))
+}
+
+// RecordResourceCreateLatency records the time taken to create a resource
+// for a Trigger. The resource is identified by its group, version and
+// resource, such as tekton.dev/v1beta1/pipelineruns.
+func RecordResourceCreateLatency(eventListener, trigger, resource string, d time.Duration) {
+	record(resourceCreateLatency.M(milliseconds(d)),
+		tag.Upsert(eventListenerKey, eventListener),
+		tag.Upsert(triggerKey, trigger),
+		tag.Upsert(resourceKey, resource))
+}
+
+// RecordHTTPResponse records an HTTP response sent by the EventListener.
+func RecordHTTPResponse(eventListener string, code int) {
+	record(httpResponseCount.M(1),
+		tag.Upsert(eventListenerKey, eventListener),
+		tag.Upsert(codeKey, strconv.Itoa(code)))
+}
+
+// RecordRateLimited records an event rejected by the rate limit of the
+// EventListener, or of the Trigger when trigger is not empty.
+func RecordRateLimited(eventListener, trigger string) {
+	mutators := []tag.Mutator{tag.Upsert(eventListenerKey, eventListener)}
+	if trigger != "" {
+		mutators = append(mutators, tag.Upsert(triggerKey, trigger))
+	}
+	record(rateLimitedCount.M(1), mutators...)
+}
+
+// InstrumentHandler wraps h to record the status code of its responses.
+func InstrumentHandler(eventListener string, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sw := &statusWriter{ResponseWriter: w, code: http.StatusOK}
+		h.ServeHTTP(sw, r)
+		RecordHTTPResponse(eventListener, sw.code)
+	})
+}
+
+// statusWriter remembers the status code written to a ResponseWriter.
+type statusWriter struct {
+	http.ResponseWriter
+	code        int
+	wroteHeader bool
+}
+
+func (w *statusWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.code = code
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *statusWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(b)
+}
+
+func record(m stats.Measurement, mutators ...tag.Mutator) {
+	// Recording only fails for invalid tag values, which are dropped.
+	_ = stats.RecordWithTags(context.Background(), mutators, m)
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go.opencensus.io/stats/view"
+)
+
+func setup(t *testing.T) {
+	t.Helper()
+	if err := RegisterViews(); err != nil {
+		t.Fatalf("RegisterViews() unexpected error: %v", err)
+	}
+	t.Cleanup(func() { view.Unregister(views...) })
+}
+
+func TestRecord(t *testing.T) {
+	setup(t)
+	handler, err := NewHandler()
+	if err != nil {
+		t.Fatalf("NewHandler() unexpected error: %v", err)
+	}
+
+	RecordEvent("my-el")
+	RecordEvent("my-el")
+	RecordTriggerOutcome("my-el", "my-trigger", OutcomeCreated)
+	RecordTriggerOutcome("my-el", "my-trigger", OutcomeFiltered)
+	RecordInterceptorLatency("my-el", "my-trigger", "cel", 3*time.Millisecond)
+	RecordResourceCreateLatency("my-el", "my-trigger", "tekton.dev/v1beta1/pipelineruns", 20*time.Millisecond)
+	RecordHTTPResponse("my-el", http.StatusCreated)
+	RecordRateLimited("my-el", "")
+	RecordRateLimited("my-el", "my-trigger")
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body, _ := ioutil.ReadAll(rec.Body)
+	for _, want := range []string{
+		`eventlistener_event_count{eventlistener="my-el"} 2`,
+		`eventlistener_trigger_count{eventlistener="my-el",outcome="created",trigger="my-trigger"} 1`,
+		`eventlistener_trigger_count{eventlistener="my-el",outcome="filtered",trigger="my-trigger"} 1`,
+		`eventlistener_interceptor_latency_bucket{eventlistener="my-el",interceptor="cel",trigger="my-trigger",le="5"} 1`,
+		`eventlistener_resource_create_latency_count{eventlistener="my-el",resource="tekton.dev/v1beta1/pipelineruns",trigger="my-trigger"} 1`,
+		`eventlistener_http_response_count{code="201",eventlistener="my-el"} 1`,
+		`eventlistener_rate_limited_count{eventlistener="my-el",trigger=""} 1`,
+		`eventlistener_rate_limited_count{eventlistener="my-el",trigger="my-trigger"} 1`,
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("metrics do not contain %q:\n%s", want, body)
+		}
+	}
+}
+
+func TestInstrumentHandler(t *testing.T) {
+	setup(t)
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		want    string
+	}{{
+		name:    "explicit status",
+		handler: func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusAccepted) },
+		want:    "202",
+	}, {
+		name:    "implicit status",
+		handler: func(w http.ResponseWriter, r *http.Request) { _, _ = w.Write([]byte("ok")) },
+		want:    "200",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := InstrumentHandler("instrumented-el", tt.handler)
+			h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", nil))
+
+			rows, err := view.RetrieveData("http_response_count")
+			if err != nil {
+				t.Fatalf("RetrieveData() unexpected error: %v", err)
+			}
+			for _, row := range rows {
+				for _, tag := range row.Tags {
+					if tag.Key == codeKey && tag.Value == tt.want {
+						return
+					}
+				}
+			}
+			t.Errorf("no http_response_count recorded with code %s: %v", tt.want, rows)
+		})
+	}
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/tektoncd/triggers/pkg/apis/triggers/v1alpha1"
 	listers "github.com/tektoncd/triggers/pkg/client/listers/triggers/v1alpha1"
+	"github.com/tektoncd/triggers/pkg/interceptors"
 	"go.uber.org/zap"
 	"golang.org/x/xerrors"
 	appsv1 "k8s.io/api/apps/v1"
@@ -53,6 +54,33 @@ const (
 	eventListenerConfigMapName = "config-logging-triggers"
 	// eventListenerServicePortName defines service port name for EventListener Service
 	eventListenerServicePortName = "http-listener"
+	// eventListenerMetricsPortName defines the container port name for the
+	// EventListener metrics, which are not exposed on its Service
+	eventListenerMetricsPortName = "http-metrics"
+	// eventListenerAdminPortName defines the container port name for the
+	// EventListener admin endpoint
+	eventListenerAdminPortName = "http-admin"
+	// The paths that the Secrets holding the TLS certificates of the
+	// EventListener are mounted at
+	eventListenerTLSMountPath      = "/etc/triggers/tls"
+	eventListenerClientCAMountPath = "/etc/triggers/client-ca"
+	// eventListenerAdminMountPath is the path that the Secret holding the
+	// token of the EventListener admin endpoint is mounted at
+	eventListenerAdminMountPath = "/etc/triggers/admin"
+	// eventListenerAdminTokenKey is the key of the admin token in its Secret
+	eventListenerAdminTokenKey = "token"
+	// eventListenerInterceptorTokenMountPath is the path that the
+	// ServiceAccount token sent to ClusterInterceptors is projected at
+	eventListenerInterceptorTokenMountPath = "/var/run/secrets/triggers.tekton.dev/interceptors"
+	// eventListenerInterceptorTokenExpiration is how long the token sent to
+	// ClusterInterceptors is valid for before the kubelet rotates it
+	eventListenerInterceptorTokenExpiration = 3600
+	// defaultRecentEvents is the number of recent events kept for the admin
+	// endpoint when the EventListener does not set it
+	defaultRecentEvents = 100
+	// eventListenerClientCAKey is the key of the client CA certificates in
+	// their Secret
+	eventListenerClientCAKey = "ca.crt"
 	// GeneratedResourcePrefix is the name prefix for resources generated in the
 	// EventListener reconciler
 	GeneratedResourcePrefix = "el"
@@ -67,6 +95,21 @@ var (
 	// ElPort defines the port for the EventListener to listen on
 	ElPort = flag.Int("el-port", 8080,
 		"The container port for the EventListener to listen on.")
+	// ElMetricsPort defines the port for the EventListener to serve metrics on
+	ElMetricsPort = flag.Int("el-metrics-port", 9000,
+		"The container port for the EventListener to serve metrics on.")
+	// ElAdminPort defines the port for the EventListener to serve the admin endpoint on
+	ElAdminPort = flag.Int("el-admin-port", 9001,
+		"The container port for the EventListener to serve the admin endpoint on.")
+	// ElInterceptorToken defines whether the EventListener authenticates to ClusterInterceptors
+	ElInterceptorToken = flag.Bool("el-interceptor-token", false,
+		"Whether the EventListener sends a projected ServiceAccount token to ClusterInterceptors, which requires ServiceAccount token projection.")
+	// ElTracingEndpoint defines the OTLP receiver the EventListener exports traces to
+	ElTracingEndpoint = flag.String("el-tracing-endpoint", "",
+		"The address of the OTLP receiver for the EventListener to export traces to.")
+	// ElTracingSampleRate defines the probability of the EventListener sampling new traces
+	ElTracingSampleRate = flag.Float64("el-tracing-sample-rate", 1,
+		"The probability of the EventListener sampling the traces of events without a sampled parent.")
 	// ELReadTimeOut defines the read timeout for EventListener Server
 	ELReadTimeOut = flag.Int64("el-readtimeout", 5,
 		"The read timeout for EventListener Server.")
@@ -79,6 +122,27 @@ var (
 	// ELTimeOutHandler defines the timeout for Timeout Handler of EventListener Server
 	ELTimeOutHandler = flag.Int64("el-timeouthandler", 5,
 		"The timeout for Timeout Handler of EventListener Server.")
+	// ElMaxBodySize defines the maximum size of the body of an event
+	ElMaxBodySize = flag.Int64("el-maxbodysize", 25*1024*1024,
+		"The maximum size in bytes of the body of an event received by the EventListener.")
+	// ElMaxBatchSize defines the maximum number of events in a batch
+	ElMaxBatchSize = flag.Int("el-maxbatchsize", 100,
+		"The maximum number of events in a batch received by the EventListener.")
+	// ElQueueSize defines the maximum number of Triggers waiting in the event queue
+	ElQueueSize = flag.Int("el-queuesize", 1000,
+		"The maximum number of Triggers waiting to be processed for events handled asynchronously by the EventListener.")
+	// ElQueueWorkers defines the number of workers processing the event queue
+	ElQueueWorkers = flag.Int("el-queueworkers", 4,
+		"The number of workers processing Triggers for events handled asynchronously by the EventListener.")
+	// ElQueueRetries defines the number of retries of a failed Trigger in the event queue
+	ElQueueRetries = flag.Int("el-queueretries", 3,
+		"The number of times a failed Trigger for an event handled asynchronously by the EventListener is retried.")
+	// ElDrainTimeOut defines the time allowed for draining the event queue on shutdown
+	ElDrainTimeOut = flag.Int64("el-draintimeout", 20,
+		"The time in seconds allowed for processing the Triggers left in the event queue when the EventListener shuts down.")
+	// ElShutdownTimeOut defines the time allowed for handling in-flight events on shutdown
+	ElShutdownTimeOut = flag.Int64("el-shutdowntimeout", 10,
+		"The time in seconds allowed for handling the events being received when the EventListener shuts down.")
 	// PeriodSeconds defines Period Seconds for the EventListener Liveness and Readiness Probes
 	PeriodSeconds = flag.Int("period-seconds", 10,
 		"The Period Seconds for the EventListener Liveness and Readiness Probes.")
@@ -202,8 +266,8 @@ func (r *Reconciler) reconcileService(ctx context.Context, logger *zap.SugaredLo
 				Port:     int32(*ElPort),
 				TargetPort: intstr.IntOrString{
 					IntVal: int32(*ElPort),
-				}},
-			},
+				},
+			}},
 		},
 	}
 	existingService, err := r.serviceLister.Services(el.Namespace).Get(el.Status.Configuration.GeneratedResourceName)
@@ -212,7 +276,7 @@ func (r *Reconciler) reconcileService(ctx context.Context, logger *zap.SugaredLo
 		// Determine if reconciliation has to occur
 		updated := reconcileObjectMeta(&existingService.ObjectMeta, service.ObjectMeta)
 		el.Status.SetExistsCondition(v1alpha1.ServiceExists, nil)
-		el.Status.SetAddress(listenerHostname(service.Name, el.Namespace, *ElPort))
+		el.Status.SetAddressWithScheme(listenerScheme(el), listenerHostname(service.Name, el.Namespace, *ElPort))
 		if !reflect.DeepEqual(existingService.Spec.Selector, service.Spec.Selector) {
 			existingService.Spec.Selector = service.Spec.Selector
 			updated = true
@@ -243,7 +307,7 @@ func (r *Reconciler) reconcileService(ctx context.Context, logger *zap.SugaredLo
 			logger.Errorf("Error creating EventListener Service: %s", err)
 			return err
 		}
-		el.Status.SetAddress(listenerHostname(service.Name, el.Namespace, *ElPort))
+		el.Status.SetAddressWithScheme(listenerScheme(el), listenerHostname(service.Name, el.Namespace, *ElPort))
 		logger.Infof("Created EventListener Service %s in Namespace %s", service.Name, el.Namespace)
 	default:
 		logger.Error(err)
@@ -312,12 +376,16 @@ func (r *Reconciler) reconcileDeployment(ctx context.Context, logger *zap.Sugare
 		Ports: []corev1.ContainerPort{{
 			ContainerPort: int32(*ElPort),
 			Protocol:      corev1.ProtocolTCP,
+		}, {
+			Name:          eventListenerMetricsPortName,
+			ContainerPort: int32(*ElMetricsPort),
+			Protocol:      corev1.ProtocolTCP,
 		}},
 		LivenessProbe: &corev1.Probe{
 			Handler: corev1.Handler{
 				HTTPGet: &corev1.HTTPGetAction{
 					Path:   "/live",
-					Scheme: corev1.URISchemeHTTP,
+					Scheme: probeScheme(el),
 					Port:   intstr.FromInt((*ElPort)),
 				},
 			},
@@ -327,8 +395,8 @@ func (r *Reconciler) reconcileDeployment(ctx context.Context, logger *zap.Sugare
 		ReadinessProbe: &corev1.Probe{
 			Handler: corev1.Handler{
 				HTTPGet: &corev1.HTTPGetAction{
-					Path:   "/live",
-					Scheme: corev1.URISchemeHTTP,
+					Path:   "/ready",
+					Scheme: probeScheme(el),
 					Port:   intstr.FromInt((*ElPort)),
 				},
 			},
@@ -344,6 +412,14 @@ func (r *Reconciler) reconcileDeployment(ctx context.Context, logger *zap.Sugare
 			"-writetimeout", strconv.FormatInt(*ELWriteTimeOut, 10),
 			"-idletimeout", strconv.FormatInt(*ELIdleTimeOut, 10),
 			"-timeouthandler", strconv.FormatInt(*ELTimeOutHandler, 10),
+			"-metricsport", strconv.Itoa(*ElMetricsPort),
+			"-maxbodysize", strconv.FormatInt(*ElMaxBodySize, 10),
+			"-maxbatchsize", strconv.Itoa(*ElMaxBatchSize),
+			"-queuesize", strconv.Itoa(*ElQueueSize),
+			"-queueworkers", strconv.Itoa(*ElQueueWorkers),
+			"-queueretries", strconv.Itoa(*ElQueueRetries),
+			"-draintimeout", strconv.FormatInt(*ElDrainTimeOut, 10),
+			"-shutdowntimeout", strconv.FormatInt(*ElShutdownTimeOut, 10),
 		},
 		VolumeMounts: []corev1.VolumeMount{{
 			Name:      "config-logging",
@@ -359,6 +435,113 @@ func (r *Reconciler) reconcileDeployment(ctx context.Context, logger *zap.Sugare
 		}},
 	}
 
+	if *ElTracingEndpoint != "" {
+		container.Args = append(container.Args,
+			"-tracingendpoint", *ElTracingEndpoint,
+			"-tracingsamplerate", strconv.FormatFloat(*ElTracingSampleRate, 'f', -1, 64),
+		)
+	}
+
+	volumes := []corev1.Volume{{
+		Name: "config-logging",
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: eventListenerConfigMapName,
+				},
+			},
+		},
+	}}
+	if *ElInterceptorToken {
+		// The token that the sink authenticates to ClusterInterceptors with
+		// is only valid for them
+		container.Args = append(container.Args,
+			"-interceptortokenfile", eventListenerInterceptorTokenMountPath+"/token",
+		)
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      "interceptor-token",
+			MountPath: eventListenerInterceptorTokenMountPath,
+			ReadOnly:  true,
+		})
+		volumes = append(volumes, corev1.Volume{
+			Name: "interceptor-token",
+			VolumeSource: corev1.VolumeSource{
+				Projected: &corev1.ProjectedVolumeSource{
+					Sources: []corev1.VolumeProjection{{
+						ServiceAccountToken: &corev1.ServiceAccountTokenProjection{
+							Audience:          interceptors.TokenAudience,
+							ExpirationSeconds: ptr.Int64(eventListenerInterceptorTokenExpiration),
+							Path:              "token",
+						},
+					}},
+				},
+			},
+		})
+	}
+	if tls := el.Spec.TLS; tls != nil {
+		// The kubelet updates the mounted Secrets when they change, and the
+		// sink reloads the certificates.
+		container.Args = append(container.Args,
+			"-tlscertfile", eventListenerTLSMountPath+"/"+corev1.TLSCertKey,
+			"-tlskeyfile", eventListenerTLSMountPath+"/"+corev1.TLSPrivateKeyKey,
+		)
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      "tls",
+			MountPath: eventListenerTLSMountPath,
+			ReadOnly:  true,
+		})
+		volumes = append(volumes, corev1.Volume{
+			Name: "tls",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{SecretName: tls.SecretName},
+			},
+		})
+		if tls.ClientCASecretName != "" {
+			container.Args = append(container.Args,
+				"-tlsclientcafile", eventListenerClientCAMountPath+"/"+eventListenerClientCAKey,
+			)
+			container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+				Name:      "client-ca",
+				MountPath: eventListenerClientCAMountPath,
+				ReadOnly:  true,
+			})
+			volumes = append(volumes, corev1.Volume{
+				Name: "client-ca",
+				VolumeSource: corev1.VolumeSource{
+					Secret: &corev1.SecretVolumeSource{SecretName: tls.ClientCASecretName},
+				},
+			})
+		}
+	}
+
+	if admin := el.Spec.Admin; admin != nil {
+		recentEvents := admin.RecentEvents
+		if recentEvents == 0 {
+			recentEvents = defaultRecentEvents
+		}
+		container.Ports = append(container.Ports, corev1.ContainerPort{
+			Name:          eventListenerAdminPortName,
+			ContainerPort: int32(*ElAdminPort),
+			Protocol:      corev1.ProtocolTCP,
+		})
+		container.Args = append(container.Args,
+			"-adminport", strconv.Itoa(*ElAdminPort),
+			"-admintokenfile", eventListenerAdminMountPath+"/"+eventListenerAdminTokenKey,
+			"-recentevents", strconv.Itoa(int(recentEvents)),
+		)
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      "admin-token",
+			MountPath: eventListenerAdminMountPath,
+			ReadOnly:  true,
+		})
+		volumes = append(volumes, corev1.Volume{
+			Name: "admin-token",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{SecretName: admin.TokenSecretName},
+			},
+		})
+	}
+
 	deployment := &appsv1.Deployment{
 		ObjectMeta: generateObjectMeta(el),
 		Spec: appsv1.DeploymentSpec{
@@ -368,25 +551,19 @@ func (r *Reconciler) reconcileDeployment(ctx context.Context, logger *zap.Sugare
 			},
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
-					Labels:      podlabels,
-					Annotations: annotations,
+					Labels: podlabels,
+					// Let users override the scraping annotations
+					Annotations: mergeMaps(metricsAnnotations(), annotations),
 				},
 				Spec: corev1.PodSpec{
 					Tolerations:        tolerations,
 					NodeSelector:       nodeSelector,
 					ServiceAccountName: serviceAccountName,
 					Containers:         []corev1.Container{container},
-
-					Volumes: []corev1.Volume{{
-						Name: "config-logging",
-						VolumeSource: corev1.VolumeSource{
-							ConfigMap: &corev1.ConfigMapVolumeSource{
-								LocalObjectReference: corev1.LocalObjectReference{
-									Name: eventListenerConfigMapName,
-								},
-							},
-						},
-					}},
+					Volumes:            volumes,
+					// The sink handles the events being received, then
+					// drains the event queue, before it is killed
+					TerminationGracePeriodSeconds: ptr.Int64(*ElShutdownTimeOut + *ElDrainTimeOut),
 				},
 			},
 		},
@@ -430,6 +607,10 @@ func (r *Reconciler) reconcileDeployment(ctx context.Context, logger *zap.Sugare
 			existingDeployment.Spec.Template.Spec.NodeSelector = deployment.Spec.Template.Spec.NodeSelector
 			updated = true
 		}
+		if !reflect.DeepEqual(existingDeployment.Spec.Template.Spec.TerminationGracePeriodSeconds, deployment.Spec.Template.Spec.TerminationGracePeriodSeconds) {
+			existingDeployment.Spec.Template.Spec.TerminationGracePeriodSeconds = deployment.Spec.Template.Spec.TerminationGracePeriodSeconds
+			updated = true
+		}
 		if len(existingDeployment.Spec.Template.Spec.Containers) == 0 ||
 			len(existingDeployment.Spec.Template.Spec.Containers) > 1 {
 			existingDeployment.Spec.Template.Spec.Containers = []corev1.Container{container}
@@ -459,10 +640,19 @@ func (r *Reconciler) reconcileDeployment(ctx context.Context, logger *zap.Sugare
 				existingDeployment.Spec.Template.Spec.Containers[0].Resources = container.Resources
 				updated = true
 			}
-			if len(existingDeployment.Spec.Template.Spec.Containers[0].VolumeMounts) == 0 {
+			if !reflect.DeepEqual(existingDeployment.Spec.Template.Spec.Containers[0].VolumeMounts, container.VolumeMounts) {
 				existingDeployment.Spec.Template.Spec.Containers[0].VolumeMounts = container.VolumeMounts
 				updated = true
 			}
+			// The API server defaults the other fields of the probes
+			if probeChanged(existingDeployment.Spec.Template.Spec.Containers[0].LivenessProbe, container.LivenessProbe) {
+				existingDeployment.Spec.Template.Spec.Containers[0].LivenessProbe = container.LivenessProbe
+				updated = true
+			}
+			if probeChanged(existingDeployment.Spec.Template.Spec.Containers[0].ReadinessProbe, container.ReadinessProbe) {
+				existingDeployment.Spec.Template.Spec.Containers[0].ReadinessProbe = container.ReadinessProbe
+				updated = true
+			}
 			if !reflect.DeepEqual(existingDeployment.Spec.Template.Spec.Volumes, deployment.Spec.Template.Spec.Volumes) {
 				existingDeployment.Spec.Template.Spec.Volumes = deployment.Spec.Template.Spec.Volumes
 				updated = true
@@ -514,6 +704,16 @@ func generateObjectMeta(el *v1alpha1.EventListener) metav1.ObjectMeta {
 	}
 }
 
+// metricsAnnotations returns the annotations for Prometheus to scrape the
+// metrics of the EventListener Pods.
+func metricsAnnotations() map[string]string {
+	return map[string]string{
+		"prometheus.io/scrape": "true",
+		"prometheus.io/port":   strconv.Itoa(*ElMetricsPort),
+		"prometheus.io/path":   "/metrics",
+	}
+}
+
 // mergeMaps merges the values in the passed maps into a new map.
 // Values within m2 potentially clobber m1 values.
 func mergeMaps(m1, m2 map[string]string) map[string]string {
@@ -539,6 +739,30 @@ func wrapError(err1, err2 error) error {
 	return xerrors.Errorf("%s : %s", err1.Error(), err2.Error())
 }
 
+// listenerScheme returns the URL scheme of the EventListener's address.
+func listenerScheme(el *v1alpha1.EventListener) string {
+	if el.Spec.TLS != nil {
+		return "https"
+	}
+	return "http"
+}
+
+// probeScheme returns the scheme of the EventListener's probes.
+func probeScheme(el *v1alpha1.EventListener) corev1.URIScheme {
+	if el.Spec.TLS != nil {
+		return corev1.URISchemeHTTPS
+	}
+	return corev1.URISchemeHTTP
+}
+
+// probeChanged reports whether the existing probe of the EventListener's
+// container no longer gets the path of the desired probe with its scheme.
+func probeChanged(existing, desired *corev1.Probe) bool {
+	return existing == nil || existing.HTTPGet == nil ||
+		existing.HTTPGet.Path != desired.HTTPGet.Path ||
+		existing.HTTPGet.Scheme != desired.HTTPGet.Scheme
+}
+
 // listenerHostname returns the intended hostname for the EventListener service.
 func listenerHostname(name, namespace string, port int) string {
 	return fmt.Sprintf("%s.%s.svc.cluster.local:%d", name, namespace, port)
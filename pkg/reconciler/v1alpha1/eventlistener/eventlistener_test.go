@@ -27,6 +27,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/tektoncd/triggers/pkg/apis/triggers/v1alpha1"
+	"github.com/tektoncd/triggers/pkg/interceptors"
 	"github.com/tektoncd/triggers/pkg/system"
 	"github.com/tektoncd/triggers/test"
 	bldr "github.com/tektoncd/triggers/test/builder"
@@ -168,16 +169,22 @@ func makeDeployment(ops ...func(d *appsv1.Deployment)) *appsv1.Deployment {
 			},
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
-					Labels: generatedLabels,
+					Labels:      generatedLabels,
+					Annotations: metricsAnnotations(),
 				},
 				Spec: corev1.PodSpec{
-					ServiceAccountName: "sa",
+					ServiceAccountName:            "sa",
+					TerminationGracePeriodSeconds: ptr.Int64(*ElShutdownTimeOut + *ElDrainTimeOut),
 					Containers: []corev1.Container{{
 						Name:  "event-listener",
 						Image: *elImage,
 						Ports: []corev1.ContainerPort{{
 							ContainerPort: int32(*ElPort),
 							Protocol:      corev1.ProtocolTCP,
+						}, {
+							Name:          eventListenerMetricsPortName,
+							ContainerPort: int32(*ElMetricsPort),
+							Protocol:      corev1.ProtocolTCP,
 						}},
 						LivenessProbe: &corev1.Probe{
 							Handler: corev1.Handler{
@@ -193,7 +200,7 @@ func makeDeployment(ops ...func(d *appsv1.Deployment)) *appsv1.Deployment {
 						ReadinessProbe: &corev1.Probe{
 							Handler: corev1.Handler{
 								HTTPGet: &corev1.HTTPGetAction{
-									Path:   "/live",
+									Path:   "/ready",
 									Scheme: corev1.URISchemeHTTP,
 									Port:   intstr.FromInt((*ElPort)),
 								},
@@ -209,6 +216,14 @@ func makeDeployment(ops ...func(d *appsv1.Deployment)) *appsv1.Deployment {
 							"writetimeout", strconv.FormatInt(*ELWriteTimeOut, 10),
 							"idletimeout", strconv.FormatInt(*ELIdleTimeOut, 10),
 							"timeouthandler", strconv.FormatInt(*ELTimeOutHandler, 10),
+							"-metricsport", strconv.Itoa(*ElMetricsPort),
+							"-maxbodysize", strconv.FormatInt(*ElMaxBodySize, 10),
+							"-maxbatchsize", strconv.Itoa(*ElMaxBatchSize),
+							"-queuesize", strconv.Itoa(*ElQueueSize),
+							"-queueworkers", strconv.Itoa(*ElQueueWorkers),
+							"-queueretries", strconv.Itoa(*ElQueueRetries),
+							"-draintimeout", strconv.FormatInt(*ElDrainTimeOut, 10),
+							"-shutdowntimeout", strconv.FormatInt(*ElShutdownTimeOut, 10),
 						},
 						VolumeMounts: []corev1.VolumeMount{{
 							Name:      "config-logging",
@@ -413,6 +428,30 @@ func TestReconcile(t *testing.T) {
 		}
 	})
 
+	elWithMetricsAnnotationOverride := makeEL(withStatus, func(el *v1alpha1.EventListener) {
+		el.Spec.Resources.KubernetesResource = &v1alpha1.KubernetesResource{
+			WithPodSpec: duckv1.WithPodSpec{
+				Template: duckv1.PodSpecable{
+					ObjectMeta: metav1.ObjectMeta{
+						Annotations: map[string]string{"prometheus.io/scrape": "false"},
+					},
+				},
+			},
+		}
+	})
+
+	elWithTLS := makeEL(withStatus, func(el *v1alpha1.EventListener) {
+		el.Spec.TLS = &v1alpha1.EventListenerTLS{
+			SecretName:         "el-tls",
+			ClientCASecretName: "el-client-ca",
+		}
+		el.Status.SetAddressWithScheme("https", listenerHostname(generatedResourceName, namespace, *ElPort))
+	})
+
+	elWithAdmin := makeEL(withStatus, func(el *v1alpha1.EventListener) {
+		el.Spec.Admin = &v1alpha1.EventListenerAdmin{TokenSecretName: "el-admin"}
+	})
+
 	elDeployment := makeDeployment()
 	elDeploymentWithLabels := makeDeployment(func(d *appsv1.Deployment) {
 		d.Labels = mergeMaps(updateLabel, generatedLabels)
@@ -470,7 +509,72 @@ func TestReconcile(t *testing.T) {
 			"app.kubernetes.io/part-of":    "Triggers",
 			"eventlistener":                "my-eventlistener",
 			"labelkey":                     "labelvalue"}
-		d.Spec.Template.ObjectMeta.Annotations = map[string]string{"annotationkey": "annotationvalue"}
+		d.Spec.Template.ObjectMeta.Annotations = mergeMaps(metricsAnnotations(), map[string]string{"annotationkey": "annotationvalue"})
+	})
+
+	deploymentWithMetricsAnnotationOverride := makeDeployment(func(d *appsv1.Deployment) {
+		d.Spec.Template.ObjectMeta.Annotations["prometheus.io/scrape"] = "false"
+	})
+
+	// Deployments created before the readiness probe had its own path
+	deploymentWithLiveReadinessProbe := makeDeployment(func(d *appsv1.Deployment) {
+		d.Spec.Template.Spec.Containers[0].ReadinessProbe.HTTPGet.Path = "/live"
+	})
+
+	deploymentWithTLS := makeDeployment(func(d *appsv1.Deployment) {
+		container := &d.Spec.Template.Spec.Containers[0]
+		container.LivenessProbe.HTTPGet.Scheme = corev1.URISchemeHTTPS
+		container.ReadinessProbe.HTTPGet.Scheme = corev1.URISchemeHTTPS
+		container.Args = append(container.Args,
+			"-tlscertfile", "/etc/triggers/tls/tls.crt",
+			"-tlskeyfile", "/etc/triggers/tls/tls.key",
+			"-tlsclientcafile", "/etc/triggers/client-ca/ca.crt",
+		)
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      "tls",
+			MountPath: "/etc/triggers/tls",
+			ReadOnly:  true,
+		}, corev1.VolumeMount{
+			Name:      "client-ca",
+			MountPath: "/etc/triggers/client-ca",
+			ReadOnly:  true,
+		})
+		d.Spec.Template.Spec.Volumes = append(d.Spec.Template.Spec.Volumes, corev1.Volume{
+			Name: "tls",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{SecretName: "el-tls"},
+			},
+		}, corev1.Volume{
+			Name: "client-ca",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{SecretName: "el-client-ca"},
+			},
+		})
+	})
+
+	deploymentWithAdmin := makeDeployment(func(d *appsv1.Deployment) {
+		container := &d.Spec.Template.Spec.Containers[0]
+		container.Ports = append(container.Ports, corev1.ContainerPort{
+			Name:          "http-admin",
+			ContainerPort: int32(*ElAdminPort),
+			Protocol:      corev1.ProtocolTCP,
+		})
+		container.Args = append(container.Args,
+			"-adminport", strconv.Itoa(*ElAdminPort),
+			"-admintokenfile", "/etc/triggers/admin/token",
+			"-recentevents", "100",
+		)
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      "admin-token",
+			MountPath: "/etc/triggers/admin",
+			ReadOnly:  true,
+		})
+		d.Spec.Template.Spec.Volumes = append(d.Spec.Template.Spec.Volumes, corev1.Volume{
+			Name: "admin-token",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{SecretName: "el-admin"},
+			},
+		})
 	})
 
 	elService := makeService()
@@ -751,6 +855,21 @@ func TestReconcile(t *testing.T) {
 			Services:       []*corev1.Service{elService},
 			ConfigMaps:     []*corev1.ConfigMap{loggingConfigMap},
 		},
+	}, {
+		name: "eventlistener-readiness-probe-update",
+		key:  reconcileKey,
+		startResources: test.Resources{
+			Namespaces:     []*corev1.Namespace{namespaceResource},
+			EventListeners: []*v1alpha1.EventListener{makeEL(withStatus)},
+			Deployments:    []*appsv1.Deployment{deploymentWithLiveReadinessProbe},
+		},
+		endResources: test.Resources{
+			Namespaces:     []*corev1.Namespace{namespaceResource},
+			EventListeners: []*v1alpha1.EventListener{makeEL(withStatus)},
+			Deployments:    []*appsv1.Deployment{elDeployment},
+			Services:       []*corev1.Service{elService},
+			ConfigMaps:     []*corev1.ConfigMap{loggingConfigMap},
+		},
 	}, {
 		// Checks that we do not overwrite replicas changed on the deployment itself when replicas provided as part of eventlistener spec
 		name: "deployment-replica-update-unsuccessful",
@@ -798,6 +917,55 @@ func TestReconcile(t *testing.T) {
 			Deployments:    []*appsv1.Deployment{deploymentForKubernetesResourceObjectMeta},
 			Services:       []*corev1.Service{elService},
 		},
+	}, {
+		name: "eventlistener overriding the metrics annotations",
+		key:  reconcileKey,
+		startResources: test.Resources{
+			Namespaces:     []*corev1.Namespace{namespaceResource},
+			EventListeners: []*v1alpha1.EventListener{elWithMetricsAnnotationOverride},
+			ConfigMaps:     []*corev1.ConfigMap{loggingConfigMap},
+		},
+		endResources: test.Resources{
+			Namespaces:     []*corev1.Namespace{namespaceResource},
+			EventListeners: []*v1alpha1.EventListener{elWithMetricsAnnotationOverride},
+			ConfigMaps:     []*corev1.ConfigMap{loggingConfigMap},
+			Deployments:    []*appsv1.Deployment{deploymentWithMetricsAnnotationOverride},
+			Services:       []*corev1.Service{elService},
+		},
+	}, {
+		name: "eventlistener with TLS",
+		key:  reconcileKey,
+		startResources: test.Resources{
+			Namespaces:     []*corev1.Namespace{namespaceResource},
+			EventListeners: []*v1alpha1.EventListener{makeEL(withStatus, func(el *v1alpha1.EventListener) { el.Spec.TLS = elWithTLS.Spec.TLS })},
+			ConfigMaps:     []*corev1.ConfigMap{loggingConfigMap},
+			Deployments:    []*appsv1.Deployment{elDeployment},
+			Services:       []*corev1.Service{elService},
+		},
+		endResources: test.Resources{
+			Namespaces:     []*corev1.Namespace{namespaceResource},
+			EventListeners: []*v1alpha1.EventListener{elWithTLS},
+			ConfigMaps:     []*corev1.ConfigMap{loggingConfigMap},
+			Deployments:    []*appsv1.Deployment{deploymentWithTLS},
+			Services:       []*corev1.Service{elService},
+		},
+	}, {
+		name: "eventlistener with admin endpoint",
+		key:  reconcileKey,
+		startResources: test.Resources{
+			Namespaces:     []*corev1.Namespace{namespaceResource},
+			EventListeners: []*v1alpha1.EventListener{elWithAdmin},
+			ConfigMaps:     []*corev1.ConfigMap{loggingConfigMap},
+			Deployments:    []*appsv1.Deployment{elDeployment},
+			Services:       []*corev1.Service{elService},
+		},
+		endResources: test.Resources{
+			Namespaces:     []*corev1.Namespace{namespaceResource},
+			EventListeners: []*v1alpha1.EventListener{elWithAdmin},
+			ConfigMaps:     []*corev1.ConfigMap{loggingConfigMap},
+			Deployments:    []*appsv1.Deployment{deploymentWithAdmin},
+			Services:       []*corev1.Service{elService},
+		},
 	}}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -825,6 +993,116 @@ func TestReconcile(t *testing.T) {
 	}
 }
 
+// TestReconcile_SinkArgs checks the arguments that the flags of the
+// controller pass on to the sink, which TestReconcile does not compare.
+func TestReconcile_SinkArgs(t *testing.T) {
+	maxBodySize, maxBatchSize, queueSize, queueWorkers, queueRetries, drainTimeOut, shutdownTimeOut := *ElMaxBodySize, *ElMaxBatchSize, *ElQueueSize, *ElQueueWorkers, *ElQueueRetries, *ElDrainTimeOut, *ElShutdownTimeOut
+	defer func() {
+		*ElMaxBodySize, *ElMaxBatchSize, *ElQueueSize, *ElQueueWorkers, *ElQueueRetries, *ElDrainTimeOut, *ElShutdownTimeOut = maxBodySize, maxBatchSize, queueSize, queueWorkers, queueRetries, drainTimeOut, shutdownTimeOut
+	}()
+	*ElMaxBodySize = 1024
+	*ElMaxBatchSize = 20
+	*ElQueueSize = 50
+	*ElQueueWorkers = 2
+	*ElQueueRetries = 5
+	*ElDrainTimeOut = 60
+	*ElShutdownTimeOut = 30
+
+	testAssets, cancel := getEventListenerTestAssets(t, test.Resources{
+		Namespaces:     []*corev1.Namespace{namespaceResource},
+		EventListeners: []*v1alpha1.EventListener{makeEL(withStatus)},
+	})
+	defer cancel()
+	if err := testAssets.Controller.Reconciler.Reconcile(context.Background(), reconcileKey); err != nil {
+		t.Fatalf("eventlistener.Reconcile() returned error: %s", err)
+	}
+	d, err := testAssets.Clients.Kube.AppsV1().Deployments(namespace).Get(context.Background(), generatedResourceName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Error getting the Deployment: %s", err)
+	}
+
+	args := d.Spec.Template.Spec.Containers[0].Args
+	for _, want := range [][]string{
+		{"-maxbodysize", "1024"},
+		{"-maxbatchsize", "20"},
+		{"-queuesize", "50"},
+		{"-queueworkers", "2"},
+		{"-queueretries", "5"},
+		{"-draintimeout", "60"},
+		{"-shutdowntimeout", "30"},
+	} {
+		if !containsArg(args, want[0], want[1]) {
+			t.Errorf("Deployment args %v do not contain %s %s", args, want[0], want[1])
+		}
+	}
+	// The Pod is not killed before the sink is done draining
+	if got := d.Spec.Template.Spec.TerminationGracePeriodSeconds; got == nil || *got != 90 {
+		t.Errorf("Deployment terminationGracePeriodSeconds = %v, want 90", got)
+	}
+}
+
+// TestReconcile_InterceptorToken checks that the projected ServiceAccount
+// token is only mounted when the controller is told to.
+func TestReconcile_InterceptorToken(t *testing.T) {
+	interceptorToken := *ElInterceptorToken
+	defer func() { *ElInterceptorToken = interceptorToken }()
+
+	for _, enabled := range []bool{false, true} {
+		t.Run(strconv.FormatBool(enabled), func(t *testing.T) {
+			*ElInterceptorToken = enabled
+			testAssets, cancel := getEventListenerTestAssets(t, test.Resources{
+				Namespaces:     []*corev1.Namespace{namespaceResource},
+				EventListeners: []*v1alpha1.EventListener{makeEL(withStatus)},
+			})
+			defer cancel()
+			if err := testAssets.Controller.Reconciler.Reconcile(context.Background(), reconcileKey); err != nil {
+				t.Fatalf("eventlistener.Reconcile() returned error: %s", err)
+			}
+			d, err := testAssets.Clients.Kube.AppsV1().Deployments(namespace).Get(context.Background(), generatedResourceName, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("Error getting the Deployment: %s", err)
+			}
+
+			podSpec := d.Spec.Template.Spec
+			if got := containsArg(podSpec.Containers[0].Args, "-interceptortokenfile", eventListenerInterceptorTokenMountPath+"/token"); got != enabled {
+				t.Errorf("Deployment args %v contain -interceptortokenfile: %t, want %t", podSpec.Containers[0].Args, got, enabled)
+			}
+			var token *corev1.Volume
+			for i := range podSpec.Volumes {
+				if podSpec.Volumes[i].Name == "interceptor-token" {
+					token = &podSpec.Volumes[i]
+				}
+			}
+			if !enabled {
+				if token != nil {
+					t.Errorf("Deployment has the interceptor-token volume when it is disabled")
+				}
+				return
+			}
+			if token == nil || token.Projected == nil {
+				t.Fatalf("Deployment volumes %v do not contain the projected interceptor-token volume", podSpec.Volumes)
+			}
+			want := &corev1.ServiceAccountTokenProjection{
+				Audience:          interceptors.TokenAudience,
+				ExpirationSeconds: ptr.Int64(3600),
+				Path:              "token",
+			}
+			if diff := cmp.Diff(want, token.Projected.Sources[0].ServiceAccountToken); diff != "" {
+				t.Errorf("interceptor-token volume (-want +got): %s", diff)
+			}
+		})
+	}
+}
+
+func containsArg(args []string, name, value string) bool {
+	for i := 0; i < len(args)-1; i++ {
+		if args[i] == name && args[i+1] == value {
+			return true
+		}
+	}
+	return false
+}
+
 func TestReconcile_Delete(t *testing.T) {
 	tests := []struct {
 		name           string
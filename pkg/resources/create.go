@@ -19,8 +19,12 @@ package resources
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net"
+	"net/http"
 	"strings"
+	"time"
 
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 
@@ -29,12 +33,29 @@ import (
 	"go.uber.org/zap"
 
 	triggersv1 "github.com/tektoncd/triggers/pkg/apis/triggers/v1alpha1"
+	"github.com/tektoncd/triggers/pkg/metrics"
+	"github.com/tektoncd/triggers/pkg/tracing"
+	"go.opentelemetry.io/otel/label"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
 	discoveryclient "k8s.io/client-go/discovery"
 )
 
+const (
+	// defaultMaxAttempts is the number of attempts at creating a resource
+	// when the RetryPolicy of the Trigger does not set it. Resources are
+	// created in a single attempt when the Trigger has no RetryPolicy.
+	defaultMaxAttempts = 3
+	// defaultRetryBackoff is the delay before retrying to create a resource
+	// the first time.
+	defaultRetryBackoff = time.Second
+	// maxRetryBackoff is the longest delay between two attempts at creating
+	// a resource.
+	maxRetryBackoff = 30 * time.Second
+)
+
 // findAPIResource returns the APIResource definition using the discovery client c.
 func findAPIResource(apiVersion, kind string, c discoveryclient.ServerResourcesInterface) (*metav1.APIResource, error) {
 	resourceList, err := c.ServerResourcesForGroupVersion(apiVersion)
@@ -61,13 +82,83 @@ func findAPIResource(apiVersion, kind string, c discoveryclient.ServerResourcesI
 	return nil, fmt.Errorf("error could not find resource with apiVersion %s and kind %s", apiVersion, kind)
 }
 
+// Retry configures how creating a resource is retried. Its Steps are the
+// maximum number of attempts.
+type Retry struct {
+	wait.Backoff
+	// GeneratedNames retries creating resources named with a generateName
+	// after errors that do not tell whether the resource was created.
+	GeneratedNames bool
+}
+
+// RetryFor returns how creating a resource is retried under policy. Without
+// a policy, resources are created in a single attempt.
+func RetryFor(policy *triggersv1.RetryPolicy) Retry {
+	retry := Retry{Backoff: wait.Backoff{
+		Duration: defaultRetryBackoff,
+		Factor:   2,
+		Jitter:   0.1,
+		Steps:    1,
+		Cap:      maxRetryBackoff,
+	}}
+	if policy != nil {
+		retry.Steps = defaultMaxAttempts
+		if policy.MaxAttempts > 0 {
+			retry.Steps = int(policy.MaxAttempts)
+		}
+		if policy.Backoff != nil {
+			retry.Duration = policy.Backoff.Duration
+		}
+		retry.GeneratedNames = policy.RetryGeneratedNames
+	}
+	return retry
+}
+
+// retryable reports whether creating data may succeed if it is attempted
+// again after failing with err. Server errors and timeouts do not tell
+// whether the resource was created, so they are only retried for resources
+// with a fixed name, which cannot be created twice, unless generatedNames
+// is set.
+func retryable(err error, data *unstructured.Unstructured, generatedNames bool) bool {
+	generated := data.GetName() == "" && data.GetGenerateName() != ""
+	switch {
+	case kerrors.IsTooManyRequests(err), kerrors.IsServiceUnavailable(err):
+		// The API server did not process the request
+		return true
+	case kerrors.IsAlreadyExists(err), kerrors.IsConflict(err):
+		// Only a name generated by the API server may not conflict again
+		return generated
+	}
+	if generated && !generatedNames {
+		return false
+	}
+	switch {
+	case kerrors.IsServerTimeout(err), kerrors.IsTimeout(err), kerrors.IsInternalError(err), kerrors.IsUnexpectedServerError(err):
+		return true
+	}
+	var status kerrors.APIStatus
+	if errors.As(err, &status) {
+		return status.Status().Code >= http.StatusInternalServerError
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
 // Create uses the kubeClient to create the resource defined in the
-// TriggerResourceTemplate and returns any errors with this process
-func Create(logger *zap.SugaredLogger, rt json.RawMessage, triggerName, eventID, elName, elNamespace string, c discoveryclient.ServerResourcesInterface, dc dynamic.Interface) error {
+// TriggerResourceTemplate and returns the created resource, or any errors
+// with this process. Creating the resource is attempted up to retry.Steps
+// times while it fails with a transient error. If dryRun is true, the
+// resource is created with a server-side dry run and is not persisted. If ctx
+// holds a sampled trace span, the ID of its trace is added to the resource's
+// annotations.
+func Create(ctx context.Context, logger *zap.SugaredLogger, rt json.RawMessage, triggerName, eventID, elName, elNamespace string, c discoveryclient.ServerResourcesInterface, dc dynamic.Interface, retry Retry, dryRun bool) (_ *unstructured.Unstructured, err error) {
+	ctx, span := tracing.StartSpan(ctx, "CreateResource")
+	defer func() { tracing.EndSpan(span, err) }()
+
 	// Assume the TriggerResourceTemplate is valid (it has an apiVersion and Kind)
 	data := new(unstructured.Unstructured)
 	if err := data.UnmarshalJSON(rt); err != nil {
-		return fmt.Errorf("couldn't unmarshal json: %v", err)
+		return nil, fmt.Errorf("couldn't unmarshal json: %v", err)
 	}
 
 	data = addLabels(data, map[string]string{
@@ -75,6 +166,11 @@ func Create(logger *zap.SugaredLogger, rt json.RawMessage, triggerName, eventID,
 		triggersv1.EventIDLabelKey:       eventID,
 		triggersv1.TriggerLabelKey:       triggerName,
 	})
+	if traceID := tracing.TraceID(ctx); traceID != "" {
+		data = addAnnotations(data, map[string]string{
+			triggersv1.TraceIDAnnotationKey: traceID,
+		})
+	}
 
 	namespace := data.GetNamespace()
 	// Default the resource creation to the EventListenerNamespace if not found in the resource template
@@ -83,9 +179,11 @@ func Create(logger *zap.SugaredLogger, rt json.RawMessage, triggerName, eventID,
 	}
 
 	// Resolve resource kind to the underlying API Resource type.
+	_, discoverySpan := tracing.StartSpan(ctx, "findAPIResource")
 	apiResource, err := findAPIResource(data.GetAPIVersion(), data.GetKind(), c)
+	tracing.EndSpan(discoverySpan, err)
 	if err != nil {
-		return fmt.Errorf("couldn't find API resource for json: %v", err)
+		return nil, fmt.Errorf("couldn't find API resource for json: %v", err)
 	}
 
 	name := data.GetName()
@@ -100,15 +198,51 @@ func Create(logger *zap.SugaredLogger, rt json.RawMessage, triggerName, eventID,
 		Resource: apiResource.Name,
 	}
 
-	logger.Infof("For event ID %q creating resource %v", eventID, gvr)
+	opts := metav1.CreateOptions{}
+	if dryRun {
+		opts.DryRun = []string{metav1.DryRunAll}
+		logger.Infof("For event ID %q creating resource %v with a dry run", eventID, gvr)
+	} else {
+		logger.Infof("For event ID %q creating resource %v", eventID, gvr)
+	}
+	span.SetAttributes(
+		label.String("resource", gvr.GroupVersion().String()+"/"+gvr.Resource),
+		label.String("namespace", namespace),
+		label.String("name", name),
+		label.Bool("dryrun", dryRun),
+	)
 
-	if _, err := dc.Resource(gvr).Namespace(namespace).Create(context.Background(), data, metav1.CreateOptions{}); err != nil {
+	var created *unstructured.Unstructured
+	backoff := retry.Backoff
+	maxAttempts := backoff.Steps
+	for attempt := 1; ; attempt++ {
+		start := time.Now()
+		created, err = dc.Resource(gvr).Namespace(namespace).Create(ctx, data, opts)
+		metrics.RecordResourceCreateLatency(elName, triggerName, gvr.GroupVersion().String()+"/"+gvr.Resource, time.Since(start))
+		if err == nil || attempt >= maxAttempts || !retryable(err, data, retry.GeneratedNames) {
+			span.SetAttributes(label.Int64("attempts", int64(attempt)))
+			break
+		}
+		delay := backoff.Step()
+		if seconds, ok := kerrors.SuggestsClientDelay(err); ok && time.Duration(seconds)*time.Second > delay {
+			delay = time.Duration(seconds) * time.Second
+		}
+		logger.Infof("Retrying to create resource %v after %s (attempt %d of %d): %v", gvr, delay, attempt+1, maxAttempts, err)
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("couldn't create resource with group version kind %q: %v", gvr, err)
+		case <-timer.C:
+		}
+	}
+	if err != nil {
 		if kerrors.IsUnauthorized(err) || kerrors.IsForbidden(err) {
-			return err
+			return nil, err
 		}
-		return fmt.Errorf("couldn't create resource with group version kind %q: %v", gvr, err)
+		return nil, fmt.Errorf("couldn't create resource with group version kind %q: %v", gvr, err)
 	}
-	return nil
+	return created, nil
 }
 
 // addLabels adds autogenerated Tekton labels to created resources.
@@ -125,3 +259,18 @@ func addLabels(us *unstructured.Unstructured, labelsToAdd map[string]string) *un
 	us.SetLabels(labels)
 	return us
 }
+
+// addAnnotations adds autogenerated Tekton annotations to created resources.
+func addAnnotations(us *unstructured.Unstructured, annotationsToAdd map[string]string) *unstructured.Unstructured {
+	annotations := us.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	for k, v := range annotationsToAdd {
+		a := fmt.Sprintf("%s/%s", triggersv1.GroupName, strings.TrimLeft(k, "/"))
+		annotations[a] = v
+	}
+
+	us.SetAnnotations(annotations)
+	return us
+}
@@ -17,20 +17,29 @@ limitations under the License.
 package resources
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	resourcev1 "github.com/tektoncd/pipeline/pkg/apis/resource/v1alpha1"
 	triggersv1 "github.com/tektoncd/triggers/pkg/apis/triggers/v1alpha1"
 	dynamicclientset "github.com/tektoncd/triggers/pkg/client/dynamic/clientset"
 	"github.com/tektoncd/triggers/pkg/client/dynamic/clientset/tekton"
+	"github.com/tektoncd/triggers/pkg/tracing"
 	"github.com/tektoncd/triggers/test"
+	"go.opentelemetry.io/otel/api/global"
+	"go.opentelemetry.io/otel/api/trace"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
 	fakedynamic "k8s.io/client-go/dynamic/fake"
 	fakekubeclientset "k8s.io/client-go/kubernetes/fake"
 	ktesting "k8s.io/client-go/testing"
@@ -140,9 +149,10 @@ func TestCreateResource(t *testing.T) {
 	logger, _ := logging.NewLogger("", "")
 
 	tests := []struct {
-		name string
-		json []byte
-		want resourcev1.PipelineResource
+		name   string
+		json   []byte
+		traced bool
+		want   resourcev1.PipelineResource
 	}{{
 		name: "PipelineResource without namespace",
 		json: json.RawMessage(`{"kind":"PipelineResource","apiVersion":"tekton.dev/v1alpha1","metadata":{"name":"my-pipelineresource","creationTimestamp":null,"labels":{"woriginal-label-1":"label-1"}},"spec":{"type":"","params":[{"name":"foo","value":"bar\r\nbaz"}]},"status":{}}`),
@@ -189,11 +199,43 @@ func TestCreateResource(t *testing.T) {
 			Spec:   resourcev1.PipelineResourceSpec{},
 			Status: &resourcev1.PipelineResourceStatus{},
 		},
+	}, {
+		name:   "PipelineResource with a sampled trace",
+		json:   json.RawMessage(`{"kind":"PipelineResource","apiVersion":"tekton.dev/v1alpha1","metadata":{"name":"my-traced-pipelineresource","creationTimestamp":null},"spec":{"type":"","params":null},"status":{}}`),
+		traced: true,
+		want: resourcev1.PipelineResource{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: "tekton.dev/v1alpha1",
+				Kind:       "PipelineResource",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "my-traced-pipelineresource",
+				Labels: map[string]string{
+					resourceLabel: elName,
+					triggerLabel:  triggerName,
+					eventIDLabel:  eventID,
+				},
+			},
+			Spec:   resourcev1.PipelineResourceSpec{},
+			Status: &resourcev1.PipelineResourceStatus{},
+		},
 	}}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			dynamicClient.ClearActions()
-			if err := Create(logger, tt.json, triggerName, eventID, elName, elNamespace, kubeClient.Discovery(), dynamicSet); err != nil {
+			ctx := context.Background()
+			if tt.traced {
+				// The provider of the SDK samples every span by default
+				global.SetTracerProvider(sdktrace.NewTracerProvider())
+				var span trace.Span
+				ctx, span = tracing.StartSpan(ctx, "test")
+				defer span.End()
+				tt.want.Annotations = map[string]string{
+					"triggers.tekton.dev/trace-id": span.SpanContext().TraceID.String(),
+				}
+			}
+			created, err := Create(ctx, logger, tt.json, triggerName, eventID, elName, elNamespace, kubeClient.Discovery(), dynamicSet, RetryFor(nil), false)
+			if err != nil {
 				t.Errorf("createResource() returned error: %s", err)
 			}
 
@@ -210,10 +252,156 @@ func TestCreateResource(t *testing.T) {
 			if diff := cmp.Diff(want, dynamicClient.Actions()); diff != "" {
 				t.Error(diff)
 			}
+			if created.GetName() != tt.want.Name || created.GetNamespace() != namespace {
+				t.Errorf("Create() returned %s/%s, want %s/%s", created.GetNamespace(), created.GetName(), namespace, tt.want.Name)
+			}
+		})
+	}
+}
+
+func TestCreateResource_Retry(t *testing.T) {
+	gr := schema.GroupResource{Group: "tekton.dev", Resource: "pipelineresources"}
+	named := json.RawMessage(`{"kind":"PipelineResource","apiVersion":"tekton.dev/v1alpha1","metadata":{"name":"my-pipelineresource"},"spec":{"type":""}}`)
+	generated := json.RawMessage(`{"kind":"PipelineResource","apiVersion":"tekton.dev/v1alpha1","metadata":{"generateName":"my-pipelineresource-"},"spec":{"type":""}}`)
+	tests := []struct {
+		name                string
+		json                json.RawMessage
+		errs                []error
+		maxAttempts         int32
+		retryGeneratedNames bool
+		wantAttempts        int
+		wantErr             bool
+	}{{
+		name:         "too many requests",
+		json:         named,
+		errs:         []error{kerrors.NewTooManyRequests("slow down", 0)},
+		wantAttempts: 2,
+	}, {
+		name:         "server errors",
+		json:         named,
+		errs:         []error{kerrors.NewInternalError(errors.New("etcd")), kerrors.NewServiceUnavailable("unavailable")},
+		wantAttempts: 3,
+	}, {
+		name:         "timeout",
+		json:         named,
+		errs:         []error{kerrors.NewTimeoutError("timeout", 0)},
+		wantAttempts: 2,
+	}, {
+		name:         "generated name server error",
+		json:         generated,
+		errs:         []error{kerrors.NewInternalError(errors.New("etcd"))},
+		wantAttempts: 1,
+		wantErr:      true,
+	}, {
+		name:                "generated name server error retried",
+		json:                generated,
+		errs:                []error{kerrors.NewInternalError(errors.New("etcd")), kerrors.NewTimeoutError("timeout", 0)},
+		retryGeneratedNames: true,
+		wantAttempts:        3,
+	}, {
+		name:         "generated name unavailable",
+		json:         generated,
+		errs:         []error{kerrors.NewServiceUnavailable("unavailable")},
+		wantAttempts: 2,
+	}, {
+		name:         "generated name conflict",
+		json:         generated,
+		errs:         []error{kerrors.NewAlreadyExists(gr, "my-pipelineresource-abcde")},
+		wantAttempts: 2,
+	}, {
+		name:         "name conflict",
+		json:         named,
+		errs:         []error{kerrors.NewAlreadyExists(gr, "my-pipelineresource")},
+		wantAttempts: 1,
+		wantErr:      true,
+	}, {
+		name:         "forbidden",
+		json:         named,
+		errs:         []error{kerrors.NewForbidden(gr, "my-pipelineresource", errors.New("denied"))},
+		wantAttempts: 1,
+		wantErr:      true,
+	}, {
+		name:         "attempts exhausted",
+		json:         named,
+		errs:         []error{kerrors.NewInternalError(errors.New("etcd")), kerrors.NewInternalError(errors.New("etcd"))},
+		maxAttempts:  2,
+		wantAttempts: 2,
+		wantErr:      true,
+	}, {
+		name:         "retries disabled",
+		json:         named,
+		errs:         []error{kerrors.NewInternalError(errors.New("etcd"))},
+		maxAttempts:  1,
+		wantAttempts: 1,
+		wantErr:      true,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kubeClient := fakekubeclientset.NewSimpleClientset()
+			test.AddTektonResources(kubeClient)
+			dynamicClient := fakedynamic.NewSimpleDynamicClient(runtime.NewScheme())
+			attempts := 0
+			dynamicClient.PrependReactor("create", "*", func(action ktesting.Action) (bool, runtime.Object, error) {
+				attempts++
+				if attempts <= len(tt.errs) {
+					return true, nil, tt.errs[attempts-1]
+				}
+				return false, nil, nil
+			})
+			logger, _ := logging.NewLogger("", "")
+			retry := RetryFor(&triggersv1.RetryPolicy{
+				MaxAttempts:         tt.maxAttempts,
+				Backoff:             &metav1.Duration{Duration: time.Millisecond},
+				RetryGeneratedNames: tt.retryGeneratedNames,
+			})
+
+			_, err := Create(context.Background(), logger, tt.json, triggerName, eventID, "foo-el", "bar", kubeClient.Discovery(), dynamicclientset.New(tekton.WithClient(dynamicClient)), retry, false)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Create() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if attempts != tt.wantAttempts {
+				t.Errorf("Create() made %d attempts, want %d", attempts, tt.wantAttempts)
+			}
 		})
 	}
 }
 
+func TestCreateResource_RetryCancelled(t *testing.T) {
+	kubeClient := fakekubeclientset.NewSimpleClientset()
+	test.AddTektonResources(kubeClient)
+	dynamicClient := fakedynamic.NewSimpleDynamicClient(runtime.NewScheme())
+	ctx, cancel := context.WithCancel(context.Background())
+	attempts := 0
+	dynamicClient.PrependReactor("create", "*", func(action ktesting.Action) (bool, runtime.Object, error) {
+		attempts++
+		cancel()
+		return true, nil, kerrors.NewServiceUnavailable("unavailable")
+	})
+	logger, _ := logging.NewLogger("", "")
+	retry := RetryFor(&triggersv1.RetryPolicy{Backoff: &metav1.Duration{Duration: time.Hour}})
+
+	rt := json.RawMessage(`{"kind":"PipelineResource","apiVersion":"tekton.dev/v1alpha1","metadata":{"name":"my-pipelineresource"},"spec":{"type":""}}`)
+	if _, err := Create(ctx, logger, rt, triggerName, eventID, "foo-el", "bar", kubeClient.Discovery(), dynamicclientset.New(tekton.WithClient(dynamicClient)), retry, false); err == nil {
+		t.Error("Create() expected an error once the context is cancelled")
+	}
+	if attempts != 1 {
+		t.Errorf("Create() made %d attempts after the context was cancelled, want 1", attempts)
+	}
+}
+
+func TestRetryFor(t *testing.T) {
+	if got := RetryFor(nil); got.Steps != 1 || got.GeneratedNames {
+		t.Errorf("RetryFor(nil) = %+v, want a single attempt", got)
+	}
+	if got := RetryFor(&triggersv1.RetryPolicy{}); got.Steps != 3 || got.Duration != time.Second {
+		t.Errorf("RetryFor() = %+v, want 3 attempts after one second", got)
+	}
+	got := RetryFor(&triggersv1.RetryPolicy{MaxAttempts: 5, Backoff: &metav1.Duration{Duration: 2 * time.Second}, RetryGeneratedNames: true})
+	if got.Steps != 5 || got.Duration != 2*time.Second || !got.GeneratedNames {
+		t.Errorf("RetryFor() = %+v, want 5 attempts after two seconds retrying generated names", got)
+	}
+}
+
 func Test_AddLabels(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -305,3 +493,58 @@ func Test_AddLabels(t *testing.T) {
 		})
 	}
 }
+
+// optionsRecorder records the options resources are created with, which the
+// fake dynamic client ignores.
+type optionsRecorder struct {
+	dynamic.Interface
+	opts *[]metav1.CreateOptions
+}
+
+func (r optionsRecorder) Resource(gvr schema.GroupVersionResource) dynamic.NamespaceableResourceInterface {
+	return namespaceableOptionsRecorder{NamespaceableResourceInterface: r.Interface.Resource(gvr), opts: r.opts}
+}
+
+type namespaceableOptionsRecorder struct {
+	dynamic.NamespaceableResourceInterface
+	opts *[]metav1.CreateOptions
+}
+
+func (r namespaceableOptionsRecorder) Namespace(ns string) dynamic.ResourceInterface {
+	return resourceOptionsRecorder{ResourceInterface: r.NamespaceableResourceInterface.Namespace(ns), opts: r.opts}
+}
+
+type resourceOptionsRecorder struct {
+	dynamic.ResourceInterface
+	opts *[]metav1.CreateOptions
+}
+
+func (r resourceOptionsRecorder) Create(ctx context.Context, obj *unstructured.Unstructured, opts metav1.CreateOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	*r.opts = append(*r.opts, opts)
+	return r.ResourceInterface.Create(ctx, obj, opts, subresources...)
+}
+
+func TestCreateResource_DryRun(t *testing.T) {
+	rt := json.RawMessage(`{"kind":"PipelineResource","apiVersion":"tekton.dev/v1alpha1","metadata":{"name":"my-pipelineresource"},"spec":{"type":""}}`)
+	for _, dryRun := range []bool{false, true} {
+		t.Run(fmt.Sprintf("dryRun %t", dryRun), func(t *testing.T) {
+			kubeClient := fakekubeclientset.NewSimpleClientset()
+			test.AddTektonResources(kubeClient)
+			dynamicClient := fakedynamic.NewSimpleDynamicClient(runtime.NewScheme())
+			var opts []metav1.CreateOptions
+			dc := optionsRecorder{Interface: dynamicclientset.New(tekton.WithClient(dynamicClient)), opts: &opts}
+			logger, _ := logging.NewLogger("", "")
+
+			if _, err := Create(context.Background(), logger, rt, triggerName, eventID, "foo-el", "bar", kubeClient.Discovery(), dc, RetryFor(nil), dryRun); err != nil {
+				t.Fatalf("Create() unexpected error: %v", err)
+			}
+			want := []metav1.CreateOptions{{}}
+			if dryRun {
+				want = []metav1.CreateOptions{{DryRun: []string{metav1.DryRunAll}}}
+			}
+			if diff := cmp.Diff(want, opts); diff != "" {
+				t.Errorf("Create() options (-want +got): %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,204 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sink
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	triggersv1 "github.com/tektoncd/triggers/pkg/apis/triggers/v1alpha1"
+	"go.uber.org/zap"
+)
+
+// DryRunResult describes the outcome of replaying an event for a single
+// Trigger without creating its resources.
+type DryRunResult struct {
+	TriggerResult
+	// Params are the params resolved from the bindings of the Trigger
+	Params []triggersv1.Param `json:"params,omitempty"`
+	// RenderedResources are the resources that would have been created
+	RenderedResources []json.RawMessage `json:"renderedResources,omitempty"`
+}
+
+// DryRunResponse defines the HTTP body that the admin endpoint responds to
+// replayed events with.
+type DryRunResponse struct {
+	EventListener string         `json:"eventListener"`
+	Namespace     string         `json:"namespace,omitempty"`
+	EventID       string         `json:"eventID"`
+	Triggers      []DryRunResult `json:"triggers"`
+}
+
+// AdminHandler returns the handler of the admin endpoint, which lists the
+// events kept in the EventStore without their credentials (GET /events) and
+// replays one of them without creating resources
+// (POST /events/<eventID>/replay). Requests must send the
+// bearer token held in tokenFile, which is read on each request so that the
+// token can be rotated.
+func (r Sink) AdminHandler(tokenFile string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/events", func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		// The HMAC header of the EventListener is redacted along with the
+		// usual credential headers
+		var headers []string
+		if el, err := r.EventListenerLister.EventListeners(r.EventListenerNamespace).Get(r.EventListenerName); err == nil {
+			if auth := el.Spec.Authentication; auth != nil && auth.HMAC != nil {
+				headers = append(headers, auth.HMAC.Header)
+			}
+		}
+		events := r.EventStore.List()
+		for i := range events {
+			events[i] = events[i].redacted(headers...)
+		}
+		writeAdminResponse(w, http.StatusOK, events, r.Logger)
+	})
+	mux.HandleFunc("/events/", func(w http.ResponseWriter, req *http.Request) {
+		eventID := strings.TrimPrefix(req.URL.Path, "/events/")
+		if !strings.HasSuffix(eventID, "/replay") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if req.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		event, ok := r.EventStore.Get(strings.TrimSuffix(eventID, "/replay"))
+		if !ok {
+			http.Error(w, "event not found", http.StatusNotFound)
+			return
+		}
+		results, err := r.DryRunEvent(req.Context(), event)
+		if err != nil {
+			r.Logger.Errorf("Error replaying event %s: %s", event.EventID, err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeAdminResponse(w, http.StatusOK, DryRunResponse{
+			EventListener: r.EventListenerName,
+			Namespace:     r.EventListenerNamespace,
+			EventID:       event.EventID,
+			Triggers:      results,
+		}, r.Logger)
+	})
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if err := authorizeAdmin(req, tokenFile); err != nil {
+			r.Logger.Infof("Rejected admin request %s %s: %s", req.Method, req.URL.Path, err)
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		mux.ServeHTTP(w, req)
+	})
+}
+
+// authorizeAdmin checks that the request sends the bearer token held in
+// tokenFile.
+func authorizeAdmin(req *http.Request, tokenFile string) error {
+	token, err := ioutil.ReadFile(tokenFile)
+	if err != nil {
+		return fmt.Errorf("failed to read the admin token: %w", err)
+	}
+	want := bytes.TrimSpace(token)
+	if len(want) == 0 {
+		return fmt.Errorf("the admin token in %s is empty", tokenFile)
+	}
+	auth := req.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "Bearer ") {
+		return fmt.Errorf("missing bearer token")
+	}
+	got := []byte(strings.TrimSpace(strings.TrimPrefix(auth, "Bearer ")))
+	if subtle.ConstantTimeCompare(got, want) != 1 {
+		return fmt.Errorf("invalid bearer token")
+	}
+	return nil
+}
+
+func writeAdminResponse(w http.ResponseWriter, code int, body interface{}, log *zap.SugaredLogger) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		log.Errorf("failed to write back admin response: %s", err)
+	}
+}
+
+// DryRunEvent processes the stored event for the Triggers of the
+// EventListener as the sink currently sees them, running their interceptors
+// and resolving their bindings and templates without creating any resources.
+// The event is not rate limited nor deduplicated, and no metrics or
+// CloudEvents are emitted for it.
+func (r Sink) DryRunEvent(ctx context.Context, e StoredEvent) ([]DryRunResult, error) {
+	el, err := r.EventListenerLister.EventListeners(r.EventListenerNamespace).Get(r.EventListenerName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get EventListener %s: %w", r.EventListenerName, err)
+	}
+	request, err := http.NewRequestWithContext(ctx, e.Method, e.URL, bytes.NewReader(e.Body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to rebuild the request of the event: %w", err)
+	}
+	request.Header = e.Header.Clone()
+	if request.Header == nil {
+		request.Header = http.Header{}
+	}
+
+	eventLog := r.Logger.With(zap.String(triggersv1.EventIDLabelKey, e.EventID))
+	eventLog.Infof("Replaying event %s in dry run", e.EventID)
+	results := []DryRunResult{}
+	triggers, rejectCode := routeTriggers(el.Spec.Triggers, request)
+	if rejectCode != 0 {
+		return results, nil
+	}
+	firstMatch := el.Spec.MatchPolicy == triggersv1.FirstMatchPolicy
+	if firstMatch {
+		triggers = byPriority(triggers)
+	}
+	for i := range triggers {
+		result := r.dryRunTrigger(&triggers[i], request.Clone(ctx), e.Body, e.EventID, eventLog)
+		results = append(results, result)
+		if firstMatch && result.matched() {
+			break
+		}
+	}
+	return results, nil
+}
+
+// dryRunTrigger runs the interceptors of the Trigger and resolves its
+// resources, without creating them.
+func (r Sink) dryRunTrigger(t *triggersv1.EventListenerTrigger, request *http.Request, event []byte, eventID string, eventLog *zap.SugaredLogger) DryRunResult {
+	var result DryRunResult
+	t, err := r.resolveTriggerRef(t, &result.TriggerResult)
+	if err != nil {
+		return result
+	}
+	result.DryRun = t.DryRun
+	log := eventLog.With(zap.String(triggersv1.TriggerLabelKey, t.Name))
+	payload, header, extensions, err := r.intercept(t, request, event, eventID, &result.TriggerResult, log)
+	if err != nil || result.Filtered {
+		return result
+	}
+	result.Params, result.RenderedResources, _ = r.renderResources(request.Context(), t, payload, header, extensions, &result.TriggerResult, log)
+	return result
+}
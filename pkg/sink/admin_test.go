@@ -0,0 +1,182 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sink
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	triggersv1 "github.com/tektoncd/triggers/pkg/apis/triggers/v1alpha1"
+	"github.com/tektoncd/triggers/test"
+	bldr "github.com/tektoncd/triggers/test/builder"
+)
+
+func writeAdminToken(t *testing.T, token string) string {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "admin")
+	if err != nil {
+		t.Fatalf("Error creating the token directory: %s", err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+	tokenFile := filepath.Join(dir, "token")
+	if err := ioutil.WriteFile(tokenFile, []byte(token+"\n"), 0600); err != nil {
+		t.Fatalf("Error writing the token: %s", err)
+	}
+	return tokenFile
+}
+
+func TestAdminHandler_Unauthorized(t *testing.T) {
+	sink, _ := getSinkAssets(t, test.Resources{}, "el", DefaultAuthOverride{})
+	sink.EventStore = NewEventStore(1)
+	tests := []struct {
+		name  string
+		token string
+		auth  string
+	}{{
+		name:  "no token sent",
+		token: "secret",
+	}, {
+		name:  "wrong token",
+		token: "secret",
+		auth:  "Bearer other",
+	}, {
+		name:  "not a bearer token",
+		token: "secret",
+		auth:  "Basic secret",
+	}, {
+		name: "empty admin token",
+		auth: "Bearer ",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := sink.AdminHandler(writeAdminToken(t, tt.token))
+			req := httptest.NewRequest(http.MethodGet, "/events", nil)
+			if tt.auth != "" {
+				req.Header.Set("Authorization", tt.auth)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code != http.StatusUnauthorized {
+				t.Errorf("AdminHandler() status = %d, want %d", rec.Code, http.StatusUnauthorized)
+			}
+		})
+	}
+}
+
+func TestAdminHandler_Replay(t *testing.T) {
+	eventBody := `{"head_commit": {"id": "testrevision"}, "repository": {"url": "testurl"}}`
+	tb, tt := getResources(t, "$(body.repository.url)")
+	el := bldr.EventListener("el", namespace, bldr.EventListenerSpec(
+		bldr.EventListenerTrigger("tt", "v1alpha1",
+			bldr.EventListenerTriggerName("my-trigger"),
+			bldr.EventListenerTriggerBinding("tb", "TriggerBinding", "v1alpha1"),
+		)))
+	resources := test.Resources{
+		TriggerBindings:  []*triggersv1.TriggerBinding{tb},
+		TriggerTemplates: []*triggersv1.TriggerTemplate{tt},
+		EventListeners:   []*triggersv1.EventListener{el},
+	}
+	sink, dynamicClient := getSinkAssets(t, resources, el.Name, DefaultAuthOverride{})
+	sink.EventStore = NewEventStore(10)
+
+	ts := httptest.NewServer(http.HandlerFunc(sink.HandleEvent))
+	defer ts.Close()
+	req, err := http.NewRequest(http.MethodPost, ts.URL, bytes.NewReader([]byte(eventBody)))
+	if err != nil {
+		t.Fatalf("Error creating request: %s", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer event-token")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Error sending the event: %s", err)
+	}
+	var sinkResp Response
+	if err := json.NewDecoder(resp.Body).Decode(&sinkResp); err != nil {
+		t.Fatalf("Error decoding the sink response: %s", err)
+	}
+	resp.Body.Close()
+	dynamicClient.ClearActions()
+
+	admin := httptest.NewServer(sink.AdminHandler(writeAdminToken(t, "secret")))
+	defer admin.Close()
+	send := func(method, path string) *http.Response {
+		t.Helper()
+		req, err := http.NewRequest(method, admin.URL+path, nil)
+		if err != nil {
+			t.Fatalf("Error creating request: %s", err)
+		}
+		req.Header.Set("Authorization", "Bearer secret")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Error sending the admin request: %s", err)
+		}
+		return resp
+	}
+
+	resp = send(http.MethodGet, "/events")
+	var events []StoredEvent
+	if err := json.NewDecoder(resp.Body).Decode(&events); err != nil {
+		t.Fatalf("Error decoding the stored events: %s", err)
+	}
+	resp.Body.Close()
+	if len(events) != 1 || events[0].EventID != sinkResp.EventID || string(events[0].Body) != eventBody {
+		t.Errorf("GET /events = %+v, want the event %s", events, sinkResp.EventID)
+	}
+	if len(events) == 1 && events[0].Header.Get("Authorization") != redactedValue {
+		t.Errorf("GET /events Authorization header = %q, want it redacted", events[0].Header.Get("Authorization"))
+	}
+
+	resp = send(http.MethodPost, "/events/"+sinkResp.EventID+"/replay")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("POST /events/%s/replay status = %d, want %d", sinkResp.EventID, resp.StatusCode, http.StatusOK)
+	}
+	var got DryRunResponse
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatalf("Error decoding the replay response: %s", err)
+	}
+	resp.Body.Close()
+	if got.EventID != sinkResp.EventID || len(got.Triggers) != 1 {
+		t.Fatalf("replay response = %+v, want the result of the Trigger", got)
+	}
+	result := got.Triggers[0]
+	if result.Name != "my-trigger" || result.FailedStep != "" {
+		t.Errorf("replay result = %+v, want my-trigger to succeed", result)
+	}
+	if diff := cmp.Diff([]triggersv1.Param{{Name: "url", Value: "testurl"}}, result.Params); diff != "" {
+		t.Errorf("replay params (-want +got): %s", diff)
+	}
+	if len(result.RenderedResources) != 1 || !bytes.Contains(result.RenderedResources[0], []byte("testurl")) {
+		t.Errorf("replay rendered resources = %s, want the resolved PipelineResource", result.RenderedResources)
+	}
+	if actions := dynamicClient.Actions(); len(actions) != 0 {
+		t.Errorf("replay created resources: %v", actions)
+	}
+
+	resp = send(http.MethodPost, "/events/unknown/replay")
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("POST /events/unknown/replay status = %d, want %d", resp.StatusCode, http.StatusNotFound)
+	}
+}
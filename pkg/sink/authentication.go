@@ -0,0 +1,184 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sink
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha1" //nolint:gosec
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"net/http"
+	"strings"
+
+	triggersv1 "github.com/tektoncd/triggers/pkg/apis/triggers/v1alpha1"
+	"github.com/tektoncd/triggers/pkg/interceptors"
+)
+
+// errUnauthenticated is returned for requests whose credentials are missing
+// or invalid.
+var errUnauthenticated = errors.New("request not authenticated")
+
+// authenticateRequest authenticates the request from its headers, for the
+// bearer token and TokenReview modes. Requests signed with an HMAC are
+// verified once their body is read, by verifySignature.
+func (r Sink) authenticateRequest(auth *triggersv1.EventListenerAuthentication, request *http.Request) error {
+	if auth == nil {
+		return nil
+	}
+	switch {
+	case auth.BearerToken != nil:
+		token, err := bearerToken(request)
+		if err != nil {
+			return err
+		}
+		want, err := r.secretToken(request, &auth.BearerToken.SecretRef)
+		if err != nil {
+			return fmt.Errorf("failed to get the bearer token: %w", err)
+		}
+		want = bytes.TrimSpace(want)
+		if len(want) == 0 || subtle.ConstantTimeCompare([]byte(token), want) != 1 {
+			return fmt.Errorf("%w: invalid bearer token", errUnauthenticated)
+		}
+	case auth.TokenReview != nil:
+		token, err := bearerToken(request)
+		if err != nil {
+			return err
+		}
+		return r.reviewToken(auth.TokenReview, request, token)
+	}
+	return nil
+}
+
+// bearerToken returns the token sent in the Authorization header of the
+// request.
+func bearerToken(request *http.Request) (string, error) {
+	authorization := request.Header.Get("Authorization")
+	if len(authorization) < len("Bearer ") || !strings.EqualFold(authorization[:len("Bearer ")], "Bearer ") {
+		return "", fmt.Errorf("%w: missing bearer token", errUnauthenticated)
+	}
+	token := strings.TrimSpace(authorization[len("Bearer "):])
+	if token == "" {
+		return "", fmt.Errorf("%w: missing bearer token", errUnauthenticated)
+	}
+	return token, nil
+}
+
+// secretToken returns the value of the secret authenticating the request,
+// through the secret cache of the sink if any.
+func (r Sink) secretToken(request *http.Request, sr *triggersv1.SecretRef) ([]byte, error) {
+	ctx := request.Context()
+	if r.Secrets != nil {
+		ctx = interceptors.WithSecretCache(ctx, r.Secrets)
+	}
+	return interceptors.GetSecretTokenFromContext(ctx, r.KubeClientSet, sr, r.EventListenerNamespace)
+}
+
+// reviewToken authenticates the token with a TokenReview, checking that it
+// belongs to one of the allowed users.
+func (r Sink) reviewToken(review *triggersv1.TokenReviewAuthentication, request *http.Request, token string) error {
+	reviews := r.TokenReviews
+	if reviews == nil {
+		reviews = interceptors.NewTokenReviewCache(r.KubeClientSet, 0)
+	}
+	status, err := reviews.Review(request.Context(), token, review.Audiences)
+	if err != nil {
+		return err
+	}
+	if !status.Authenticated {
+		if status.Error != "" {
+			return fmt.Errorf("%w: %s", errUnauthenticated, status.Error)
+		}
+		return fmt.Errorf("%w: token not authenticated", errUnauthenticated)
+	}
+	if len(review.Users) == 0 {
+		return nil
+	}
+	for _, user := range review.Users {
+		if user == status.User.Username {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: user %s is not allowed", errUnauthenticated, status.User.Username)
+}
+
+// verifySignature verifies the HMAC of the body of the request, for the HMAC
+// mode.
+func (r Sink) verifySignature(auth *triggersv1.EventListenerAuthentication, request *http.Request, body []byte) error {
+	if auth == nil || auth.HMAC == nil {
+		return nil
+	}
+	algorithm := auth.HMAC.Algorithm
+	if algorithm == "" {
+		algorithm = triggersv1.HMACSHA256
+	}
+	var newHash func() hash.Hash
+	switch algorithm {
+	case triggersv1.HMACSHA1:
+		newHash = sha1.New
+	case triggersv1.HMACSHA256:
+		newHash = sha256.New
+	case triggersv1.HMACSHA512:
+		newHash = sha512.New
+	default:
+		return fmt.Errorf("unknown HMAC algorithm %q", algorithm)
+	}
+
+	header := request.Header.Get(auth.HMAC.Header)
+	if header == "" {
+		return fmt.Errorf("%w: missing signature in %s header", errUnauthenticated, auth.HMAC.Header)
+	}
+	// The signature may be prefixed with its algorithm, as by GitHub
+	signature, err := hex.DecodeString(strings.TrimPrefix(header, algorithm+"="))
+	if err != nil {
+		return fmt.Errorf("%w: signature is not hex encoded", errUnauthenticated)
+	}
+	key, err := r.secretToken(request, &auth.HMAC.SecretRef)
+	if err != nil {
+		return fmt.Errorf("failed to get the HMAC key: %w", err)
+	}
+	if len(key) == 0 {
+		return fmt.Errorf("the HMAC key in Secret %s is empty", auth.HMAC.SecretRef.SecretName)
+	}
+	mac := hmac.New(newHash, key)
+	_, _ = mac.Write(body)
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return fmt.Errorf("%w: invalid signature", errUnauthenticated)
+	}
+	return nil
+}
+
+// writeAuthenticationError responds to a request that failed authentication
+// with err: 401 (Unauthorized) when its credentials are missing or invalid,
+// and 500 (Internal Server Error) when they could not be verified.
+func (r Sink) writeAuthenticationError(response http.ResponseWriter, auth *triggersv1.EventListenerAuthentication, err error) {
+	if !errors.Is(err, errUnauthenticated) {
+		r.Logger.Errorf("Error authenticating request to EventListener %s: %s", r.EventListenerName, err)
+		response.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	r.Logger.Infof("Rejected request to EventListener %s: %s", r.EventListenerName, err)
+	if auth.BearerToken != nil || auth.TokenReview != nil {
+		response.Header().Set("WWW-Authenticate", "Bearer")
+	}
+	response.WriteHeader(http.StatusUnauthorized)
+}
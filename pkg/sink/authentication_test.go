@@ -0,0 +1,282 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sink
+
+import (
+	"crypto/hmac"
+	"crypto/sha1" //nolint:gosec
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	triggersv1 "github.com/tektoncd/triggers/pkg/apis/triggers/v1alpha1"
+	"github.com/tektoncd/triggers/pkg/interceptors"
+	"github.com/tektoncd/triggers/test"
+	bldr "github.com/tektoncd/triggers/test/builder"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakekubeclient "k8s.io/client-go/kubernetes/fake"
+	ktesting "k8s.io/client-go/testing"
+)
+
+func sign(newHash func() hash.Hash, key, body string) string {
+	mac := hmac.New(newHash, []byte(key))
+	_, _ = mac.Write([]byte(body))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestHandleEvent_Authentication(t *testing.T) {
+	const eventBody = `{"repository": {"url": "testurl"}}`
+	secretRef := triggersv1.SecretRef{SecretName: "auth", SecretKey: "key"}
+	bearer := triggersv1.EventListenerAuthentication{
+		BearerToken: &triggersv1.BearerTokenAuthentication{SecretRef: secretRef},
+	}
+	hmacSHA256 := triggersv1.EventListenerAuthentication{
+		HMAC: &triggersv1.HMACAuthentication{SecretRef: secretRef, Header: "X-Signature"},
+	}
+	hmacSHA1 := triggersv1.EventListenerAuthentication{
+		HMAC: &triggersv1.HMACAuthentication{SecretRef: secretRef, Header: "X-Signature", Algorithm: triggersv1.HMACSHA1},
+	}
+	tokenReview := triggersv1.EventListenerAuthentication{
+		TokenReview: &triggersv1.TokenReviewAuthentication{
+			Audiences: []string{"el"},
+			Users:     []string{"system:serviceaccount:ci:builder"},
+		},
+	}
+	tests := []struct {
+		name     string
+		auth     triggersv1.EventListenerAuthentication
+		secret   bool
+		header   http.Header
+		wantCode int
+	}{{
+		name:     "bearer token",
+		auth:     bearer,
+		secret:   true,
+		header:   http.Header{"Authorization": {"Bearer secret"}},
+		wantCode: http.StatusCreated,
+	}, {
+		name:     "wrong bearer token",
+		auth:     bearer,
+		secret:   true,
+		header:   http.Header{"Authorization": {"Bearer other"}},
+		wantCode: http.StatusUnauthorized,
+	}, {
+		name:     "missing bearer token",
+		auth:     bearer,
+		secret:   true,
+		wantCode: http.StatusUnauthorized,
+	}, {
+		name:     "missing bearer token Secret",
+		auth:     bearer,
+		header:   http.Header{"Authorization": {"Bearer secret"}},
+		wantCode: http.StatusInternalServerError,
+	}, {
+		name:     "HMAC prefixed with the algorithm",
+		auth:     hmacSHA256,
+		secret:   true,
+		header:   http.Header{"X-Signature": {"sha256=" + sign(sha256.New, "secret", eventBody)}},
+		wantCode: http.StatusCreated,
+	}, {
+		name:     "HMAC with sha1",
+		auth:     hmacSHA1,
+		secret:   true,
+		header:   http.Header{"X-Signature": {sign(sha1.New, "secret", eventBody)}},
+		wantCode: http.StatusCreated,
+	}, {
+		name:     "HMAC with the wrong algorithm",
+		auth:     hmacSHA256,
+		secret:   true,
+		header:   http.Header{"X-Signature": {sign(sha1.New, "secret", eventBody)}},
+		wantCode: http.StatusUnauthorized,
+	}, {
+		name:     "HMAC with the wrong key",
+		auth:     hmacSHA256,
+		secret:   true,
+		header:   http.Header{"X-Signature": {sign(sha256.New, "other", eventBody)}},
+		wantCode: http.StatusUnauthorized,
+	}, {
+		name:     "missing HMAC",
+		auth:     hmacSHA256,
+		secret:   true,
+		wantCode: http.StatusUnauthorized,
+	}, {
+		name:     "TokenReview",
+		auth:     tokenReview,
+		header:   http.Header{"Authorization": {"Bearer builder"}},
+		wantCode: http.StatusCreated,
+	}, {
+		name:     "TokenReview of a user not allowed",
+		auth:     tokenReview,
+		header:   http.Header{"Authorization": {"Bearer other"}},
+		wantCode: http.StatusUnauthorized,
+	}, {
+		name:     "TokenReview of an invalid token",
+		auth:     tokenReview,
+		header:   http.Header{"Authorization": {"Bearer invalid"}},
+		wantCode: http.StatusUnauthorized,
+	}, {
+		// The ServiceAccount of the EventListener may not create TokenReviews
+		name:     "TokenReview denied",
+		auth:     tokenReview,
+		header:   http.Header{"Authorization": {"Bearer denied"}},
+		wantCode: http.StatusInternalServerError,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tb, tt2 := getResources(t, "$(body.repository.url)")
+			el := bldr.EventListener("el", namespace, bldr.EventListenerSpec(
+				bldr.EventListenerAuthentication(tt.auth),
+				bldr.EventListenerTrigger("tt", "v1alpha1",
+					bldr.EventListenerTriggerBinding("tb", "TriggerBinding", "v1alpha1"),
+				),
+			))
+			resources := test.Resources{
+				TriggerBindings:  []*triggersv1.TriggerBinding{tb},
+				TriggerTemplates: []*triggersv1.TriggerTemplate{tt2},
+				EventListeners:   []*triggersv1.EventListener{el},
+			}
+			if tt.secret {
+				resources.Secrets = []*corev1.Secret{{
+					ObjectMeta: metav1.ObjectMeta{Name: "auth", Namespace: namespace},
+					Data:       map[string][]byte{"key": []byte("secret")},
+				}}
+			}
+			sink, dynamicClient := getSinkAssets(t, resources, el.Name, DefaultAuthOverride{})
+			sink.KubeClientSet.(*fakekubeclient.Clientset).PrependReactor("create", "tokenreviews", func(action ktesting.Action) (bool, runtime.Object, error) {
+				review := action.(ktesting.CreateAction).GetObject().(*authenticationv1.TokenReview)
+				if len(review.Spec.Audiences) != 1 || review.Spec.Audiences[0] != "el" {
+					t.Errorf("TokenReview audiences = %v, want [el]", review.Spec.Audiences)
+				}
+				switch review.Spec.Token {
+				case "denied":
+					return true, nil, apierrors.NewForbidden(authenticationv1.Resource("tokenreviews"), "", errors.New("not allowed to create tokenreviews"))
+				case "builder":
+					review.Status.Authenticated = true
+					review.Status.User.Username = "system:serviceaccount:ci:builder"
+				case "other":
+					review.Status.Authenticated = true
+					review.Status.User.Username = "system:serviceaccount:ci:other"
+				default:
+					review.Status.Error = "invalid token"
+				}
+				return true, review, nil
+			})
+			ts := httptest.NewServer(http.HandlerFunc(sink.HandleEvent))
+			defer ts.Close()
+
+			req, err := http.NewRequest(http.MethodPost, ts.URL, strings.NewReader(eventBody))
+			if err != nil {
+				t.Fatalf("Error creating request: %s", err)
+			}
+			for k, v := range tt.header {
+				req.Header[k] = v
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("Error sending the event: %s", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.wantCode {
+				t.Errorf("HandleEvent() status = %d, want %d", resp.StatusCode, tt.wantCode)
+			}
+			if tt.wantCode != http.StatusCreated && len(dynamicClient.Actions()) != 0 {
+				t.Errorf("HandleEvent() created resources for an unauthenticated request: %v", dynamicClient.Actions())
+			}
+		})
+	}
+}
+
+// TestHandleEvent_AuthenticationCache checks that the secrets and the
+// TokenReviews authenticating events are not read for every event.
+func TestHandleEvent_AuthenticationCache(t *testing.T) {
+	for name, auth := range map[string]triggersv1.EventListenerAuthentication{
+		"bearer token": {
+			BearerToken: &triggersv1.BearerTokenAuthentication{SecretRef: triggersv1.SecretRef{SecretName: "auth", SecretKey: "key"}},
+		},
+		"TokenReview": {
+			TokenReview: &triggersv1.TokenReviewAuthentication{Audiences: []string{"el"}},
+		},
+	} {
+		auth := auth
+		t.Run(name, func(t *testing.T) {
+			tb, tt := getResources(t, "$(body.repository.url)")
+			el := bldr.EventListener("el", namespace, bldr.EventListenerSpec(
+				bldr.EventListenerAuthentication(auth),
+				bldr.EventListenerTrigger("tt", "v1alpha1",
+					bldr.EventListenerTriggerBinding("tb", "TriggerBinding", "v1alpha1"),
+				),
+			))
+			sink, _ := getSinkAssets(t, test.Resources{
+				TriggerBindings:  []*triggersv1.TriggerBinding{tb},
+				TriggerTemplates: []*triggersv1.TriggerTemplate{tt},
+				EventListeners:   []*triggersv1.EventListener{el},
+				Secrets: []*corev1.Secret{{
+					ObjectMeta: metav1.ObjectMeta{Name: "auth", Namespace: namespace},
+					Data:       map[string][]byte{"key": []byte("secret")},
+				}},
+			}, el.Name, DefaultAuthOverride{})
+			sink.Secrets = interceptors.NewSecretCache(time.Minute)
+			sink.TokenReviews = interceptors.NewTokenReviewCache(sink.KubeClientSet, time.Minute)
+			kubeClient := sink.KubeClientSet.(*fakekubeclient.Clientset)
+			kubeClient.PrependReactor("create", "tokenreviews", func(action ktesting.Action) (bool, runtime.Object, error) {
+				review := action.(ktesting.CreateAction).GetObject().(*authenticationv1.TokenReview)
+				review.Status.Authenticated = true
+				return true, review, nil
+			})
+			ts := httptest.NewServer(http.HandlerFunc(sink.HandleEvent))
+			defer ts.Close()
+
+			kubeClient.ClearActions()
+			for i := 0; i < 3; i++ {
+				req, err := http.NewRequest(http.MethodPost, ts.URL, strings.NewReader(`{"repository": {"url": "testurl"}}`))
+				if err != nil {
+					t.Fatalf("Error creating request: %s", err)
+				}
+				req.Header.Set("Authorization", "Bearer secret")
+				resp, err := http.DefaultClient.Do(req)
+				if err != nil {
+					t.Fatalf("Error sending the event: %s", err)
+				}
+				resp.Body.Close()
+				// The resources of the events after the first one already
+				// exist, so only the authentication is checked
+				if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusInternalServerError {
+					t.Errorf("HandleEvent() status = %d, want the event authenticated", resp.StatusCode)
+				}
+			}
+			reads := 0
+			for _, action := range kubeClient.Actions() {
+				if action.Matches("get", "secrets") || action.Matches("create", "tokenreviews") {
+					reads++
+				}
+			}
+			if reads != 1 {
+				t.Errorf("authenticating 3 events made %d requests to Kubernetes, want 1", reads)
+			}
+		})
+	}
+}
@@ -0,0 +1,194 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sink
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	triggersv1 "github.com/tektoncd/triggers/pkg/apis/triggers/v1alpha1"
+	"github.com/tektoncd/triggers/pkg/metrics"
+	"github.com/tektoncd/triggers/pkg/tracing"
+	"go.opentelemetry.io/otel/label"
+)
+
+// BatchPath is the path of the endpoint that accepts batches of events. The
+// events of a batch sent to a path under it are processed as if they were
+// sent to the rest of the path.
+const BatchPath = "/batch"
+
+// errInvalidBatch is returned for batches that are neither a JSON array nor
+// newline delimited JSON.
+var errInvalidBatch = errors.New("invalid batch of events")
+
+// BatchResponse defines the HTTP body that the Sink responds to batches of
+// events with.
+type BatchResponse struct {
+	// EventListener is the name of the eventListener
+	EventListener string `json:"eventListener"`
+	// Namespace is the namespace that the eventListener is running in
+	Namespace string `json:"namespace,omitempty"`
+	// Events holds the outcome of each event of the batch, in order
+	Events []BatchEventResult `json:"events"`
+}
+
+// BatchEventResult describes the outcome of processing an event of a batch.
+type BatchEventResult struct {
+	// Code is the status code that the event would have been responded to
+	// with had it been sent on its own
+	Code int `json:"code"`
+	// EventID is the uniqueID assigned to the event
+	EventID string `json:"eventID,omitempty"`
+	// Duplicate is true when the event is a redelivery of the event with
+	// EventID, which was already processed
+	Duplicate bool `json:"duplicate,omitempty"`
+	// Triggers holds the result of processing the event for each of the
+	// EventListener's Triggers
+	Triggers []TriggerResult `json:"triggers,omitempty"`
+}
+
+// HandleBatch processes a batch of events sent as a JSON array, or as
+// newline delimited JSON. Each event of the batch is processed in order as if
+// it were sent on its own, with the header of the batch. The events of a batch
+// of CloudEvents are CloudEvents in structured content mode. Batches of more
+// than MaxBatchSize events are rejected.
+func (r Sink) HandleBatch(response http.ResponseWriter, request *http.Request) {
+	ctx, span := tracing.StartSpanFromRequest(request, "HandleBatch")
+	defer span.End()
+	span.SetAttributes(label.String("eventlistener", r.EventListenerName))
+	// The events are routed to the Triggers as if they were sent to the path
+	// under the batch endpoint
+	request = request.Clone(ctx)
+	request.URL.Path = strings.TrimPrefix(request.URL.Path, BatchPath)
+	if request.URL.Path == "" {
+		request.URL.Path = "/"
+	}
+	request.URL.RawPath = ""
+
+	request, el, triggers, body, ok := r.admitRequest(response, request)
+	if !ok {
+		return
+	}
+	mediaType, _, _ := mime.ParseMediaType(request.Header.Get("Content-Type"))
+	events, err := splitBatch(mediaType, body)
+	if err != nil {
+		r.Logger.Errorf("Error reading batch of events: %s", err)
+		response.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if r.MaxBatchSize > 0 && len(events) > r.MaxBatchSize {
+		r.Logger.Errorf("Rejected batch of %d events, more than the maximum of %d", len(events), r.MaxBatchSize)
+		http.Error(response, fmt.Sprintf("batch of %d events exceeds the maximum of %d", len(events), r.MaxBatchSize), http.StatusRequestEntityTooLarge)
+		return
+	}
+	eventType := "application/json"
+	if mediaType == cloudEventsBatchJSON {
+		eventType = cloudEventsJSON
+	}
+
+	var delay time.Duration
+	results := make([]BatchEventResult, 0, len(events))
+	for i, event := range events {
+		metrics.RecordEvent(r.EventListenerName)
+		code, res, d := r.processBatchEvent(el, triggers, request, i, eventType, event)
+		if code == http.StatusTooManyRequests && d > delay {
+			delay = d
+		}
+		results = append(results, BatchEventResult{
+			Code:      code,
+			EventID:   res.EventID,
+			Duplicate: res.Duplicate,
+			Triggers:  res.Triggers,
+		})
+	}
+	if delay > 0 {
+		setRetryAfter(response, delay)
+	}
+
+	response.Header().Set("Content-Type", "application/json")
+	response.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(response).Encode(BatchResponse{
+		EventListener: r.EventListenerName,
+		Namespace:     r.EventListenerNamespace,
+		Events:        results,
+	}); err != nil {
+		r.Logger.Errorf("failed to write back sink response: %s", err)
+	}
+}
+
+// processBatchEvent processes the event at index i of the batch sent with
+// request, in its own span, as if it were sent on its own with the
+// contentType.
+func (r Sink) processBatchEvent(el *triggersv1.EventListener, triggers []triggersv1.EventListenerTrigger, request *http.Request, i int, contentType string, event []byte) (int, Response, time.Duration) {
+	ctx, span := tracing.StartSpan(request.Context(), "HandleBatchEvent")
+	defer span.End()
+	eventRequest := request.Clone(withBatchIndex(ctx, i))
+	eventRequest.Body = ioutil.NopCloser(bytes.NewReader(event))
+	eventRequest.ContentLength = int64(len(event))
+	eventRequest.Header.Set("Content-Type", contentType)
+	event, err := toBinaryCloudEvent(eventRequest, event)
+	if err != nil {
+		r.Logger.Errorf("Error reading event of batch: %s", err)
+		return http.StatusBadRequest, Response{}, 0
+	}
+	return r.processEvent(el, triggers, eventRequest, event)
+}
+
+// splitBatch returns the events of a batch, which is newline delimited JSON
+// when its media type is application/x-ndjson or application/ndjson, and a
+// JSON array otherwise.
+func splitBatch(mediaType string, body []byte) ([][]byte, error) {
+	if mediaType != "application/x-ndjson" && mediaType != "application/ndjson" {
+		var events []json.RawMessage
+		if err := json.Unmarshal(body, &events); err != nil {
+			return nil, fmt.Errorf("%w: %v", errInvalidBatch, err)
+		}
+		split := make([][]byte, 0, len(events))
+		for _, e := range events {
+			split = append(split, e)
+		}
+		return split, nil
+	}
+
+	var events [][]byte
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	// Events are bounded by the maximum size of the body rather than by the
+	// default size of a token
+	scanner.Buffer(nil, len(body)+1)
+	for line := 1; scanner.Scan(); line++ {
+		event := bytes.TrimSpace(scanner.Bytes())
+		if len(event) == 0 {
+			continue
+		}
+		if !json.Valid(event) {
+			return nil, fmt.Errorf("%w: line %d is not valid JSON", errInvalidBatch, line)
+		}
+		events = append(events, append([]byte(nil), event...))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", errInvalidBatch, err)
+	}
+	return events, nil
+}
@@ -0,0 +1,250 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sink
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	triggersv1 "github.com/tektoncd/triggers/pkg/apis/triggers/v1alpha1"
+	"github.com/tektoncd/triggers/test"
+	bldr "github.com/tektoncd/triggers/test/builder"
+	"k8s.io/apimachinery/pkg/runtime"
+	ktesting "k8s.io/client-go/testing"
+)
+
+func TestSplitBatch(t *testing.T) {
+	tests := []struct {
+		name      string
+		mediaType string
+		body      string
+		want      []string
+		wantErr   bool
+	}{{
+		name: "JSON array",
+		body: `[{"id": 1}, {"id": 2}]`,
+		want: []string{`{"id": 1}`, `{"id": 2}`},
+	}, {
+		name: "empty JSON array",
+		body: `[]`,
+		want: []string{},
+	}, {
+		name:    "not a JSON array",
+		body:    `{"id": 1}`,
+		wantErr: true,
+	}, {
+		name:      "NDJSON",
+		mediaType: "application/x-ndjson",
+		body:      "{\"id\": 1}\r\n\n{\"id\": 2}\n",
+		want:      []string{`{"id": 1}`, `{"id": 2}`},
+	}, {
+		name:      "invalid NDJSON line",
+		mediaType: "application/ndjson",
+		body:      "{\"id\": 1}\n{\"id\":\n",
+		wantErr:   true,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, err := splitBatch(tt.mediaType, []byte(tt.body))
+			if tt.wantErr {
+				if !errors.Is(err, errInvalidBatch) {
+					t.Fatalf("splitBatch() error = %v, want %v", err, errInvalidBatch)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("splitBatch() unexpected error: %v", err)
+			}
+			got := make([]string, 0, len(events))
+			for _, e := range events {
+				got = append(got, string(e))
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("splitBatch() (-want +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestHandleBatch(t *testing.T) {
+	tb, tt := getResources(t, "$(body.repository.url)")
+	el := bldr.EventListener("el", namespace, bldr.EventListenerSpec(
+		bldr.EventListenerTrigger("tt", "v1alpha1",
+			bldr.EventListenerTriggerName("scanner"),
+			bldr.EventListenerTriggerPath("/scanner"),
+			bldr.EventListenerTriggerBinding("tb", "TriggerBinding", "v1alpha1"),
+		),
+	))
+	resources := test.Resources{
+		TriggerBindings:  []*triggersv1.TriggerBinding{tb},
+		TriggerTemplates: []*triggersv1.TriggerTemplate{tt},
+		EventListeners:   []*triggersv1.EventListener{el},
+	}
+	sink, dynamicClient := getSinkAssets(t, resources, el.Name, DefaultAuthOverride{})
+	sink.MaxBatchSize = 2
+	// Every event of the batch creates a resource with the same name
+	dynamicClient.PrependReactor("create", "*", func(action ktesting.Action) (bool, runtime.Object, error) {
+		return true, action.(ktesting.CreateAction).GetObject(), nil
+	})
+
+	tests := []struct {
+		name        string
+		path        string
+		contentType string
+		body        string
+		wantCode    int
+		wantEvents  int
+	}{{
+		name:       "JSON array",
+		path:       "/batch/scanner",
+		body:       `[{"repository": {"url": "one"}}, {"repository": {"url": "two"}}]`,
+		wantCode:   http.StatusOK,
+		wantEvents: 2,
+	}, {
+		name:        "NDJSON",
+		path:        "/batch/scanner",
+		contentType: "application/x-ndjson",
+		body:        "{\"repository\": {\"url\": \"one\"}}\n{\"repository\": {\"url\": \"two\"}}\n",
+		wantCode:    http.StatusOK,
+		wantEvents:  2,
+	}, {
+		name: "CloudEvents",
+		path: "/batch/scanner",
+		body: `[{"specversion": "1.0", "type": "dev.example.scan", "source": "scanner", "id": "1",
+			"data": {"repository": {"url": "one"}}}]`,
+		contentType: "application/cloudevents-batch+json",
+		wantCode:    http.StatusOK,
+		wantEvents:  1,
+	}, {
+		name:     "path without Triggers",
+		path:     "/batch",
+		body:     `[{"repository": {"url": "one"}}]`,
+		wantCode: http.StatusNotFound,
+	}, {
+		name:     "invalid batch",
+		path:     "/batch/scanner",
+		body:     `{"repository": {"url": "one"}}`,
+		wantCode: http.StatusBadRequest,
+	}, {
+		name:     "too many events",
+		path:     "/batch/scanner",
+		body:     `[{"repository": {"url": "one"}}, {"repository": {"url": "two"}}, {"repository": {"url": "three"}}]`,
+		wantCode: http.StatusRequestEntityTooLarge,
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dynamicClient.ClearActions()
+			req := httptest.NewRequest(http.MethodPost, tc.path, strings.NewReader(tc.body))
+			if tc.contentType != "" {
+				req.Header.Set("Content-Type", tc.contentType)
+			}
+			rec := httptest.NewRecorder()
+			sink.HandleBatch(rec, req)
+			if rec.Code != tc.wantCode {
+				t.Fatalf("HandleBatch() status = %d, want %d", rec.Code, tc.wantCode)
+			}
+			if tc.wantCode != http.StatusOK {
+				return
+			}
+
+			var resp BatchResponse
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("Error decoding the batch response: %s", err)
+			}
+			if resp.EventListener != el.Name || resp.Namespace != namespace || len(resp.Events) != tc.wantEvents {
+				t.Fatalf("HandleBatch() response = %+v, want %d events of %s/%s", resp, tc.wantEvents, namespace, el.Name)
+			}
+			for i, e := range resp.Events {
+				if e.Code != http.StatusCreated || e.EventID == "" || len(e.Triggers) != 1 || e.Triggers[0].Name != "scanner" {
+					t.Errorf("HandleBatch() event %d = %+v, want a resource created by scanner", i, e)
+				}
+			}
+
+			prs := getCreatedPipelineResources(t, dynamicClient.Actions())
+			var urls []string
+			for _, pr := range prs {
+				urls = append(urls, pr.Spec.Params[0].Value)
+			}
+			if diff := cmp.Diff([]string{"one", "two"}[:tc.wantEvents], urls); diff != "" {
+				t.Errorf("HandleBatch() created resources for urls (-want +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestHandleBatch_Deduplication(t *testing.T) {
+	tb, tt := getResources(t, "$(body.repository.url)")
+	el := bldr.EventListener("el", namespace, bldr.EventListenerSpec(
+		bldr.EventListenerTrigger("tt", "v1alpha1",
+			bldr.EventListenerTriggerBinding("tb", "TriggerBinding", "v1alpha1"),
+		),
+		bldr.EventListenerDeduplication(triggersv1.Deduplication{Header: "X-GitHub-Delivery"}),
+	))
+	resources := test.Resources{
+		TriggerBindings:  []*triggersv1.TriggerBinding{tb},
+		TriggerTemplates: []*triggersv1.TriggerTemplate{tt},
+		EventListeners:   []*triggersv1.EventListener{el},
+	}
+	sink, dynamicClient := getSinkAssets(t, resources, el.Name, DefaultAuthOverride{})
+	sink.DeliveryStore = NewMemoryDeliveryStore()
+	dynamicClient.PrependReactor("create", "*", func(action ktesting.Action) (bool, runtime.Object, error) {
+		return true, action.(ktesting.CreateAction).GetObject(), nil
+	})
+
+	send := func() BatchResponse {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, BatchPath, strings.NewReader(`[{"repository": {"url": "one"}}, {"repository": {"url": "two"}}]`))
+		req.Header.Set("X-GitHub-Delivery", "abcde")
+		rec := httptest.NewRecorder()
+		sink.HandleBatch(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("HandleBatch() status = %d, want %d", rec.Code, http.StatusOK)
+		}
+		var resp BatchResponse
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatalf("Error decoding the batch response: %s", err)
+		}
+		return resp
+	}
+
+	// The events of the batch share its delivery header, but are not
+	// duplicates of each other
+	for i, e := range send().Events {
+		if e.Code != http.StatusCreated || e.Duplicate {
+			t.Errorf("event %d of the batch = %+v, want a resource created", i, e)
+		}
+	}
+	if got := len(getCreatedPipelineResources(t, dynamicClient.Actions())); got != 2 {
+		t.Errorf("created %d resources for the batch, want 2", got)
+	}
+
+	// Redelivering the batch redelivers each of its events
+	dynamicClient.ClearActions()
+	for i, e := range send().Events {
+		if e.Code != http.StatusOK || !e.Duplicate {
+			t.Errorf("event %d of the redelivered batch = %+v, want a duplicate", i, e)
+		}
+	}
+	if got := len(getCreatedPipelineResources(t, dynamicClient.Actions())); got != 0 {
+		t.Errorf("created %d resources for the redelivered batch, want none", got)
+	}
+}
@@ -0,0 +1,300 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sink
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tektoncd/triggers/pkg/metrics"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/util/uuid"
+)
+
+const (
+	// cloudEventsJSON is the content type of CloudEvents in structured
+	// content mode.
+	cloudEventsJSON = "application/cloudevents+json"
+	// cloudEventsBatchJSON is the content type of batches of CloudEvents in
+	// structured content mode.
+	cloudEventsBatchJSON = "application/cloudevents-batch+json"
+	// ResponseCloudEventType is the type of the CloudEvents the Sink replies
+	// with.
+	ResponseCloudEventType = "dev.tekton.triggers.response"
+
+	// The types of the CloudEvents the Sink sends to the EventListener's
+	// CloudEventURI.
+	EventReceivedCloudEventType    = "dev.tekton.triggers.event.received"
+	TriggerFilteredCloudEventType  = "dev.tekton.triggers.trigger.filtered"
+	ResourcesCreatedCloudEventType = "dev.tekton.triggers.resources.created"
+	TriggerFailedCloudEventType    = "dev.tekton.triggers.trigger.failed"
+	ResourcesDryRunCloudEventType  = "dev.tekton.triggers.resources.dryrun"
+
+	// cloudEventTimeout bounds the time spent sending a CloudEvent.
+	cloudEventTimeout = 10 * time.Second
+	// cloudEventBufferSize is the number of CloudEvents waiting to be sent
+	// beyond which they are dropped, and cloudEventWorkers the number of
+	// CloudEvents sent at once.
+	cloudEventBufferSize = 1000
+	cloudEventWorkers    = 4
+)
+
+// CloudEventSender sends the CloudEvents of the Sink in the background on a
+// fixed number of workers. CloudEvents are dropped when too many are waiting
+// to be sent, so that a slow CloudEventURI does not hold up events.
+type CloudEventSender struct {
+	sends chan func()
+	wg    sync.WaitGroup
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+// NewCloudEventSender returns a CloudEventSender with its workers started.
+func NewCloudEventSender() *CloudEventSender {
+	s := &CloudEventSender{sends: make(chan func(), cloudEventBufferSize)}
+	s.wg.Add(cloudEventWorkers)
+	for i := 0; i < cloudEventWorkers; i++ {
+		go func() {
+			defer s.wg.Done()
+			for send := range s.sends {
+				send()
+			}
+		}()
+	}
+	return s
+}
+
+// add queues send, and returns false if it was dropped.
+func (s *CloudEventSender) add(send func()) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return false
+	}
+	select {
+	case s.sends <- send:
+		return true
+	default:
+		return false
+	}
+}
+
+// Drain stops the CloudEventSender from accepting CloudEvents and waits up
+// to timeout for those already queued to be sent.
+func (s *CloudEventSender) Drain(timeout time.Duration) error {
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.sends)
+	}
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-time.After(timeout):
+		return fmt.Errorf("timed out after %s, %d CloudEvents were not sent", timeout, len(s.sends))
+	}
+}
+
+// LifecycleEvent is the data of the CloudEvents the Sink sends to the
+// EventListener's CloudEventURI. For the Trigger related CloudEvents, it holds
+// the result of processing the event for the Trigger.
+type LifecycleEvent struct {
+	// EventListener is the name of the eventListener
+	EventListener string `json:"eventListener"`
+	// Namespace is the namespace that the eventListener is running in
+	Namespace string `json:"namespace"`
+	// EventID is the uniqueID assigned to the event
+	EventID string `json:"eventID"`
+	TriggerResult
+}
+
+// errInvalidCloudEvent is returned for CloudEvents in structured content mode
+// that cannot be decoded.
+var errInvalidCloudEvent = errors.New("invalid structured CloudEvent")
+
+// isCloudEvent reports whether the headers are those of a CloudEvent in
+// binary content mode.
+func isCloudEvent(header http.Header) bool {
+	return header.Get("Ce-Specversion") != ""
+}
+
+// toBinaryCloudEvent converts a CloudEvent in structured content mode into
+// binary content mode, moving its context attributes into the request's
+// headers and returning its data as the body. Any other body is returned as
+// is.
+func toBinaryCloudEvent(request *http.Request, body []byte) ([]byte, error) {
+	mediaType, _, err := mime.ParseMediaType(request.Header.Get("Content-Type"))
+	if err != nil || mediaType != cloudEventsJSON {
+		return body, nil
+	}
+
+	var attributes map[string]json.RawMessage
+	if err := json.Unmarshal(body, &attributes); err != nil {
+		return nil, fmt.Errorf("%w: %v", errInvalidCloudEvent, err)
+	}
+	if _, ok := attributes["specversion"]; !ok {
+		return nil, fmt.Errorf("%w: missing specversion", errInvalidCloudEvent)
+	}
+	data, hasData := attributes["data"]
+	dataBase64, hasDataBase64 := attributes["data_base64"]
+	delete(attributes, "data")
+	delete(attributes, "data_base64")
+
+	contentType := "application/json"
+	for name, raw := range attributes {
+		// Extension attributes may be numbers or booleans
+		value := string(raw)
+		var str string
+		if err := json.Unmarshal(raw, &str); err == nil {
+			value = str
+		}
+		if name == "datacontenttype" {
+			contentType = value
+			continue
+		}
+		request.Header.Set("Ce-"+name, value)
+	}
+	request.Header.Set("Content-Type", contentType)
+
+	switch {
+	case hasDataBase64:
+		var encoded string
+		if err := json.Unmarshal(dataBase64, &encoded); err != nil {
+			return nil, fmt.Errorf("%w: data_base64 is not a string: %v", errInvalidCloudEvent, err)
+		}
+		decoded, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", errInvalidCloudEvent, err)
+		}
+		return decoded, nil
+	case !hasData:
+		return nil, nil
+	case isJSONContentType(contentType):
+		return data, nil
+	default:
+		// Data that is not JSON is held in a JSON string
+		var str string
+		if err := json.Unmarshal(data, &str); err == nil {
+			return []byte(str), nil
+		}
+		return data, nil
+	}
+}
+
+func isJSONContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || mediaType == "text/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// cloudEventSource returns the source of the CloudEvents sent by the Sink.
+func (r Sink) cloudEventSource() string {
+	return fmt.Sprintf("/apis/triggers.tekton.dev/v1alpha1/namespaces/%s/eventlisteners/%s", r.EventListenerNamespace, r.EventListenerName)
+}
+
+// emitTriggerResult sends the CloudEvent for the outcome of processing the
+// event for a Trigger.
+func (r Sink) emitTriggerResult(eventID string, result TriggerResult, log *zap.SugaredLogger) {
+	eventType := ResourcesCreatedCloudEventType
+	switch result.outcome() {
+	case metrics.OutcomeFiltered:
+		eventType = TriggerFilteredCloudEventType
+	case metrics.OutcomeFailed:
+		eventType = TriggerFailedCloudEventType
+	case metrics.OutcomeDryRun:
+		eventType = ResourcesDryRunCloudEventType
+	}
+	r.emitCloudEvent(eventType, LifecycleEvent{EventID: eventID, TriggerResult: result}, log)
+}
+
+// emitCloudEvent sends a CloudEvent with the given type and data to the
+// EventListener's CloudEventURI, if it has one. The CloudEvent is sent in the
+// background by the CloudEventSender of the Sink, or right away when there is
+// none, and failures are only logged.
+func (r Sink) emitCloudEvent(eventType string, data LifecycleEvent, log *zap.SugaredLogger) {
+	el, err := r.EventListenerLister.EventListeners(r.EventListenerNamespace).Get(r.EventListenerName)
+	if err != nil || el.Spec.CloudEventURI == "" {
+		return
+	}
+	data.EventListener = r.EventListenerName
+	data.Namespace = r.EventListenerNamespace
+	body, err := json.Marshal(data)
+	if err != nil {
+		log.Errorf("Error marshaling %s CloudEvent: %s", eventType, err)
+		return
+	}
+
+	uri := el.Spec.CloudEventURI
+	send := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), cloudEventTimeout)
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, uri, bytes.NewReader(body))
+		if err != nil {
+			log.Errorf("Error creating %s CloudEvent: %s", eventType, err)
+			return
+		}
+		r.setCloudEventHeaders(req.Header, eventType, string(uuid.NewUUID()))
+		if data.Name != "" {
+			req.Header.Set("Ce-Subject", data.Name)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := r.HTTPClient.Do(req)
+		if err != nil {
+			log.Errorf("Error sending %s CloudEvent to %s: %s", eventType, uri, err)
+			return
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			log.Errorf("Error sending %s CloudEvent to %s: unexpected response %s", eventType, uri, resp.Status)
+		}
+	}
+	if r.CloudEvents == nil {
+		send()
+		return
+	}
+	if !r.CloudEvents.add(send) {
+		log.Errorf("Dropped %s CloudEvent to %s: too many CloudEvents are waiting to be sent, or the EventListener is shutting down", eventType, uri)
+	}
+}
+
+// setCloudEventHeaders sets the headers of a CloudEvent in binary content mode
+// with the given type and ID, sent by the Sink.
+func (r Sink) setCloudEventHeaders(header http.Header, eventType, id string) {
+	header.Set("Ce-Specversion", "1.0")
+	header.Set("Ce-Type", eventType)
+	header.Set("Ce-Source", r.cloudEventSource())
+	header.Set("Ce-Id", id)
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sink
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestToBinaryCloudEvent(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		wantHeader  http.Header
+		wantBody    string
+	}{{
+		name:        "not a structured cloudevent",
+		contentType: "application/json",
+		body:        `{"specversion":"1.0"}`,
+		wantHeader:  http.Header{"Content-Type": []string{"application/json"}},
+		wantBody:    `{"specversion":"1.0"}`,
+	}, {
+		name:        "json data",
+		contentType: "application/cloudevents+json; charset=utf-8",
+		body:        `{"specversion":"1.0","type":"dev.knative.example","source":"/example","id":"1234","myext":42,"data":{"a":"b"}}`,
+		wantHeader: http.Header{
+			"Content-Type":   []string{"application/json"},
+			"Ce-Specversion": []string{"1.0"},
+			"Ce-Type":        []string{"dev.knative.example"},
+			"Ce-Source":      []string{"/example"},
+			"Ce-Id":          []string{"1234"},
+			"Ce-Myext":       []string{"42"},
+		},
+		wantBody: `{"a":"b"}`,
+	}, {
+		name:        "text data",
+		contentType: "application/cloudevents+json",
+		body:        `{"specversion":"1.0","datacontenttype":"text/plain","data":"hello"}`,
+		wantHeader: http.Header{
+			"Content-Type":   []string{"text/plain"},
+			"Ce-Specversion": []string{"1.0"},
+		},
+		wantBody: `hello`,
+	}, {
+		name:        "base64 data",
+		contentType: "application/cloudevents+json",
+		body:        `{"specversion":"1.0","datacontenttype":"application/vnd.example+json","data_base64":"eyJhIjoiYiJ9"}`,
+		wantHeader: http.Header{
+			"Content-Type":   []string{"application/vnd.example+json"},
+			"Ce-Specversion": []string{"1.0"},
+		},
+		wantBody: `{"a":"b"}`,
+	}, {
+		name:        "no data",
+		contentType: "application/cloudevents+json",
+		body:        `{"specversion":"1.0"}`,
+		wantHeader: http.Header{
+			"Content-Type":   []string{"application/json"},
+			"Ce-Specversion": []string{"1.0"},
+		},
+		wantBody: ``,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			req.Header.Set("Content-Type", tt.contentType)
+			got, err := toBinaryCloudEvent(req, []byte(tt.body))
+			if err != nil {
+				t.Fatalf("toBinaryCloudEvent() unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tt.wantBody, string(got)); diff != "" {
+				t.Errorf("toBinaryCloudEvent() body (-want, +got) = %s", diff)
+			}
+			if diff := cmp.Diff(tt.wantHeader, req.Header); diff != "" {
+				t.Errorf("toBinaryCloudEvent() header (-want, +got) = %s", diff)
+			}
+		})
+	}
+}
+
+func TestToBinaryCloudEvent_Error(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{{
+		name: "not json",
+		body: `specversion=1.0`,
+		want: "invalid character",
+	}, {
+		name: "missing specversion",
+		body: `{"type":"dev.knative.example"}`,
+		want: "missing specversion",
+	}, {
+		name: "invalid base64 data",
+		body: `{"specversion":"1.0","data_base64":"!!"}`,
+		want: "illegal base64 data",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			req.Header.Set("Content-Type", cloudEventsJSON)
+			_, err := toBinaryCloudEvent(req, []byte(tt.body))
+			if !errors.Is(err, errInvalidCloudEvent) || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("toBinaryCloudEvent() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCloudEventSender(t *testing.T) {
+	s := NewCloudEventSender()
+	unblock := make(chan struct{})
+	var sent int32
+	send := func() {
+		<-unblock
+		atomic.AddInt32(&sent, 1)
+	}
+	// The workers block on their CloudEvents, and the buffer fills up
+	for i := 0; i < cloudEventWorkers+cloudEventBufferSize; i++ {
+		if !s.add(send) {
+			// Workers may not have taken their CloudEvents yet
+			time.Sleep(10 * time.Millisecond)
+			if !s.add(send) {
+				t.Fatalf("add() dropped CloudEvent %d before the buffer was full", i)
+			}
+		}
+	}
+	time.Sleep(10 * time.Millisecond)
+	if s.add(send) {
+		t.Error("add() queued a CloudEvent beyond the buffer")
+	}
+	if err := s.Drain(10 * time.Millisecond); err == nil {
+		t.Error("Drain() expected an error while CloudEvents are being sent")
+	}
+	close(unblock)
+	if err := s.Drain(time.Minute); err != nil {
+		t.Fatalf("Drain() unexpected error: %v", err)
+	}
+	if got := atomic.LoadInt32(&sent); got != cloudEventWorkers+cloudEventBufferSize {
+		t.Errorf("sent %d CloudEvents, want %d", got, cloudEventWorkers+cloudEventBufferSize)
+	}
+	if s.add(send) {
+		t.Error("add() queued a CloudEvent once drained")
+	}
+}
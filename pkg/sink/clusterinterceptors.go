@@ -0,0 +1,97 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sink
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/tektoncd/triggers/pkg/apis/triggers/v1alpha1"
+	"github.com/tektoncd/triggers/pkg/client/informers/externalversions"
+	listers "github.com/tektoncd/triggers/pkg/client/listers/triggers/v1alpha1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// clusterInterceptorSyncTimeout bounds the wait for the ClusterInterceptors
+// informer to sync.
+const clusterInterceptorSyncTimeout = 10 * time.Second
+
+var errClusterInterceptorsNotSynced = errors.New("ClusterInterceptors have not synced, check that the ServiceAccount of the EventListener may list and watch clusterinterceptors")
+
+// LazyClusterInterceptorLister lists ClusterInterceptors from an informer
+// that is only started once a Trigger refers to a ClusterInterceptor, so that
+// EventListeners that do not use them need not be allowed to list and watch
+// them.
+type LazyClusterInterceptorLister struct {
+	ctx         context.Context
+	factory     externalversions.SharedInformerFactory
+	syncTimeout time.Duration
+
+	once     sync.Once
+	informer cache.SharedIndexInformer
+	lister   listers.ClusterInterceptorLister
+}
+
+// NewLazyClusterInterceptorLister returns a LazyClusterInterceptorLister
+// starting its informer from factory, until ctx is done.
+func NewLazyClusterInterceptorLister(ctx context.Context, factory externalversions.SharedInformerFactory) *LazyClusterInterceptorLister {
+	return &LazyClusterInterceptorLister{
+		ctx:         ctx,
+		factory:     factory,
+		syncTimeout: clusterInterceptorSyncTimeout,
+	}
+}
+
+// List lists all the ClusterInterceptors matching selector.
+func (l *LazyClusterInterceptorLister) List(selector labels.Selector) ([]*v1alpha1.ClusterInterceptor, error) {
+	if err := l.sync(); err != nil {
+		return nil, err
+	}
+	return l.lister.List(selector)
+}
+
+// Get returns the ClusterInterceptor named name.
+func (l *LazyClusterInterceptorLister) Get(name string) (*v1alpha1.ClusterInterceptor, error) {
+	if err := l.sync(); err != nil {
+		return nil, err
+	}
+	return l.lister.Get(name)
+}
+
+// sync starts the informer on first use and waits for it to sync. The
+// informer keeps retrying in the background when it fails to sync in time.
+func (l *LazyClusterInterceptorLister) sync() error {
+	l.once.Do(func() {
+		i := l.factory.Triggers().V1alpha1().ClusterInterceptors()
+		l.informer = i.Informer()
+		l.lister = i.Lister()
+		// Only starts the informers that have not been started yet
+		l.factory.Start(l.ctx.Done())
+	})
+	if l.informer.HasSynced() {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(l.ctx, l.syncTimeout)
+	defer cancel()
+	if !cache.WaitForCacheSync(ctx.Done(), l.informer.HasSynced) {
+		return errClusterInterceptorsNotSynced
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sink
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/tektoncd/triggers/pkg/apis/triggers/v1alpha1"
+	faketriggersclient "github.com/tektoncd/triggers/pkg/client/clientset/versioned/fake"
+	"github.com/tektoncd/triggers/pkg/client/informers/externalversions"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ktesting "k8s.io/client-go/testing"
+)
+
+func TestLazyClusterInterceptorLister(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client := faketriggersclient.NewSimpleClientset(&v1alpha1.ClusterInterceptor{
+		ObjectMeta: metav1.ObjectMeta{Name: "scanner"},
+	})
+	factory := externalversions.NewSharedInformerFactory(client, 0)
+	l := NewLazyClusterInterceptorLister(ctx, factory)
+	factory.Start(ctx.Done())
+	if actions := client.Actions(); len(actions) != 0 {
+		t.Errorf("ClusterInterceptors were watched before being used: %v", actions)
+	}
+
+	ci, err := l.Get("scanner")
+	if err != nil {
+		t.Fatalf("Get() unexpected error: %v", err)
+	}
+	if ci.Name != "scanner" {
+		t.Errorf("Get() = %s, want scanner", ci.Name)
+	}
+}
+
+func TestLazyClusterInterceptorLister_Forbidden(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client := faketriggersclient.NewSimpleClientset()
+	client.PrependReactor("list", "clusterinterceptors", func(action ktesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(schema.GroupResource{Group: "triggers.tekton.dev", Resource: "clusterinterceptors"}, "", errors.New("forbidden"))
+	})
+	l := NewLazyClusterInterceptorLister(ctx, externalversions.NewSharedInformerFactory(client, 0))
+	l.syncTimeout = 100 * time.Millisecond
+
+	if _, err := l.Get("scanner"); !errors.Is(err, errClusterInterceptorsNotSynced) {
+		t.Errorf("Get() error = %v, want %v", err, errClusterInterceptorsNotSynced)
+	}
+}
@@ -0,0 +1,280 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	triggersv1 "github.com/tektoncd/triggers/pkg/apis/triggers/v1alpha1"
+	"github.com/tektoncd/triggers/pkg/tracing"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"knative.dev/pkg/kmeta"
+)
+
+const (
+	// defaultDeadLetterMaxEvents is the number of failed events kept for an
+	// EventListener whose DeadLetter does not set MaxEvents.
+	defaultDeadLetterMaxEvents = 100
+	// failedEventDataKey is the key of the Secret data holding a
+	// FailedEvent.
+	failedEventDataKey = "event.json"
+	// FailedEventPruneInterval is how often the failed events beyond the
+	// MaxEvents of the DeadLetter are deleted.
+	FailedEventPruneInterval = time.Minute
+)
+
+// FailedEvent is an event that failed to create the resources of a Trigger,
+// kept so that it can be replayed. It is kept in a Secret since the headers
+// of the event may hold credentials.
+type FailedEvent struct {
+	// Name is the name of the Secret the event is kept in
+	Name string `json:"-"`
+	// EventListener is the name of the EventListener that received the event
+	EventListener string `json:"eventListener"`
+	// Namespace is the namespace of the EventListener
+	Namespace string `json:"namespace"`
+	// EventID is the ID the event was assigned when it was received
+	EventID string `json:"eventID"`
+	// Trigger is the Trigger of the EventListener that failed
+	Trigger triggersv1.EventListenerTrigger `json:"trigger"`
+	// Method, URL, Header and Body describe the request of the event
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   []byte      `json:"body,omitempty"`
+	// Extensions are the extensions added by the interceptors
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
+	// Params are the params resolved for the Trigger
+	Params []triggersv1.Param `json:"params,omitempty"`
+	// Error is the error creating the resources failed with
+	Error string `json:"error"`
+	// FailedAt is when creating the resources failed
+	FailedAt metav1.Time `json:"failedAt"`
+}
+
+// failedEventName returns the name of the Secret keeping the event with
+// eventID that failed for trigger, so that failing again updates it.
+func failedEventName(eventListener, eventID, trigger string) string {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(trigger))
+	return kmeta.ChildName(eventListener, fmt.Sprintf("-failed-%s-%08x", strings.ToLower(eventID), h.Sum32()))
+}
+
+// failedEventSelector selects the Secrets keeping the failed events of the
+// EventListener.
+func failedEventSelector(eventListener string) string {
+	return labels.SelectorFromSet(labels.Set{
+		triggersv1.GroupName + triggersv1.EventListenerLabelKey: eventListener,
+		triggersv1.GroupName + triggersv1.FailedEventLabelKey:   "true",
+	}).String()
+}
+
+// storeFailedEvent keeps fe if the EventListener has a DeadLetter. Errors are
+// only logged since the event has already failed.
+func (r Sink) storeFailedEvent(ctx context.Context, fe FailedEvent, log *zap.SugaredLogger) {
+	el, err := r.EventListenerLister.EventListeners(r.EventListenerNamespace).Get(r.EventListenerName)
+	if err != nil {
+		log.Errorf("Error getting EventListener %s in Namespace %s: %s", r.EventListenerName, r.EventListenerNamespace, err)
+		return
+	}
+	if el.Spec.DeadLetter == nil {
+		return
+	}
+	ctx = tracing.Detach(ctx)
+
+	fe.EventListener = r.EventListenerName
+	fe.Namespace = r.EventListenerNamespace
+	data, err := json.Marshal(fe)
+	if err != nil {
+		log.Errorf("Error marshalling the failed event: %s", err)
+		return
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      failedEventName(r.EventListenerName, fe.EventID, fe.Trigger.Name),
+			Namespace: r.EventListenerNamespace,
+			Labels: map[string]string{
+				triggersv1.GroupName + triggersv1.EventListenerLabelKey: r.EventListenerName,
+				triggersv1.GroupName + triggersv1.TriggerLabelKey:       fe.Trigger.Name,
+				triggersv1.GroupName + triggersv1.EventIDLabelKey:       fe.EventID,
+				triggersv1.GroupName + triggersv1.FailedEventLabelKey:   "true",
+			},
+			// The failed events are deleted along with the EventListener
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: triggersv1.SchemeGroupVersion.String(),
+				Kind:       "EventListener",
+				Name:       el.Name,
+				UID:        el.UID,
+			}},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{failedEventDataKey: data},
+	}
+	secrets := r.KubeClientSet.CoreV1().Secrets(r.EventListenerNamespace)
+	if _, err := secrets.Create(ctx, secret, metav1.CreateOptions{}); kerrors.IsAlreadyExists(err) {
+		// The event failed again after being replayed
+		existing, err := secrets.Get(ctx, secret.Name, metav1.GetOptions{})
+		if err == nil {
+			existing.Data = secret.Data
+			_, err = secrets.Update(ctx, existing, metav1.UpdateOptions{})
+		}
+		if err != nil {
+			log.Errorf("Error updating failed event %s: %s", secret.Name, err)
+			return
+		}
+	} else if err != nil {
+		log.Errorf("Error storing failed event %s: %s", secret.Name, err)
+		return
+	}
+	log.Infof("Stored failed event %s", secret.Name)
+}
+
+// PruneFailedEvents deletes the oldest failed events of the EventListener
+// beyond the MaxEvents of its DeadLetter every interval, until ctx is done.
+// Pruning is kept apart from storing failed events so that it does not hold
+// up processing events.
+func (r Sink) PruneFailedEvents(ctx context.Context, interval time.Duration) {
+	wait.UntilWithContext(ctx, r.pruneFailedEvents, interval)
+}
+
+func (r Sink) pruneFailedEvents(ctx context.Context) {
+	el, err := r.EventListenerLister.EventListeners(r.EventListenerNamespace).Get(r.EventListenerName)
+	if err != nil {
+		r.Logger.Errorf("Error getting EventListener %s in Namespace %s: %s", r.EventListenerName, r.EventListenerNamespace, err)
+		return
+	}
+	if el.Spec.DeadLetter == nil {
+		return
+	}
+	maxEvents := int(el.Spec.DeadLetter.MaxEvents)
+	if maxEvents == 0 {
+		maxEvents = defaultDeadLetterMaxEvents
+	}
+	secrets := r.KubeClientSet.CoreV1().Secrets(r.EventListenerNamespace)
+	list, err := secrets.List(ctx, metav1.ListOptions{LabelSelector: failedEventSelector(r.EventListenerName)})
+	if err != nil {
+		r.Logger.Errorf("Error listing failed events: %s", err)
+		return
+	}
+	items := list.Items
+	sortFailedEvents(items)
+	for i := 0; i < len(items)-maxEvents; i++ {
+		if err := secrets.Delete(ctx, items[i].Name, metav1.DeleteOptions{}); err != nil && !kerrors.IsNotFound(err) {
+			r.Logger.Errorf("Error deleting failed event %s: %s", items[i].Name, err)
+		}
+	}
+}
+
+// sortFailedEvents sorts the Secrets of failed events from the oldest to the
+// newest.
+func sortFailedEvents(items []corev1.Secret) {
+	sort.SliceStable(items, func(i, j int) bool {
+		ti, tj := items[i].CreationTimestamp, items[j].CreationTimestamp
+		if !ti.Equal(&tj) {
+			return ti.Before(&tj)
+		}
+		return items[i].Name < items[j].Name
+	})
+}
+
+// decodeFailedEvent returns the FailedEvent kept in secret.
+func decodeFailedEvent(secret *corev1.Secret) (FailedEvent, error) {
+	var fe FailedEvent
+	data, ok := secret.Data[failedEventDataKey]
+	if !ok {
+		return fe, fmt.Errorf("Secret %s does not hold a failed event", secret.Name)
+	}
+	if err := json.Unmarshal(data, &fe); err != nil {
+		return fe, fmt.Errorf("failed to decode the failed event in Secret %s: %w", secret.Name, err)
+	}
+	fe.Name = secret.Name
+	return fe, nil
+}
+
+// ListFailedEvents returns the failed events kept for the EventListener, from
+// the oldest to the newest.
+func ListFailedEvents(ctx context.Context, kubeClient kubernetes.Interface, namespace, eventListener string) ([]FailedEvent, error) {
+	list, err := kubeClient.CoreV1().Secrets(namespace).List(ctx, metav1.ListOptions{LabelSelector: failedEventSelector(eventListener)})
+	if err != nil {
+		return nil, err
+	}
+	items := list.Items
+	sortFailedEvents(items)
+	events := make([]FailedEvent, 0, len(items))
+	for i := range items {
+		fe, err := decodeFailedEvent(&items[i])
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, fe)
+	}
+	return events, nil
+}
+
+// GetFailedEvent returns the failed event kept in the Secret name.
+func GetFailedEvent(ctx context.Context, kubeClient kubernetes.Interface, namespace, name string) (FailedEvent, error) {
+	secret, err := kubeClient.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return FailedEvent{}, err
+	}
+	return decodeFailedEvent(secret)
+}
+
+// ReplayFailedEvent processes the failed event again for its Trigger, as if
+// the EventListener had just received it. The event is no longer kept once
+// its resources are created; if it fails again, it is kept with the new
+// error.
+func (r Sink) ReplayFailedEvent(ctx context.Context, fe FailedEvent) (TriggerResult, error) {
+	request, err := http.NewRequestWithContext(ctx, fe.Method, fe.URL, bytes.NewReader(fe.Body))
+	if err != nil {
+		return TriggerResult{}, fmt.Errorf("failed to rebuild the request of the failed event: %w", err)
+	}
+	request.Header = fe.Header.Clone()
+	if request.Header == nil {
+		request.Header = http.Header{}
+	}
+	request.Body = ioutil.NopCloser(bytes.NewReader(fe.Body))
+
+	eventLog := r.Logger.With(zap.String(triggersv1.EventIDLabelKey, fe.EventID))
+	eventLog.Infof("Replaying failed event %s", fe.Name)
+	trigger := fe.Trigger
+	result, err := r.processTrigger(&trigger, request, fe.Body, fe.EventID, eventLog)
+	if err != nil {
+		return result, err
+	}
+	if fe.Name != "" {
+		if err := r.KubeClientSet.CoreV1().Secrets(r.EventListenerNamespace).Delete(ctx, fe.Name, metav1.DeleteOptions{}); err != nil && !kerrors.IsNotFound(err) {
+			return result, fmt.Errorf("failed to delete the replayed event %s: %w", fe.Name, err)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,264 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	triggersv1 "github.com/tektoncd/triggers/pkg/apis/triggers/v1alpha1"
+	"github.com/tektoncd/triggers/test"
+	bldr "github.com/tektoncd/triggers/test/builder"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakekubeclient "k8s.io/client-go/kubernetes/fake"
+	ktesting "k8s.io/client-go/testing"
+)
+
+func failedEventSecret(t *testing.T, name string, created time.Time, fe FailedEvent) *corev1.Secret {
+	t.Helper()
+	data, err := json.Marshal(fe)
+	if err != nil {
+		t.Fatalf("Error marshalling the failed event: %s", err)
+	}
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         namespace,
+			CreationTimestamp: metav1.NewTime(created),
+			Labels: map[string]string{
+				"triggers.tekton.dev/eventlistener": "el",
+				"triggers.tekton.dev/failed-event":  "true",
+			},
+		},
+		Data: map[string][]byte{failedEventDataKey: data},
+	}
+}
+
+func TestFailedEventName(t *testing.T) {
+	name := failedEventName("el", "abcde", "my-trigger")
+	if !strings.HasPrefix(name, "el-failed-abcde-") {
+		t.Errorf("failedEventName() = %q, want the EventListener and event ID as prefix", name)
+	}
+	if got := failedEventName("el", "abcde", "my-trigger"); got != name {
+		t.Errorf("failedEventName() is not stable: %q != %q", got, name)
+	}
+	if got := failedEventName("el", "abcde", "other-trigger"); got == name {
+		t.Errorf("failedEventName() = %q for two Triggers", got)
+	}
+	if got := failedEventName(strings.Repeat("a", 300), "abcde", "my-trigger"); len(got) > 63 {
+		t.Errorf("failedEventName() = %q is longer than 63 characters", got)
+	}
+}
+
+func TestStoreFailedEvent(t *testing.T) {
+	el := bldr.EventListener("el", namespace, bldr.EventListenerSpec(bldr.EventListenerDeadLetter(2)))
+	now := time.Now()
+	resources := test.Resources{
+		EventListeners: []*triggersv1.EventListener{el},
+		Secrets: []*corev1.Secret{
+			failedEventSecret(t, "el-failed-old", now.Add(-2*time.Hour), FailedEvent{EventID: "old"}),
+			failedEventSecret(t, "el-failed-older", now.Add(-3*time.Hour), FailedEvent{EventID: "older"}),
+		},
+	}
+	sink, _ := getSinkAssets(t, resources, el.Name, DefaultAuthOverride{})
+	// The fake clients do not set the creation timestamp the failed events
+	// are ordered by
+	sink.KubeClientSet.(*fakekubeclient.Clientset).PrependReactor("create", "secrets", func(action ktesting.Action) (bool, runtime.Object, error) {
+		action.(ktesting.CreateAction).GetObject().(*corev1.Secret).CreationTimestamp = metav1.NewTime(now)
+		return false, nil, nil
+	})
+
+	fe := FailedEvent{
+		EventID: "abcde",
+		Trigger: triggersv1.EventListenerTrigger{Name: "my-trigger"},
+		Method:  http.MethodPost,
+		URL:     "/",
+		Header:  http.Header{"Content-Type": {"application/json"}},
+		Body:    []byte(`{"foo": "bar"}`),
+		Params:  []triggersv1.Param{{Name: "foo", Value: "bar"}},
+		Error:   "failed",
+	}
+	sink.storeFailedEvent(context.Background(), fe, sink.Logger)
+	// Failing again updates the event
+	fe.Error = "failed again"
+	sink.storeFailedEvent(context.Background(), fe, sink.Logger)
+	// Only the newest events are kept once pruned
+	sink.pruneFailedEvents(context.Background())
+
+	events, err := ListFailedEvents(context.Background(), sink.KubeClientSet, namespace, el.Name)
+	if err != nil {
+		t.Fatalf("ListFailedEvents() unexpected error: %v", err)
+	}
+	var ids []string
+	for _, e := range events {
+		ids = append(ids, e.EventID)
+	}
+	if diff := cmp.Diff([]string{"old", "abcde"}, ids); diff != "" {
+		t.Errorf("ListFailedEvents() event IDs (-want +got): %s", diff)
+	}
+
+	got, err := GetFailedEvent(context.Background(), sink.KubeClientSet, namespace, failedEventName(el.Name, "abcde", "my-trigger"))
+	if err != nil {
+		t.Fatalf("GetFailedEvent() unexpected error: %v", err)
+	}
+	want := fe
+	want.Name = failedEventName(el.Name, "abcde", "my-trigger")
+	want.EventListener = el.Name
+	want.Namespace = namespace
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("GetFailedEvent() (-want +got): %s", diff)
+	}
+
+	secret, err := sink.KubeClientSet.CoreV1().Secrets(namespace).Get(context.Background(), want.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Error getting the failed event Secret: %s", err)
+	}
+	wantLabels := map[string]string{
+		"triggers.tekton.dev/eventlistener":    el.Name,
+		"triggers.tekton.dev/trigger":          "my-trigger",
+		"triggers.tekton.dev/triggers-eventid": "abcde",
+		"triggers.tekton.dev/failed-event":     "true",
+	}
+	if diff := cmp.Diff(wantLabels, secret.Labels); diff != "" {
+		t.Errorf("failed event labels (-want +got): %s", diff)
+	}
+}
+
+func TestStoreFailedEvent_NoDeadLetter(t *testing.T) {
+	el := bldr.EventListener("el", namespace)
+	sink, _ := getSinkAssets(t, test.Resources{EventListeners: []*triggersv1.EventListener{el}}, el.Name, DefaultAuthOverride{})
+
+	sink.storeFailedEvent(context.Background(), FailedEvent{EventID: "abcde"}, sink.Logger)
+
+	events, err := ListFailedEvents(context.Background(), sink.KubeClientSet, namespace, el.Name)
+	if err != nil {
+		t.Fatalf("ListFailedEvents() unexpected error: %v", err)
+	}
+	if len(events) != 0 {
+		t.Errorf("ListFailedEvents() = %v, want no events without a dead letter", events)
+	}
+}
+
+func TestGetFailedEvent_NotAFailedEvent(t *testing.T) {
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: namespace}}
+	sink, _ := getSinkAssets(t, test.Resources{Secrets: []*corev1.Secret{secret}}, "el", DefaultAuthOverride{})
+
+	if _, err := GetFailedEvent(context.Background(), sink.KubeClientSet, namespace, "other"); err == nil {
+		t.Error("GetFailedEvent() expected an error for a Secret without a failed event")
+	}
+}
+
+func TestHandleEvent_DeadLetter(t *testing.T) {
+	eventBody := `{"head_commit": {"id": "testrevision"}, "repository": {"url": "testurl"}}`
+	tb, tt := getResources(t, "$(body.repository.url)")
+	el := bldr.EventListener("el", namespace, bldr.EventListenerSpec(
+		bldr.EventListenerTrigger("tt", "v1alpha1",
+			bldr.EventListenerTriggerName("my-trigger"),
+			bldr.EventListenerTriggerBinding("tb", "TriggerBinding", "v1alpha1"),
+		),
+		bldr.EventListenerDeadLetter(0)))
+	resources := test.Resources{
+		TriggerBindings:  []*triggersv1.TriggerBinding{tb},
+		TriggerTemplates: []*triggersv1.TriggerTemplate{tt},
+		EventListeners:   []*triggersv1.EventListener{el},
+	}
+	sink, dynamicClient := getSinkAssets(t, resources, el.Name, DefaultAuthOverride{})
+	var failing int32 = 1
+	dynamicClient.PrependReactor("create", "*", func(action ktesting.Action) (bool, runtime.Object, error) {
+		if atomic.LoadInt32(&failing) == 1 {
+			return true, nil, errors.New("create failed")
+		}
+		return false, nil, nil
+	})
+
+	ts := httptest.NewServer(http.HandlerFunc(sink.HandleEvent))
+	defer ts.Close()
+	req, err := http.NewRequest(http.MethodPost, ts.URL+"/?foo=bar", bytes.NewReader([]byte(eventBody)))
+	if err != nil {
+		t.Fatalf("Error creating request: %s", err)
+	}
+	req.Header.Set("X-Test", "value")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Error sending the event: %s", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		t.Errorf("HandleEvent() status = %d, want %d", resp.StatusCode, http.StatusAccepted)
+	}
+
+	events, err := ListFailedEvents(context.Background(), sink.KubeClientSet, namespace, el.Name)
+	if err != nil {
+		t.Fatalf("ListFailedEvents() unexpected error: %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("ListFailedEvents() = %d events, want 1", len(events))
+	}
+	fe := events[0]
+	if fe.Trigger.Name != "my-trigger" || fe.Method != http.MethodPost || fe.URL != "/?foo=bar" ||
+		string(fe.Body) != eventBody || fe.Header.Get("X-Test") != "value" || !strings.Contains(fe.Error, "create failed") {
+		t.Errorf("failed event does not describe the event: %+v", fe)
+	}
+	if diff := cmp.Diff([]triggersv1.Param{{Name: "url", Value: "testurl"}}, fe.Params); diff != "" {
+		t.Errorf("failed event params (-want +got): %s", diff)
+	}
+
+	atomic.StoreInt32(&failing, 0)
+	result, err := sink.ReplayFailedEvent(context.Background(), fe)
+	if err != nil {
+		t.Fatalf("ReplayFailedEvent() unexpected error: %v", err)
+	}
+	want := []CreatedResource{{
+		APIVersion: "tekton.dev/v1alpha1",
+		Kind:       "PipelineResource",
+		Namespace:  namespace,
+		Name:       "my-pipelineresource",
+	}}
+	if diff := cmp.Diff(want, result.Resources); diff != "" {
+		t.Errorf("ReplayFailedEvent() resources (-want +got): %s", diff)
+	}
+
+	var created []ktesting.Action
+	for _, a := range dynamicClient.Actions() {
+		if a.GetVerb() == "create" {
+			created = append(created, a)
+		}
+	}
+	prs := getCreatedPipelineResources(t, created[len(created)-1:])
+	if got := prs[0].Labels["triggers.tekton.dev/triggers-eventid"]; got != fe.EventID {
+		t.Errorf("replayed resource event ID = %q, want the original %q", got, fe.EventID)
+	}
+
+	events, err = ListFailedEvents(context.Background(), sink.KubeClientSet, namespace, el.Name)
+	if err != nil {
+		t.Fatalf("ListFailedEvents() unexpected error: %v", err)
+	}
+	if len(events) != 0 {
+		t.Errorf("ListFailedEvents() = %v, want the replayed event deleted", events)
+	}
+}
@@ -0,0 +1,207 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sink
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	triggersv1 "github.com/tektoncd/triggers/pkg/apis/triggers/v1alpha1"
+	"github.com/tektoncd/triggers/pkg/interceptors/cel"
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultDeduplicationTTL is how long delivery keys are remembered when
+	// the EventListener does not set a TTL.
+	defaultDeduplicationTTL = time.Hour
+	// memoryStorePruneInterval is how often expired delivery keys are removed
+	// from the in-memory DeliveryStore.
+	memoryStorePruneInterval = time.Minute
+)
+
+// DeliveryStore remembers the keys of the events delivered to the
+// EventListener so that redeliveries are only processed once. Implementations
+// backed by a shared resource allow the replicas of an EventListener to
+// deduplicate events together.
+type DeliveryStore interface {
+	// Reserve records eventID as the delivery for key for the duration of ttl.
+	// If key is already reserved, it returns the eventID of the original
+	// delivery and false.
+	Reserve(key, eventID string, ttl time.Duration) (string, bool, error)
+	// Release forgets key so that its next delivery is processed.
+	Release(key string) error
+}
+
+type delivery struct {
+	eventID string
+	expires time.Time
+}
+
+// memoryDeliveryStore is a DeliveryStore local to a single EventListener
+// replica.
+type memoryDeliveryStore struct {
+	mu         sync.Mutex
+	deliveries map[string]delivery
+	lastPrune  time.Time
+	now        func() time.Time
+}
+
+// NewMemoryDeliveryStore returns a DeliveryStore that keeps the delivery keys
+// in memory.
+func NewMemoryDeliveryStore() DeliveryStore {
+	return &memoryDeliveryStore{
+		deliveries: map[string]delivery{},
+		now:        time.Now,
+	}
+}
+
+func (s *memoryDeliveryStore) Reserve(key, eventID string, ttl time.Duration) (string, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	if now.Sub(s.lastPrune) >= memoryStorePruneInterval {
+		for k, d := range s.deliveries {
+			if !now.Before(d.expires) {
+				delete(s.deliveries, k)
+			}
+		}
+		s.lastPrune = now
+	}
+	if d, ok := s.deliveries[key]; ok && now.Before(d.expires) {
+		return d.eventID, false, nil
+	}
+	s.deliveries[key] = delivery{eventID: eventID, expires: now.Add(ttl)}
+	return eventID, true, nil
+}
+
+func (s *memoryDeliveryStore) Release(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.deliveries, key)
+	return nil
+}
+
+// batchIndexKey is the context key of the index of an event in its batch.
+type batchIndexKey struct{}
+
+// withBatchIndex returns a copy of ctx carrying the index of the event in the
+// batch it was sent in.
+func withBatchIndex(ctx context.Context, i int) context.Context {
+	return context.WithValue(ctx, batchIndexKey{}, i)
+}
+
+// batchIndex returns the index of the event in the batch it was sent in, and
+// false if ctx does not carry it.
+func batchIndex(ctx context.Context) (int, bool) {
+	i, ok := ctx.Value(batchIndexKey{}).(int)
+	return i, ok
+}
+
+// reserveDelivery reserves the delivery key of the event when the
+// EventListener deduplicates events. It returns the reserved key, which is
+// empty if there is none, and the eventID of the original delivery if the
+// event is a duplicate. The events of a batch share the header of the batch,
+// so a key taken from the header is suffixed with the index of the event in
+// the batch.
+func (r Sink) reserveDelivery(el *triggersv1.EventListener, request *http.Request, event []byte, eventID string, log *zap.SugaredLogger) (string, string) {
+	d := el.Spec.Deduplication
+	if d == nil || r.DeliveryStore == nil {
+		return "", ""
+	}
+
+	key := request.Header.Get(d.Header)
+	if i, ok := batchIndex(request.Context()); ok && key != "" {
+		key = fmt.Sprintf("%s/%d", key, i)
+	}
+	if d.Expression != "" {
+		var err error
+		key, err = cel.EvaluateString(d.Expression, event, request.Header, request.URL.String(), sourceIP(request.Context()), r.EventListenerNamespace, r.KubeClientSet)
+		if err != nil {
+			log.Errorf("Error evaluating the delivery key of the event: %s", err)
+			return "", ""
+		}
+	}
+	if key == "" {
+		return "", ""
+	}
+
+	ttl := defaultDeduplicationTTL
+	if d.TTL != nil {
+		ttl = d.TTL.Duration
+	}
+	originalID, reserved, err := r.DeliveryStore.Reserve(key, eventID, ttl)
+	if err != nil {
+		// Process the event rather than dropping it when the store is unavailable.
+		log.Errorf("Error reserving delivery %s: %s", key, err)
+		return "", ""
+	}
+	if !reserved {
+		log.Infof("Skipping duplicate delivery %s of event %s", key, originalID)
+		return "", originalID
+	}
+	return key, ""
+}
+
+// releaseDelivery releases the delivery key of an event that could not be
+// processed so that a redelivery is processed again.
+func (r Sink) releaseDelivery(key string, log *zap.SugaredLogger) {
+	if key == "" {
+		return
+	}
+	if err := r.DeliveryStore.Release(key); err != nil {
+		log.Errorf("Error releasing delivery %s: %s", key, err)
+	}
+}
+
+// queuedDelivery is the delivery key of an event whose Triggers are processed
+// by the EventQueue. As for the events processed synchronously, the key is
+// released once all of the Triggers are processed if none of them created its
+// resources, so that a redelivery of the event is processed.
+type queuedDelivery struct {
+	key string
+
+	mu        sync.Mutex
+	remaining int
+	created   bool
+}
+
+// newQueuedDelivery returns the queuedDelivery of the key for an event whose
+// Triggers are queued as n items. It returns nil when there is no key.
+func newQueuedDelivery(key string, n int) *queuedDelivery {
+	if key == "" {
+		return nil
+	}
+	return &queuedDelivery{key: key, remaining: n}
+}
+
+// processed records that one of the queued items of the event is processed,
+// and whether it created its resources. It returns true when the key is to
+// be released.
+func (d *queuedDelivery) processed(created bool) bool {
+	if d == nil {
+		return false
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.remaining--
+	d.created = d.created || created
+	return d.remaining == 0 && !d.created
+}
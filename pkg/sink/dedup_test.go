@@ -0,0 +1,54 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sink
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMemoryDeliveryStore(t *testing.T) {
+	now := time.Date(2020, 10, 1, 0, 0, 0, 0, time.UTC)
+	store := NewMemoryDeliveryStore().(*memoryDeliveryStore)
+	store.now = func() time.Time { return now }
+
+	reserve := func(key, eventID, wantID string, wantReserved bool) {
+		t.Helper()
+		gotID, reserved, err := store.Reserve(key, eventID, time.Hour)
+		if err != nil {
+			t.Fatalf("Reserve() unexpected error: %v", err)
+		}
+		if gotID != wantID || reserved != wantReserved {
+			t.Errorf("Reserve(%q, %q) = (%q, %t), want (%q, %t)", key, eventID, gotID, reserved, wantID, wantReserved)
+		}
+	}
+
+	reserve("delivery-1", "event-1", "event-1", true)
+	reserve("delivery-1", "event-2", "event-1", false)
+	reserve("delivery-2", "event-3", "event-3", true)
+
+	if err := store.Release("delivery-2"); err != nil {
+		t.Fatalf("Release() unexpected error: %v", err)
+	}
+	reserve("delivery-2", "event-4", "event-4", true)
+
+	now = now.Add(time.Hour)
+	reserve("delivery-1", "event-5", "event-5", true)
+	if _, ok := store.deliveries["delivery-2"]; ok {
+		t.Error("expected the expired delivery-2 to be pruned")
+	}
+}
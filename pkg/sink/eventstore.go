@@ -0,0 +1,125 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sink
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// StoredEvent is an event received by the sink, as seen by the interceptors
+// of its Triggers.
+type StoredEvent struct {
+	EventID    string      `json:"eventID"`
+	Method     string      `json:"method"`
+	URL        string      `json:"url"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       []byte      `json:"body,omitempty"`
+	ReceivedAt time.Time   `json:"receivedAt"`
+}
+
+// credentialHeaders are the headers carrying the credentials of events. They
+// are kept so that events can be replayed, but not listed.
+var credentialHeaders = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"X-Hub-Signature",
+	"X-Hub-Signature-256",
+	"X-Gitlab-Token",
+}
+
+// redactedValue replaces the values of the credential headers of listed
+// events.
+const redactedValue = "REDACTED"
+
+// redacted returns a copy of the event whose credential headers, and the
+// headers named, have their values replaced.
+func (e StoredEvent) redacted(headers ...string) StoredEvent {
+	e.Header = e.Header.Clone()
+	for _, names := range [][]string{credentialHeaders, headers} {
+		for _, h := range names {
+			if _, ok := e.Header[http.CanonicalHeaderKey(h)]; ok {
+				e.Header.Set(h, redactedValue)
+			}
+		}
+	}
+	return e
+}
+
+// EventStore keeps the most recent events received by the sink so that they
+// can be replayed through the admin endpoint.
+type EventStore struct {
+	mu     sync.Mutex
+	events []StoredEvent
+	// next is the index of the slot the next event is stored in
+	next int
+	full bool
+}
+
+// NewEventStore returns an EventStore keeping the last size events.
+func NewEventStore(size int) *EventStore {
+	return &EventStore{events: make([]StoredEvent, size)}
+}
+
+// Add stores the event, evicting the oldest event when the store is full.
+func (s *EventStore) Add(e StoredEvent) {
+	if s == nil || len(s.events) == 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events[s.next] = e
+	s.next = (s.next + 1) % len(s.events)
+	if s.next == 0 {
+		s.full = true
+	}
+}
+
+// Get returns the stored event with the EventID, and whether it was found.
+func (s *EventStore) Get(eventID string) (StoredEvent, bool) {
+	if s == nil {
+		return StoredEvent{}, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, e := range s.events {
+		if e.EventID == eventID && eventID != "" {
+			return e, true
+		}
+	}
+	return StoredEvent{}, false
+}
+
+// List returns the stored events, most recent first.
+func (s *EventStore) List() []StoredEvent {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := s.next
+	if s.full {
+		n = len(s.events)
+	}
+	events := make([]StoredEvent, 0, n)
+	for i := 1; i <= n; i++ {
+		events = append(events, s.events[(s.next-i+len(s.events))%len(s.events)])
+	}
+	return events
+}
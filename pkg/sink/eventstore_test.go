@@ -0,0 +1,91 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sink
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestEventStore(t *testing.T) {
+	s := NewEventStore(2)
+	ids := func() []string {
+		var ids []string
+		for _, e := range s.List() {
+			ids = append(ids, e.EventID)
+		}
+		return ids
+	}
+	if got := ids(); len(got) != 0 {
+		t.Errorf("List() of an empty store = %v", got)
+	}
+
+	s.Add(StoredEvent{EventID: "a"})
+	if diff := cmp.Diff([]string{"a"}, ids()); diff != "" {
+		t.Errorf("List() (-want +got): %s", diff)
+	}
+	s.Add(StoredEvent{EventID: "b"})
+	s.Add(StoredEvent{EventID: "c"})
+	if diff := cmp.Diff([]string{"c", "b"}, ids()); diff != "" {
+		t.Errorf("List() once full (-want +got): %s", diff)
+	}
+	if _, ok := s.Get("a"); ok {
+		t.Error("Get() found the evicted event")
+	}
+	if e, ok := s.Get("b"); !ok || e.EventID != "b" {
+		t.Errorf("Get() = %v, %t, want the stored event", e, ok)
+	}
+}
+
+func TestEventStore_Disabled(t *testing.T) {
+	for _, s := range []*EventStore{nil, NewEventStore(0)} {
+		s.Add(StoredEvent{EventID: "a"})
+		if _, ok := s.Get("a"); ok {
+			t.Errorf("Get() found an event in a disabled store %v", s)
+		}
+		if got := s.List(); len(got) != 0 {
+			t.Errorf("List() = %v for a disabled store", got)
+		}
+	}
+}
+
+func TestStoredEvent_Redacted(t *testing.T) {
+	e := StoredEvent{
+		EventID: "a",
+		Header: http.Header{
+			"Authorization":       {"Bearer secret"},
+			"X-Hub-Signature-256": {"sha256=abc"},
+			"X-Signature":         {"def"},
+			"Content-Type":        {"application/json"},
+		},
+	}
+	want := http.Header{
+		"Authorization":       {redactedValue},
+		"X-Hub-Signature-256": {redactedValue},
+		"X-Signature":         {redactedValue},
+		"Content-Type":        {"application/json"},
+	}
+	if diff := cmp.Diff(want, e.redacted("x-signature").Header); diff != "" {
+		t.Errorf("redacted() header (-want +got): %s", diff)
+	}
+	// The stored event keeps its credentials to be replayed
+	if got := e.Header.Get("Authorization"); got != "Bearer secret" {
+		t.Errorf("redacted() changed the header of the stored event to %q", got)
+	}
+}
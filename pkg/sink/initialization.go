@@ -50,6 +50,44 @@ var (
 		"The idle timeout for EventListener Server.")
 	elTimeOutHandler = flag.Int64("timeouthandler", 5,
 		"The timeout for Timeout Handler of EventListener Server.")
+	elQueueSize = flag.Int("queuesize", 1000,
+		"The maximum number of triggers waiting to be processed for asynchronously handled events.")
+	elQueueWorkers = flag.Int("queueworkers", 4,
+		"The number of workers processing triggers for asynchronously handled events.")
+	elQueueRetries = flag.Int("queueretries", 3,
+		"The number of times a trigger for an asynchronously handled event is retried after a failure.")
+	elDrainTimeOut = flag.Int64("draintimeout", 20,
+		"The time allowed for processing queued events when the EventListener shuts down.")
+	elShutdownTimeOut = flag.Int64("shutdowntimeout", 10,
+		"The time allowed for handling the events being received when the EventListener shuts down.")
+	elMaxBodySize = flag.Int64("maxbodysize", 25*1024*1024,
+		"The maximum size in bytes of the body of an event.")
+	elMaxBatchSize = flag.Int("maxbatchsize", 100,
+		"The maximum number of events in a batch. There is no limit if zero.")
+	elMetricsPort = flag.String("metricsport", "9000",
+		"The port for the EventListener sink to serve metrics on.")
+	elTracingEndpoint = flag.String("tracingendpoint", "",
+		"The address of the OTLP receiver to export traces to. Tracing is disabled if empty.")
+	elTracingSampleRate = flag.Float64("tracingsamplerate", 1,
+		"The probability of sampling the traces of events without a sampled parent.")
+	elTLSCertFile = flag.String("tlscertfile", "",
+		"The file holding the certificate served over HTTPS. The sink serves HTTP if empty.")
+	elTLSKeyFile = flag.String("tlskeyfile", "",
+		"The file holding the private key of the certificate served over HTTPS.")
+	elTLSClientCAFile = flag.String("tlsclientcafile", "",
+		"The file holding the CA certificates that the certificates of clients are verified against.")
+	elAdminPort = flag.String("adminport", "",
+		"The port for the EventListener sink to serve the admin endpoint on. The admin endpoint is disabled if empty.")
+	elAdminTokenFile = flag.String("admintokenfile", "",
+		"The file holding the bearer token that requests to the admin endpoint must send.")
+	elRecentEvents = flag.Int("recentevents", 100,
+		"The number of recent events kept for the admin endpoint to replay.")
+	elInterceptorTokenFile = flag.String("interceptortokenfile", "",
+		"The file holding the ServiceAccount token sent to ClusterInterceptors. No token is sent if empty.")
+	elSecretTTL = flag.Duration("secretttl", time.Minute,
+		"The time for which the secrets that authenticate events are cached.")
+	elTokenReviewTTL = flag.Duration("tokenreviewttl", time.Minute,
+		"The time for which the outcome of reviewing the token of an event is cached.")
 )
 
 // Args define the arguments for Sink.
@@ -68,6 +106,51 @@ type Args struct {
 	ELIdleTimeOut time.Duration
 	// ELTimeOutHandler defines the timeout for Timeout Handler of EventListener Server
 	ELTimeOutHandler time.Duration
+	// QueueSize is the maximum number of triggers held by the EventQueue
+	QueueSize int
+	// QueueWorkers is the number of workers processing the EventQueue
+	QueueWorkers int
+	// QueueRetries is the number of retries for a failed trigger in the EventQueue
+	QueueRetries int
+	// ELDrainTimeOut defines the time allowed for draining the EventQueue on shutdown
+	ELDrainTimeOut time.Duration
+	// ELShutdownTimeOut defines the time allowed for handling the events
+	// being received on shutdown
+	ELShutdownTimeOut time.Duration
+	// MaxBodySize is the maximum size in bytes of the body of an event
+	MaxBodySize int64
+	// MaxBatchSize is the maximum number of events in a batch
+	MaxBatchSize int
+	// MetricsPort is the port the Sink should serve metrics on
+	MetricsPort string
+	// TracingEndpoint is the address of the OTLP receiver to export traces to
+	TracingEndpoint string
+	// TracingSampleRate is the probability of sampling new traces
+	TracingSampleRate float64
+	// TLSCertFile is the file holding the certificate served over HTTPS
+	TLSCertFile string
+	// TLSKeyFile is the file holding the private key of the certificate
+	TLSKeyFile string
+	// TLSClientCAFile is the file holding the CA certificates that client
+	// certificates are verified against
+	TLSClientCAFile string
+	// AdminPort is the port the Sink should serve the admin endpoint on
+	AdminPort string
+	// AdminTokenFile is the file holding the bearer token of the admin
+	// endpoint
+	AdminTokenFile string
+	// RecentEvents is the number of recent events kept for the admin
+	// endpoint to replay
+	RecentEvents int
+	// InterceptorTokenFile is the file holding the ServiceAccount token sent
+	// to ClusterInterceptors
+	InterceptorTokenFile string
+	// SecretTTL is the time for which the secrets that authenticate events
+	// are cached
+	SecretTTL time.Duration
+	// TokenReviewTTL is the time for which the outcome of reviewing a token
+	// is cached
+	TokenReviewTTL time.Duration
 }
 
 // Clients define the set of client dependencies Sink requires.
@@ -89,14 +172,42 @@ func GetArgs() (Args, error) {
 	if *portFlag == "" {
 		return Args{}, xerrors.Errorf("-%s arg not found", port)
 	}
+	if (*elTLSCertFile == "") != (*elTLSKeyFile == "") {
+		return Args{}, xerrors.New("-tlscertfile and -tlskeyfile must be set together")
+	}
+	if *elTLSClientCAFile != "" && *elTLSCertFile == "" {
+		return Args{}, xerrors.New("-tlsclientcafile requires -tlscertfile")
+	}
+	if *elAdminPort != "" && *elAdminTokenFile == "" {
+		return Args{}, xerrors.New("-adminport requires -admintokenfile")
+	}
 	return Args{
-		ElName:           *nameFlag,
-		ElNamespace:      *namespaceFlag,
-		Port:             *portFlag,
-		ELReadTimeOut:    time.Duration(*elReadTimeOut),
-		ELWriteTimeOut:   time.Duration(*elWriteTimeOut),
-		ELIdleTimeOut:    time.Duration(*elIdleTimeOut),
-		ELTimeOutHandler: time.Duration(*elTimeOutHandler),
+		ElName:               *nameFlag,
+		ElNamespace:          *namespaceFlag,
+		Port:                 *portFlag,
+		ELReadTimeOut:        time.Duration(*elReadTimeOut),
+		ELWriteTimeOut:       time.Duration(*elWriteTimeOut),
+		ELIdleTimeOut:        time.Duration(*elIdleTimeOut),
+		ELTimeOutHandler:     time.Duration(*elTimeOutHandler),
+		QueueSize:            *elQueueSize,
+		QueueWorkers:         *elQueueWorkers,
+		QueueRetries:         *elQueueRetries,
+		ELDrainTimeOut:       time.Duration(*elDrainTimeOut),
+		ELShutdownTimeOut:    time.Duration(*elShutdownTimeOut),
+		MaxBodySize:          *elMaxBodySize,
+		MaxBatchSize:         *elMaxBatchSize,
+		MetricsPort:          *elMetricsPort,
+		TracingEndpoint:      *elTracingEndpoint,
+		TracingSampleRate:    *elTracingSampleRate,
+		TLSCertFile:          *elTLSCertFile,
+		TLSKeyFile:           *elTLSKeyFile,
+		TLSClientCAFile:      *elTLSClientCAFile,
+		AdminPort:            *elAdminPort,
+		AdminTokenFile:       *elAdminTokenFile,
+		RecentEvents:         *elRecentEvents,
+		InterceptorTokenFile: *elInterceptorTokenFile,
+		SecretTTL:            *elSecretTTL,
+		TokenReviewTTL:       *elTokenReviewTTL,
 	}, nil
 }
 
@@ -44,6 +44,12 @@ func Test_GetArgs(t *testing.T) {
 	if sinkArgs.Port != "port" {
 		t.Errorf("Error port want port, got %s", sinkArgs.Port)
 	}
+	if sinkArgs.MetricsPort != "9000" {
+		t.Errorf("Error metricsport want 9000, got %s", sinkArgs.MetricsPort)
+	}
+	if sinkArgs.ELShutdownTimeOut != 10 {
+		t.Errorf("Error shutdowntimeout want 10, got %d", sinkArgs.ELShutdownTimeOut)
+	}
 }
 
 func Test_GetArgs_error(t *testing.T) {
@@ -85,3 +91,93 @@ func Test_GetArgs_error(t *testing.T) {
 		})
 	}
 }
+
+func Test_GetArgs_TLS(t *testing.T) {
+	for _, f := range []string{name, elNamespace, port} {
+		if err := flag.Set(f, "value"); err != nil {
+			t.Fatalf("Error setting flag %s: %s", f, err)
+		}
+	}
+	tests := []struct {
+		name     string
+		certFile string
+		keyFile  string
+		caFile   string
+		wantErr  bool
+	}{{
+		name:     "certificate and key",
+		certFile: "tls.crt",
+		keyFile:  "tls.key",
+	}, {
+		name:     "client CA",
+		certFile: "tls.crt",
+		keyFile:  "tls.key",
+		caFile:   "ca.crt",
+	}, {
+		name:     "certificate without key",
+		certFile: "tls.crt",
+		wantErr:  true,
+	}, {
+		name:    "client CA without certificate",
+		caFile:  "ca.crt",
+		wantErr: true,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for f, v := range map[string]string{"tlscertfile": tt.certFile, "tlskeyfile": tt.keyFile, "tlsclientcafile": tt.caFile} {
+				if err := flag.Set(f, v); err != nil {
+					t.Fatalf("Error setting flag %s: %s", f, err)
+				}
+				defer func(f string) { _ = flag.Set(f, "") }(f)
+			}
+			sinkArgs, err := GetArgs()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetArgs() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if err == nil && (sinkArgs.TLSCertFile != tt.certFile || sinkArgs.TLSKeyFile != tt.keyFile || sinkArgs.TLSClientCAFile != tt.caFile) {
+				t.Errorf("GetArgs() returned unexpected TLS files: %+v", sinkArgs)
+			}
+		})
+	}
+}
+
+func Test_GetArgs_Admin(t *testing.T) {
+	for _, f := range []string{name, elNamespace, port} {
+		if err := flag.Set(f, "value"); err != nil {
+			t.Fatalf("Error setting flag %s: %s", f, err)
+		}
+	}
+	tests := []struct {
+		name      string
+		adminPort string
+		tokenFile string
+		wantErr   bool
+	}{{
+		name: "no admin endpoint",
+	}, {
+		name:      "admin endpoint",
+		adminPort: "9001",
+		tokenFile: "token",
+	}, {
+		name:      "admin endpoint without token",
+		adminPort: "9001",
+		wantErr:   true,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for f, v := range map[string]string{"adminport": tt.adminPort, "admintokenfile": tt.tokenFile} {
+				if err := flag.Set(f, v); err != nil {
+					t.Fatalf("Error setting flag %s: %s", f, err)
+				}
+				defer func(f string) { _ = flag.Set(f, "") }(f)
+			}
+			sinkArgs, err := GetArgs()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetArgs() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if err == nil && (sinkArgs.AdminPort != tt.adminPort || sinkArgs.AdminTokenFile != tt.tokenFile || sinkArgs.RecentEvents != 100) {
+				t.Errorf("GetArgs() returned unexpected admin args: %+v", sinkArgs)
+			}
+		})
+	}
+}
@@ -0,0 +1,224 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sink
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	triggersv1 "github.com/tektoncd/triggers/pkg/apis/triggers/v1alpha1"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/status"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/util/workqueue"
+)
+
+const (
+	// Backoff bounds between retries of a failed Trigger
+	queueBaseDelay = 500 * time.Millisecond
+	queueMaxDelay  = 30 * time.Second
+)
+
+// ErrQueueFull is returned when an event cannot be queued because the
+// EventQueue is at capacity or is draining.
+var ErrQueueFull = errors.New("event queue is full")
+
+// queuedTrigger is a single Trigger of an event waiting to be processed. With
+// the FirstMatch MatchPolicy, it holds all of the EventListener's Triggers in
+// the order they are evaluated.
+type queuedTrigger struct {
+	triggers []triggersv1.EventListenerTrigger
+	request  *http.Request
+	event    []byte
+	eventID  string
+	log      *zap.SugaredLogger
+	// done, if set, is called once the Triggers are processed
+	done func()
+	// delivery, if set, is the delivery key of the event, released when
+	// none of its Triggers created their resources
+	delivery *queuedDelivery
+
+	// index is the Trigger that a retry resumes from, along with its
+	// progress, if any
+	index    int
+	progress *triggerProgress
+	// requeued is set while the Triggers wait out the backoff of a retry
+	requeued bool
+}
+
+// EventQueue is a bounded work queue that processes the Triggers of events
+// handled asynchronously by the Sink on a fixed number of workers.
+type EventQueue struct {
+	queue      workqueue.RateLimitingInterface
+	size       int
+	workers    int
+	maxRetries int
+
+	// pending counts the Triggers that are queued, being processed or
+	// waiting to be retried. backoff holds those waiting to be retried,
+	// which the queue drops once it shuts down.
+	mu      sync.Mutex
+	pending int
+	backoff map[*queuedTrigger]struct{}
+	process func(*queuedTrigger, bool) error
+	wg      sync.WaitGroup
+}
+
+// NewEventQueue returns an EventQueue holding at most size Triggers, processed
+// by the given number of workers. A failed Trigger is retried up to maxRetries
+// times with an exponential backoff.
+func NewEventQueue(size, workers, maxRetries int) *EventQueue {
+	return &EventQueue{
+		queue:      workqueue.NewRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(queueBaseDelay, queueMaxDelay)),
+		size:       size,
+		workers:    workers,
+		maxRetries: maxRetries,
+		backoff:    map[*queuedTrigger]struct{}{},
+	}
+}
+
+// add queues all of the given Triggers, or none of them if there is not
+// enough room left in the queue.
+func (q *EventQueue) add(items ...*queuedTrigger) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.queue.ShuttingDown() || q.pending+len(items) > q.size {
+		return ErrQueueFull
+	}
+	q.pending += len(items)
+	for _, item := range items {
+		q.queue.Add(item)
+	}
+	return nil
+}
+
+// run starts the workers, each calling process for the Triggers it takes
+// off the queue. process is told whether the attempt is final, in which case
+// the Triggers are not retried whatever the error.
+func (q *EventQueue) run(process func(*queuedTrigger, bool) error) {
+	q.mu.Lock()
+	q.process = process
+	q.mu.Unlock()
+	for i := 0; i < q.workers; i++ {
+		q.wg.Add(1)
+		go func() {
+			defer q.wg.Done()
+			for q.processNext(process) {
+			}
+		}()
+	}
+}
+
+func (q *EventQueue) processNext(process func(*queuedTrigger, bool) error) bool {
+	obj, shutdown := q.queue.Get()
+	if shutdown {
+		return false
+	}
+	defer q.queue.Done(obj)
+	item := obj.(*queuedTrigger)
+	q.mu.Lock()
+	if item.requeued {
+		if _, ok := q.backoff[item]; !ok {
+			// Drain already made the final attempt at the Triggers
+			q.mu.Unlock()
+			return true
+		}
+		delete(q.backoff, item)
+		item.requeued = false
+	}
+	q.mu.Unlock()
+
+	// Triggers are not retried once the queue is draining.
+	final := q.queue.NumRequeues(item) >= q.maxRetries || q.queue.ShuttingDown()
+	err := process(item, final)
+	if err != nil && !final && isRetryable(err) {
+		q.mu.Lock()
+		if !q.queue.ShuttingDown() {
+			item.log.Infof("Retrying trigger after error: %s", err)
+			item.requeued = true
+			q.backoff[item] = struct{}{}
+			q.queue.AddRateLimited(item)
+			q.mu.Unlock()
+			return true
+		}
+		q.mu.Unlock()
+		// The queue started draining during the attempt, so the final
+		// attempt is made without waiting on the backoff.
+		item.log.Infof("Retrying trigger while draining the event queue after error: %s", err)
+		process(item, true)
+	}
+	q.finish(item)
+	return true
+}
+
+// finish releases the Triggers of item once they are processed.
+func (q *EventQueue) finish(item *queuedTrigger) {
+	q.queue.Forget(item)
+	q.mu.Lock()
+	q.pending--
+	q.mu.Unlock()
+	if item.done != nil {
+		item.done()
+	}
+}
+
+// Drain stops the EventQueue from accepting new events and waits up to
+// timeout for the workers to process the Triggers that are already queued.
+// The Triggers waiting to be retried get their final attempt right away, as
+// the queue no longer delivers them once it shuts down.
+func (q *EventQueue) Drain(timeout time.Duration) error {
+	q.mu.Lock()
+	q.queue.ShutDown()
+	waiting := make([]*queuedTrigger, 0, len(q.backoff))
+	for item := range q.backoff {
+		waiting = append(waiting, item)
+	}
+	q.backoff = map[*queuedTrigger]struct{}{}
+	process := q.process
+	q.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		for _, item := range waiting {
+			item.log.Info("Retrying trigger while draining the event queue")
+			process(item, true)
+			q.finish(item)
+		}
+		q.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-time.After(timeout):
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		return fmt.Errorf("timed out after %s, %d triggers left in the event queue were not processed", timeout, q.pending)
+	}
+}
+
+// isRetryable reports whether processing a Trigger might succeed if tried
+// again. Interceptor rejections and authorization failures will not.
+func isRetryable(err error) bool {
+	if _, ok := status.FromError(err); ok {
+		return false
+	}
+	return !kerrors.IsUnauthorized(err) && !kerrors.IsForbidden(err)
+}
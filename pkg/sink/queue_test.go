@@ -0,0 +1,151 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sink
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zaptest"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+func TestEventQueue_Retries(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		err       error
+		wantCalls int
+	}{{
+		name:      "success",
+		err:       nil,
+		wantCalls: 1,
+	}, {
+		name:      "retryable error",
+		err:       errors.New("connection refused"),
+		wantCalls: 3,
+	}, {
+		name:      "interceptor rejection",
+		err:       status.Error(codes.FailedPrecondition, "did not match"),
+		wantCalls: 1,
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			q := NewEventQueue(10, 1, 2)
+			var mu sync.Mutex
+			calls := 0
+			var finals []bool
+			q.run(func(_ *queuedTrigger, final bool) error {
+				mu.Lock()
+				defer mu.Unlock()
+				calls++
+				finals = append(finals, final)
+				return tc.err
+			})
+			if err := q.add(&queuedTrigger{log: zaptest.NewLogger(t).Sugar()}); err != nil {
+				t.Fatalf("add() unexpected error: %v", err)
+			}
+			if err := wait.PollImmediate(10*time.Millisecond, wait.ForeverTestTimeout, func() (bool, error) {
+				q.mu.Lock()
+				defer q.mu.Unlock()
+				return q.pending == 0, nil
+			}); err != nil {
+				t.Fatalf("trigger was not processed: %v", err)
+			}
+			if err := q.Drain(wait.ForeverTestTimeout); err != nil {
+				t.Fatalf("Drain() unexpected error: %v", err)
+			}
+			if calls != tc.wantCalls {
+				t.Errorf("process called %d times, want %d", calls, tc.wantCalls)
+			}
+			// Only the last attempt at a Trigger that is retried is final
+			for i, final := range finals {
+				wantFinal := tc.err != nil && isRetryable(tc.err) && i == len(finals)-1
+				if final != wantFinal {
+					t.Errorf("attempt %d final: want %t, got %t", i+1, wantFinal, final)
+				}
+			}
+		})
+	}
+}
+
+func TestEventQueue_Drain(t *testing.T) {
+	q := NewEventQueue(2, 1, 0)
+	log := zaptest.NewLogger(t).Sugar()
+	released := false
+	done := releaseAfter(2, func() { released = true })
+	if err := q.add(&queuedTrigger{log: log, done: done}, &queuedTrigger{log: log, done: done}); err != nil {
+		t.Fatalf("add() unexpected error: %v", err)
+	}
+	if err := q.add(&queuedTrigger{log: log}); err != ErrQueueFull {
+		t.Errorf("add() to a full queue: want %v, got %v", ErrQueueFull, err)
+	}
+
+	processed := 0
+	q.run(func(*queuedTrigger, bool) error {
+		processed++
+		return nil
+	})
+	if err := q.Drain(wait.ForeverTestTimeout); err != nil {
+		t.Fatalf("Drain() unexpected error: %v", err)
+	}
+	if processed != 2 {
+		t.Errorf("processed %d queued triggers before draining, want 2", processed)
+	}
+	if !released {
+		t.Error("expected the event to be released once its queued triggers were processed")
+	}
+	if err := q.add(&queuedTrigger{log: log}); err != ErrQueueFull {
+		t.Errorf("add() to a drained queue: want %v, got %v", ErrQueueFull, err)
+	}
+}
+
+func TestEventQueue_DrainBackoff(t *testing.T) {
+	q := NewEventQueue(1, 1, 2)
+	var mu sync.Mutex
+	var finals []bool
+	q.run(func(_ *queuedTrigger, final bool) error {
+		mu.Lock()
+		defer mu.Unlock()
+		finals = append(finals, final)
+		return errors.New("connection refused")
+	})
+	released := false
+	if err := q.add(&queuedTrigger{log: zaptest.NewLogger(t).Sugar(), done: func() { released = true }}); err != nil {
+		t.Fatalf("add() unexpected error: %v", err)
+	}
+	// Drain while the Trigger waits out the backoff of its first retry
+	if err := wait.PollImmediate(time.Millisecond, wait.ForeverTestTimeout, func() (bool, error) {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		return len(q.backoff) == 1, nil
+	}); err != nil {
+		t.Fatalf("trigger was not retried: %v", err)
+	}
+	if err := q.Drain(wait.ForeverTestTimeout); err != nil {
+		t.Fatalf("Drain() unexpected error: %v", err)
+	}
+	if want := []bool{false, true}; !reflect.DeepEqual(finals, want) {
+		t.Errorf("attempts final = %v, want %v", finals, want)
+	}
+	if !released {
+		t.Error("expected the event to be released once the final attempt was made")
+	}
+}
@@ -0,0 +1,227 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sink
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	triggersv1 "github.com/tektoncd/triggers/pkg/apis/triggers/v1alpha1"
+	"github.com/tektoncd/triggers/pkg/interceptors/cel"
+	"github.com/tektoncd/triggers/pkg/metrics"
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// defaultRateLimitPeriod is the period of a RateLimit that does not set
+	// one.
+	defaultRateLimitPeriod = time.Second
+	// minRetryAfter is the delay suggested to clients rejected because too
+	// many events are in flight, for which there is no better estimate.
+	minRetryAfter = time.Second
+	// rateLimitersPruneInterval is how often idle limiters are removed from
+	// the RateLimiters.
+	rateLimitersPruneInterval = time.Minute
+)
+
+// rateLimitedError is returned when an event is rejected by a RateLimit.
+type rateLimitedError struct {
+	retryAfter time.Duration
+}
+
+func (e *rateLimitedError) Error() string {
+	return fmt.Sprintf("rate limit exceeded, retry after %s", e.retryAfter)
+}
+
+// retryAfter returns the delay after which an event rejected with err by a
+// RateLimit may be retried, and whether err is such a rejection.
+func retryAfter(err error) (time.Duration, bool) {
+	var rl *rateLimitedError
+	if errors.As(err, &rl) {
+		return rl.retryAfter, true
+	}
+	return 0, false
+}
+
+// setRetryAfter sets the Retry-After header of the response to d, rounded up
+// to whole seconds.
+func setRetryAfter(response http.ResponseWriter, d time.Duration) {
+	seconds := int64(math.Ceil(d.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	response.Header().Set("Retry-After", strconv.FormatInt(seconds, 10))
+}
+
+// releaseAfter returns a function that calls release once it has been called
+// n times.
+func releaseAfter(n int, release func()) func() {
+	remaining := int32(n)
+	return func() {
+		if atomic.AddInt32(&remaining, -1) == 0 {
+			release()
+		}
+	}
+}
+
+// limiter holds the state of a RateLimit for a single key.
+type limiter struct {
+	limit       rate.Limit
+	burst       int
+	maxInFlight int
+	// tokens is nil when the rate is not limited
+	tokens   *rate.Limiter
+	inFlight int
+	// idle is how long the limiter must go unused before its bucket is full
+	// again, after which it can be forgotten.
+	idle     time.Duration
+	lastUsed time.Time
+}
+
+// RateLimiters keeps the state of the RateLimits of an EventListener and its
+// Triggers in memory, so each replica of the EventListener limits the events
+// it receives on its own.
+type RateLimiters struct {
+	mu        sync.Mutex
+	limiters  map[string]*limiter
+	lastPrune time.Time
+	now       func() time.Time
+}
+
+// NewRateLimiters returns empty RateLimiters.
+func NewRateLimiters() *RateLimiters {
+	return &RateLimiters{
+		limiters: map[string]*limiter{},
+		now:      time.Now,
+	}
+}
+
+// acquire admits an event under the RateLimit for key. If the event is
+// admitted, it returns a function to call once the event is processed.
+// Otherwise, it returns the delay after which the event may be retried.
+func (l *RateLimiters) acquire(key string, spec *triggersv1.RateLimit) (func(), time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	l.prune(now)
+
+	period := defaultRateLimitPeriod
+	if spec.Period != nil {
+		period = spec.Period.Duration
+	}
+	limit := rate.Limit(float64(spec.Limit) / period.Seconds())
+	burst := int(spec.Burst)
+	if burst == 0 {
+		burst = int(spec.Limit)
+	}
+
+	lim, ok := l.limiters[key]
+	if !ok || lim.limit != limit || lim.burst != burst {
+		// Start over when the RateLimit changes, keeping the events in flight
+		updated := &limiter{limit: limit, burst: burst}
+		if spec.Limit > 0 {
+			updated.tokens = rate.NewLimiter(limit, burst)
+			updated.idle = time.Duration(float64(burst) / float64(limit) * float64(time.Second))
+		}
+		if ok {
+			updated.inFlight = lim.inFlight
+		}
+		lim = updated
+		l.limiters[key] = lim
+	}
+	lim.maxInFlight = int(spec.MaxInFlight)
+	lim.lastUsed = now
+
+	if lim.maxInFlight > 0 && lim.inFlight >= lim.maxInFlight {
+		return nil, minRetryAfter, false
+	}
+	if lim.tokens != nil {
+		r := lim.tokens.ReserveN(now, 1)
+		if delay := r.DelayFrom(now); delay > 0 {
+			r.CancelAt(now)
+			return nil, delay, false
+		}
+	}
+
+	lim.inFlight++
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			lim.inFlight--
+			lim.lastUsed = l.now()
+		})
+	}, 0, true
+}
+
+// prune forgets the limiters that have no events in flight and have been
+// idle long enough for their bucket to be full again.
+func (l *RateLimiters) prune(now time.Time) {
+	if now.Sub(l.lastPrune) < rateLimitersPruneInterval {
+		return
+	}
+	for key, lim := range l.limiters {
+		if lim.inFlight == 0 && now.Sub(lim.lastUsed) >= lim.idle {
+			delete(l.limiters, key)
+		}
+	}
+	l.lastPrune = now
+}
+
+// limitEvent admits the event under the RateLimit of the EventListener, or of
+// the Trigger when trigger is not empty. If the event is admitted, it returns
+// a function to call once the event is processed. Rejections are logged and
+// recorded in the metrics.
+func (r Sink) limitEvent(spec *triggersv1.RateLimit, trigger string, request *http.Request, event []byte, log *zap.SugaredLogger) (func(), error) {
+	if spec == nil || r.RateLimiters == nil {
+		return func() {}, nil
+	}
+
+	key := "eventlistener"
+	if trigger != "" {
+		key = "trigger/" + trigger
+	}
+	if spec.Key != "" {
+		value, err := cel.EvaluateString(spec.Key, event, request.Header, request.URL.String(), sourceIP(request.Context()), r.EventListenerNamespace, r.KubeClientSet)
+		if err != nil {
+			// Limit the events without a key together rather than
+			// rejecting them.
+			log.Errorf("Error evaluating the rate limit key of the event: %s", err)
+		}
+		key += "/" + value
+	}
+
+	release, delay, ok := r.RateLimiters.acquire(key, spec)
+	if !ok {
+		if trigger != "" {
+			log.Infof("Rate limit of Trigger %s exceeded, retry after %s", trigger, delay)
+		} else {
+			log.Infof("Rate limit of EventListener %s exceeded, retry after %s", r.EventListenerName, delay)
+		}
+		metrics.RecordRateLimited(r.EventListenerName, trigger)
+		return nil, &rateLimitedError{retryAfter: delay}
+	}
+	return release, nil
+}
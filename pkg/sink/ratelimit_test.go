@@ -0,0 +1,103 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sink
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	triggersv1 "github.com/tektoncd/triggers/pkg/apis/triggers/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRateLimiters_Rate(t *testing.T) {
+	now := time.Date(2020, 10, 1, 0, 0, 0, 0, time.UTC)
+	limiters := NewRateLimiters()
+	limiters.now = func() time.Time { return now }
+	spec := &triggersv1.RateLimit{Limit: 2, Period: &metav1.Duration{Duration: time.Minute}}
+
+	acquire := func(key string, wantOK bool, wantRetryAfter time.Duration) {
+		t.Helper()
+		release, retryAfter, ok := limiters.acquire(key, spec)
+		if ok != wantOK || retryAfter != wantRetryAfter {
+			t.Errorf("acquire(%q) = (%s, %t), want (%s, %t)", key, retryAfter, ok, wantRetryAfter, wantOK)
+		}
+		if ok {
+			release()
+		}
+	}
+
+	acquire("a", true, 0)
+	acquire("a", true, 0)
+	acquire("a", false, 30*time.Second)
+	// Keys are limited separately
+	acquire("b", true, 0)
+
+	now = now.Add(30 * time.Second)
+	acquire("a", true, 0)
+	acquire("a", false, 30*time.Second)
+
+	// Idle limiters are forgotten once their bucket is full again
+	now = now.Add(time.Minute)
+	acquire("b", true, 0)
+	if _, ok := limiters.limiters["a"]; ok {
+		t.Error("expected the idle limiter of key a to be pruned")
+	}
+}
+
+func TestRateLimiters_MaxInFlight(t *testing.T) {
+	limiters := NewRateLimiters()
+	spec := &triggersv1.RateLimit{MaxInFlight: 1}
+
+	release, _, ok := limiters.acquire("a", spec)
+	if !ok {
+		t.Fatal("acquire() rejected the first event")
+	}
+	if _, retryAfter, ok := limiters.acquire("a", spec); ok || retryAfter != minRetryAfter {
+		t.Errorf("acquire() with an event in flight = (%s, %t), want (%s, false)", retryAfter, ok, minRetryAfter)
+	}
+	release()
+	// Releasing twice has no effect
+	release()
+	release, _, ok = limiters.acquire("a", spec)
+	if !ok {
+		t.Fatal("acquire() rejected an event after the event in flight was released")
+	}
+	if _, _, ok := limiters.acquire("a", spec); ok {
+		t.Error("acquire() admitted a second event in flight")
+	}
+	release()
+}
+
+func TestSetRetryAfter(t *testing.T) {
+	for _, tc := range []struct {
+		delay time.Duration
+		want  string
+	}{
+		{delay: 0, want: "1"},
+		{delay: 200 * time.Millisecond, want: "1"},
+		{delay: 2 * time.Second, want: "2"},
+		{delay: 2500 * time.Millisecond, want: "3"},
+	} {
+		rec := httptest.NewRecorder()
+		setRetryAfter(rec, tc.delay)
+		if got := rec.Header().Get("Retry-After"); got != tc.want {
+			t.Errorf("setRetryAfter(%s) set %q, want %q", tc.delay, got, tc.want)
+		}
+	}
+}
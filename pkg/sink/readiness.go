@@ -0,0 +1,80 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sink
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"k8s.io/client-go/tools/cache"
+)
+
+// Readiness serves the readiness probe of the sink. The sink is ready once
+// the informers backing its listers have synced.
+type Readiness struct {
+	synced []cache.InformerSynced
+}
+
+// NewReadiness returns the Readiness of a sink whose listers are backed by
+// the informers reporting whether they have synced with synced.
+func NewReadiness(synced ...cache.InformerSynced) *Readiness {
+	return &Readiness{synced: synced}
+}
+
+func (r *Readiness) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	for _, synced := range r.synced {
+		if !synced() {
+			http.Error(w, "informers not synced", http.StatusServiceUnavailable)
+			return
+		}
+	}
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, "ok")
+}
+
+// Drainer keeps track of the requests being handled so that they can be
+// waited for when the sink shuts down. Unlike the server's own shutdown, it
+// also waits for the handlers of requests that already timed out.
+type Drainer struct {
+	wg sync.WaitGroup
+}
+
+// Track wraps h so that the requests it handles are waited for by Wait.
+func (d *Drainer) Track(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		d.wg.Add(1)
+		defer d.wg.Done()
+		h.ServeHTTP(w, r)
+	})
+}
+
+// Wait waits up to timeout for the requests being handled to complete.
+func (d *Drainer) Wait(timeout time.Duration) error {
+	done := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-time.After(timeout):
+		return fmt.Errorf("timed out after %s waiting for the events being handled", timeout)
+	}
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sink
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestReadiness(t *testing.T) {
+	synced := false
+	r := NewReadiness(func() bool { return true }, func() bool { return synced })
+
+	probe := func() int {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+		return rec.Code
+	}
+	if got := probe(); got != http.StatusServiceUnavailable {
+		t.Errorf("readiness before the informers synced = %d, want %d", got, http.StatusServiceUnavailable)
+	}
+	synced = true
+	if got := probe(); got != http.StatusOK {
+		t.Errorf("readiness once the informers synced = %d, want %d", got, http.StatusOK)
+	}
+}
+
+func TestDrainer(t *testing.T) {
+	var d Drainer
+	release := make(chan struct{})
+	started := make(chan struct{})
+	h := d.Track(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+	}))
+	go h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", nil))
+	<-started
+
+	if err := d.Wait(10 * time.Millisecond); err == nil {
+		t.Error("Wait() expected a timeout while a request is being handled")
+	}
+	close(release)
+	if err := d.Wait(time.Second); err != nil {
+		t.Errorf("Wait() unexpected error once the request was handled: %v", err)
+	}
+}
@@ -17,13 +17,17 @@ limitations under the License.
 package sink
 
 import (
-	"bytes"
+	"compress/gzip"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
+	"sort"
+	"strings"
+	"time"
 
 	triggersv1 "github.com/tektoncd/triggers/pkg/apis/triggers/v1alpha1"
 	triggersclientset "github.com/tektoncd/triggers/pkg/client/clientset/versioned"
@@ -34,10 +38,18 @@ import (
 	"github.com/tektoncd/triggers/pkg/interceptors/github"
 	"github.com/tektoncd/triggers/pkg/interceptors/gitlab"
 	"github.com/tektoncd/triggers/pkg/interceptors/webhook"
+	"github.com/tektoncd/triggers/pkg/metrics"
 	"github.com/tektoncd/triggers/pkg/resources"
 	"github.com/tektoncd/triggers/pkg/template"
+	"github.com/tektoncd/triggers/pkg/tracing"
+	"go.opentelemetry.io/otel/api/trace"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/label"
 	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	discoveryclient "k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
@@ -55,6 +67,45 @@ type Sink struct {
 	EventListenerNamespace string
 	Logger                 *zap.SugaredLogger
 	Auth                   AuthOverride
+	// EventQueue processes the Triggers of events when the EventListener
+	// is in the Async processing mode
+	EventQueue *EventQueue
+	// DeliveryStore remembers the events already delivered when the
+	// EventListener deduplicates events
+	DeliveryStore DeliveryStore
+	// MaxBodySize is the maximum size in bytes of the (decompressed) body of
+	// an event. There is no limit when it is zero.
+	MaxBodySize int64
+	// MaxBatchSize is the maximum number of events in a batch, which are
+	// processed in turn within the timeout of the handler. There is no limit
+	// when it is zero.
+	MaxBatchSize int
+	// RateLimiters keeps the state of the rate limits of the EventListener
+	// and its Triggers. Events are not limited when it is nil.
+	RateLimiters *RateLimiters
+	// EventStore keeps the recent events so that they can be replayed
+	// through the admin endpoint. Events are not kept when it is nil.
+	EventStore *EventStore
+	// CloudEvents sends the CloudEvents to the EventListener's
+	// CloudEventURI in the background. They are sent while processing events
+	// when it is nil.
+	CloudEvents *CloudEventSender
+	// InterceptorClients holds the HTTP clients reaching ClusterInterceptors
+	// and WebhookInterceptors with their certificates, and the circuit
+	// breakers of their endpoints. Clients and breakers are not reused across
+	// events when it is nil.
+	InterceptorClients *interceptors.Clients
+	// InterceptorTokenFile holds the ServiceAccount token that the sink
+	// authenticates to ClusterInterceptors with. No token is sent when it is
+	// empty.
+	InterceptorTokenFile string
+	// Secrets caches the secrets that authenticate events across requests.
+	// They are read for every request when it is nil.
+	Secrets *interceptors.SecretCache
+	// TokenReviews caches the outcome of reviewing the tokens that
+	// authenticate events. Tokens are reviewed for every request when it is
+	// nil.
+	TokenReviews *interceptors.TokenReviewCache
 
 	// listers index properties about resources
 	EventListenerLister         listers.EventListenerLister
@@ -62,6 +113,7 @@ type Sink struct {
 	TriggerBindingLister        listers.TriggerBindingLister
 	ClusterTriggerBindingLister listers.ClusterTriggerBindingLister
 	TriggerTemplateLister       listers.TriggerTemplateLister
+	ClusterInterceptorLister    listers.ClusterInterceptorLister
 }
 
 // Response defines the HTTP body that the Sink responds to events with.
@@ -72,54 +124,298 @@ type Response struct {
 	Namespace string `json:"namespace,omitempty"`
 	// EventID is a uniqueID that gets assigned to each incoming request
 	EventID string `json:"eventID,omitempty"`
+	// Duplicate is true when the event is a redelivery of the event with
+	// EventID, which was already processed
+	Duplicate bool `json:"duplicate,omitempty"`
+	// Triggers holds the result of processing the event for each of the
+	// EventListener's Triggers
+	Triggers []TriggerResult `json:"triggers,omitempty"`
+}
+
+// The steps of processing a Trigger that can fail.
+const (
+	ResolveTriggerStep  = "ResolveTrigger"
+	InterceptorsStep    = "Interceptors"
+	RateLimitStep       = "RateLimit"
+	ResolveParamsStep   = "ResolveParams"
+	CreateResourcesStep = "CreateResources"
+)
+
+// TriggerResult describes the outcome of processing an event for a single
+// Trigger.
+type TriggerResult struct {
+	// Name is the name of the Trigger
+	Name string `json:"name,omitempty"`
+	// Filtered is true when an interceptor stopped processing the Trigger
+	Filtered bool `json:"filtered,omitempty"`
+	// Status is the status returned by the interceptor that filtered the
+	// Trigger
+	Status *InterceptorStatus `json:"status,omitempty"`
+	// FailedStep is the step at which processing the Trigger failed
+	FailedStep string `json:"failedStep,omitempty"`
+	// Error is the error that processing the Trigger failed with
+	Error string `json:"error,omitempty"`
+	// Resources are the resources created for the Trigger
+	Resources []CreatedResource `json:"resources,omitempty"`
+	// DryRun is true when the Trigger's resources were created with a
+	// dry run, so that Resources were not persisted
+	DryRun bool `json:"dryRun,omitempty"`
+}
+
+// InterceptorStatus is the gRPC status code and message returned by an
+// interceptor.
+type InterceptorStatus struct {
+	Code    string `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+// CreatedResource identifies a resource created by a Trigger.
+type CreatedResource struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+}
+
+// fail records that processing the Trigger failed at step with err.
+func (tr *TriggerResult) fail(step string, err error) {
+	tr.FailedStep = step
+	tr.Error = err.Error()
+}
+
+// matched reports whether the Trigger could be resolved and its interceptors
+// let the event continue. Triggers in dry run never match, so that they do not
+// change which Triggers create resources.
+func (tr TriggerResult) matched() bool {
+	return !tr.DryRun && !tr.Filtered && tr.FailedStep != ResolveTriggerStep && tr.FailedStep != InterceptorsStep
+}
+
+// outcome returns the metrics outcome of processing the Trigger.
+func (tr TriggerResult) outcome() string {
+	switch {
+	case tr.Filtered:
+		return metrics.OutcomeFiltered
+	case tr.FailedStep != "":
+		return metrics.OutcomeFailed
+	case tr.DryRun:
+		return metrics.OutcomeDryRun
+	default:
+		return metrics.OutcomeCreated
+	}
 }
 
 // HandleEvent processes an incoming HTTP event for the event listener.
 func (r Sink) HandleEvent(response http.ResponseWriter, request *http.Request) {
+	metrics.RecordEvent(r.EventListenerName)
+	ctx, span := tracing.StartSpanFromRequest(request, "HandleEvent")
+	defer span.End()
+	request = request.WithContext(ctx)
+	span.SetAttributes(label.String("eventlistener", r.EventListenerName))
+
+	request, el, triggers, event, ok := r.admitRequest(response, request)
+	if !ok {
+		return
+	}
+	event, err := toBinaryCloudEvent(request, event)
+	if err != nil {
+		r.Logger.Errorf("Error reading event body: %s", err)
+		response.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	replyCloudEvent := el.Spec.CloudEventReply && isCloudEvent(request.Header)
+
+	code, body, delay := r.processEvent(el, triggers, request, event)
+	if code == http.StatusTooManyRequests {
+		setRetryAfter(response, delay)
+	}
+	r.writeResponse(response, code, body, replyCloudEvent, r.Logger.With(zap.String(triggersv1.EventIDLabelKey, body.EventID)))
+}
+
+// admitRequest checks that the EventListener accepts the request and reads
+// its body, returning the request along with the EventListener, the Triggers
+// that respond to the request and the body. It responds to the requests that
+// are not admitted, returning false.
+func (r Sink) admitRequest(response http.ResponseWriter, request *http.Request) (*http.Request, *triggersv1.EventListener, []triggersv1.EventListenerTrigger, []byte, bool) {
 	el, err := r.EventListenerLister.EventListeners(r.EventListenerNamespace).Get(r.EventListenerName)
 	if err != nil {
 		r.Logger.Errorf("Error getting EventListener %s in Namespace %s: %s", r.EventListenerName, r.EventListenerNamespace, err)
 		response.WriteHeader(http.StatusInternalServerError)
-		return
+		return nil, nil, nil, nil, false
+	}
+	// Requests from addresses that are not allowed are rejected before
+	// anything else
+	callerIP, err := checkSource(el.Spec.AllowedSources, request)
+	if err != nil {
+		if errors.Is(err, errSourceNotAllowed) {
+			r.Logger.Infof("Rejected request to EventListener %s: %s", r.EventListenerName, err)
+			response.WriteHeader(http.StatusForbidden)
+		} else {
+			r.Logger.Errorf("Error checking the source of request to EventListener %s: %s", r.EventListenerName, err)
+			response.WriteHeader(http.StatusInternalServerError)
+		}
+		return nil, nil, nil, nil, false
+	}
+	request = request.WithContext(withSourceIP(request.Context(), callerIP))
+	// Requests are authenticated before their body is read, except for those
+	// signed with an HMAC
+	if err := r.authenticateRequest(el.Spec.Authentication, request); err != nil {
+		r.writeAuthenticationError(response, el.Spec.Authentication, err)
+		return nil, nil, nil, nil, false
+	}
+	triggers, rejectCode := routeTriggers(el.Spec.Triggers, request)
+	if rejectCode != 0 {
+		r.Logger.Infof("No Trigger of EventListener %s responds to %s %s", r.EventListenerName, request.Method, request.URL.Path)
+		response.WriteHeader(rejectCode)
+		return nil, nil, nil, nil, false
 	}
-	event, err := ioutil.ReadAll(request.Body)
+	event, err := r.readBody(request)
 	if err != nil {
 		r.Logger.Errorf("Error reading event body: %s", err)
-		response.WriteHeader(http.StatusInternalServerError)
-		return
+		switch {
+		case errors.Is(err, errBodyTooLarge):
+			response.WriteHeader(http.StatusRequestEntityTooLarge)
+		case errors.Is(err, gzip.ErrHeader), errors.Is(err, gzip.ErrChecksum):
+			response.WriteHeader(http.StatusBadRequest)
+		default:
+			response.WriteHeader(http.StatusInternalServerError)
+		}
+		return nil, nil, nil, nil, false
+	}
+	if err := r.verifySignature(el.Spec.Authentication, request, event); err != nil {
+		r.writeAuthenticationError(response, el.Spec.Authentication, err)
+		return nil, nil, nil, nil, false
 	}
+	return request, el, triggers, event, true
+}
 
+// processEvent processes the event for the Triggers of the EventListener that
+// respond to its request. It returns the status code and body of the response
+// to the event, along with how long to wait before sending it again when it
+// is rate limited.
+func (r Sink) processEvent(el *triggersv1.EventListener, triggers []triggersv1.EventListenerTrigger, request *http.Request, event []byte) (int, Response, time.Duration) {
 	eventID := template.UID()
 	eventLog := r.Logger.With(zap.String(triggersv1.EventIDLabelKey, eventID))
+	trace.SpanFromContext(request.Context()).SetAttributes(label.String("eventid", eventID))
 	eventLog.Debugf("EventListener: %s in Namespace: %s handling event (EventID: %s) with payload: %s and header: %v",
 		r.EventListenerName, r.EventListenerNamespace, eventID, string(event), request.Header)
 
-	result := make(chan int, 10)
-	// Execute each Trigger
-	for _, t := range el.Spec.Triggers {
-		go func(t triggersv1.EventListenerTrigger) {
-			localRequest := request.Clone(request.Context())
-			if err := r.processTrigger(&t, localRequest, event, eventID, eventLog); err != nil {
-				if kerrors.IsUnauthorized(err) {
-					result <- http.StatusUnauthorized
-					return
-				}
-				if kerrors.IsForbidden(err) {
-					result <- http.StatusForbidden
-					return
-				}
-				result <- http.StatusAccepted
-				return
+	deliveryKey, originalID := r.reserveDelivery(el, request, event, eventID, eventLog)
+	if originalID != "" {
+		return http.StatusOK, Response{EventID: originalID, Duplicate: true}, 0
+	}
+	r.EventStore.Add(StoredEvent{
+		EventID:    eventID,
+		Method:     request.Method,
+		URL:        request.URL.String(),
+		Header:     request.Header.Clone(),
+		Body:       event,
+		ReceivedAt: time.Now(),
+	})
+
+	release, err := r.limitEvent(el.Spec.RateLimit, "", request, event, eventLog)
+	if err != nil {
+		r.releaseDelivery(deliveryKey, eventLog)
+		delay, _ := retryAfter(err)
+		return http.StatusTooManyRequests, Response{EventID: eventID}, delay
+	}
+
+	// Triggers are processed with a context that is not cancelled with the
+	// request, such as by the timeout of the handler or the client
+	// disconnecting, so that they do not stop with only some of their
+	// resources created. The request context is only used to respond.
+	triggerRequest := request.Clone(withSourceIP(tracing.Detach(request.Context()), sourceIP(request.Context())))
+	firstMatch := el.Spec.MatchPolicy == triggersv1.FirstMatchPolicy
+	if el.Spec.ProcessingMode == triggersv1.AsyncProcessingMode && r.EventQueue != nil {
+		// Each Trigger is queued on its own, unless they are evaluated in
+		// order for the first match.
+		var queued [][]triggersv1.EventListenerTrigger
+		if firstMatch && len(triggers) > 0 {
+			queued = append(queued, byPriority(triggers))
+		} else {
+			for _, t := range triggers {
+				queued = append(queued, []triggersv1.EventListenerTrigger{t})
 			}
-			result <- http.StatusCreated
-		}(t)
+		}
+		if len(queued) == 0 {
+			release()
+			r.releaseDelivery(deliveryKey, eventLog)
+			r.emitCloudEvent(EventReceivedCloudEventType, LifecycleEvent{EventID: eventID}, eventLog)
+			return http.StatusAccepted, Response{EventID: eventID}, 0
+		}
+		items := make([]*queuedTrigger, 0, len(queued))
+		// The event is in flight until all of its queued Triggers are processed
+		done := releaseAfter(len(queued), release)
+		delivery := newQueuedDelivery(deliveryKey, len(queued))
+		for _, group := range queued {
+			items = append(items, &queuedTrigger{
+				triggers: group,
+				request:  triggerRequest.Clone(triggerRequest.Context()),
+				event:    event,
+				eventID:  eventID,
+				log:      eventLog,
+				done:     done,
+				delivery: delivery,
+			})
+		}
+		if err := r.EventQueue.add(items...); err != nil {
+			eventLog.Errorf("Error queueing event: %s", err)
+			release()
+			r.releaseDelivery(deliveryKey, eventLog)
+			return http.StatusServiceUnavailable, Response{EventID: eventID}, 0
+		}
+		r.emitCloudEvent(EventReceivedCloudEventType, LifecycleEvent{EventID: eventID}, eventLog)
+		return http.StatusAccepted, Response{EventID: eventID}, 0
+	}
+	defer release()
+	r.emitCloudEvent(EventReceivedCloudEventType, LifecycleEvent{EventID: eventID}, eventLog)
+
+	if firstMatch {
+		triggerResults, err := r.processFirstMatch(byPriority(triggers), triggerRequest, event, eventID, eventLog)
+		code := triggerResponseCode(err)
+		if n := len(triggerResults); n > 0 {
+			code = triggerResults[n-1].responseCode(err)
+		}
+		if code != http.StatusCreated {
+			r.releaseDelivery(deliveryKey, eventLog)
+		}
+		delay, _ := retryAfter(err)
+		return code, Response{EventID: eventID, Triggers: triggerResults}, delay
+	}
+
+	type triggerOutcome struct {
+		index  int
+		code   int
+		err    error
+		result TriggerResult
+	}
+	outcomes := make(chan triggerOutcome, len(triggers))
+	// Execute each Trigger
+	for i, t := range triggers {
+		go func(i int, t triggersv1.EventListenerTrigger) {
+			localRequest := triggerRequest.Clone(triggerRequest.Context())
+			result, err := r.processTrigger(&t, localRequest, event, eventID, eventLog)
+			outcomes <- triggerOutcome{index: i, code: result.responseCode(err), err: err, result: result}
+		}(i, t)
 	}
 
 	//The eventlistener waits until all the trigger executions (up-to the creation of the resources) and
 	//only when at least one of the execution completed successfully, it returns response code 201(Created) otherwise it returns 202 (Accepted).
 	code := http.StatusAccepted
-	for i := 0; i < len(el.Spec.Triggers); i++ {
-		thiscode := <-result
+	// The event is rejected with 429 (Too Many Requests) when nothing was
+	// created and a Trigger's rate limit was exceeded.
+	var limited bool
+	var delay time.Duration
+	results := make([]*TriggerResult, len(triggers))
+	for i := 0; i < len(triggers); i++ {
+		outcome := <-outcomes
+		results[outcome.index] = &outcome.result
+		if d, ok := retryAfter(outcome.err); ok {
+			limited = true
+			if d > delay {
+				delay = d
+			}
+		}
+		thiscode := outcome.code
 		// current take - if someone is doing unauthorized stuff, we abort immediately;
 		// unauthorized should be the final status code vs. the less than comparison
 		// below around accepted vs. created
@@ -131,79 +427,467 @@ func (r Sink) HandleEvent(response http.ResponseWriter, request *http.Request) {
 			code = thiscode
 		}
 	}
+	if code == http.StatusAccepted && limited {
+		code = http.StatusTooManyRequests
+	}
 
-	response.WriteHeader(code)
-	response.Header().Set("Content-Type", "application/json")
-	body := Response{
-		EventListener: r.EventListenerName,
-		Namespace:     r.EventListenerNamespace,
-		EventID:       eventID,
+	// Keep the results in the order of the EventListener's Triggers, leaving
+	// out those not yet processed when aborting early.
+	triggerResults := make([]TriggerResult, 0, len(results))
+	for _, result := range results {
+		if result != nil {
+			triggerResults = append(triggerResults, *result)
+		}
+	}
+	// Let a redelivery of the event try again if nothing was created.
+	if code != http.StatusCreated {
+		r.releaseDelivery(deliveryKey, eventLog)
+	}
+	return code, Response{EventID: eventID, Triggers: triggerResults}, delay
+}
+
+// routeTriggers returns the Triggers that respond to the path and method of
+// the request. If none of them do, it returns the status code to respond with:
+// 404 (Not Found) when none respond to the path, and 405 (Method Not Allowed)
+// otherwise.
+func routeTriggers(triggers []triggersv1.EventListenerTrigger, request *http.Request) ([]triggersv1.EventListenerTrigger, int) {
+	if len(triggers) == 0 {
+		return triggers, 0
+	}
+	routed := make([]triggersv1.EventListenerTrigger, 0, len(triggers))
+	pathFound := false
+	for _, t := range triggers {
+		if t.Path != "" && t.Path != request.URL.Path {
+			continue
+		}
+		pathFound = true
+		if t.Method != "" && !strings.EqualFold(t.Method, request.Method) {
+			continue
+		}
+		routed = append(routed, t)
+	}
+	switch {
+	case len(routed) > 0:
+		return routed, 0
+	case pathFound:
+		return nil, http.StatusMethodNotAllowed
+	default:
+		return nil, http.StatusNotFound
+	}
+}
+
+// triggerResponseCode returns the status code of the response for a Trigger
+// processed with err.
+func triggerResponseCode(err error) int {
+	if _, ok := retryAfter(err); ok {
+		return http.StatusTooManyRequests
+	}
+	switch {
+	case err == nil:
+		return http.StatusCreated
+	case kerrors.IsUnauthorized(err):
+		return http.StatusUnauthorized
+	case kerrors.IsForbidden(err):
+		return http.StatusForbidden
+	default:
+		return http.StatusAccepted
+	}
+}
+
+// responseCode returns the status code of the response for the Trigger
+// processed with err. Triggers in dry run do not create resources, so they
+// are at most accepted.
+func (tr TriggerResult) responseCode(err error) int {
+	code := triggerResponseCode(err)
+	if tr.DryRun && code == http.StatusCreated {
+		return http.StatusAccepted
+	}
+	return code
+}
+
+// byPriority returns the Triggers in the order they are evaluated with the
+// FirstMatch MatchPolicy: by descending Priority, keeping the order of the
+// Triggers with the same Priority.
+func byPriority(triggers []triggersv1.EventListenerTrigger) []triggersv1.EventListenerTrigger {
+	sorted := make([]triggersv1.EventListenerTrigger, len(triggers))
+	copy(sorted, triggers)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Priority > sorted[j].Priority
+	})
+	return sorted
+}
+
+// errBodyTooLarge is returned when the body of an event exceeds the Sink's
+// MaxBodySize.
+var errBodyTooLarge = errors.New("event body exceeds the maximum size")
+
+// readBody reads the body of the event, decompressing gzip encoded bodies.
+func (r Sink) readBody(request *http.Request) ([]byte, error) {
+	var body io.Reader = request.Body
+	if strings.EqualFold(request.Header.Get("Content-Encoding"), "gzip") {
+		gz, err := gzip.NewReader(request.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress body: %w", err)
+		}
+		defer gz.Close()
+		body = gz
+		// Interceptors and bindings see the decompressed body
+		request.Header.Del("Content-Encoding")
 	}
+	if r.MaxBodySize <= 0 {
+		return ioutil.ReadAll(body)
+	}
+	// Read one byte past the limit to tell whether the body exceeds it
+	payload, err := ioutil.ReadAll(io.LimitReader(body, r.MaxBodySize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(payload)) > r.MaxBodySize {
+		return nil, fmt.Errorf("%w of %d bytes", errBodyTooLarge, r.MaxBodySize)
+	}
+	return payload, nil
+}
+
+// writeResponse writes body as the response to the event, filling in the
+// EventListener's name and namespace. If cloudEvent is true, the response is
+// a CloudEvent in binary content mode.
+func (r Sink) writeResponse(response http.ResponseWriter, code int, body Response, cloudEvent bool, eventLog *zap.SugaredLogger) {
+	body.EventListener = r.EventListenerName
+	body.Namespace = r.EventListenerNamespace
+	if cloudEvent {
+		r.setCloudEventHeaders(response.Header(), ResponseCloudEventType, body.EventID)
+	}
+	response.Header().Set("Content-Type", "application/json")
+	response.WriteHeader(code)
 	if err := json.NewEncoder(response).Encode(body); err != nil {
 		eventLog.Errorf("failed to write back sink response: %w", err)
 	}
 }
 
-func (r Sink) processTrigger(t *triggersv1.EventListenerTrigger, request *http.Request, event []byte, eventID string, eventLog *zap.SugaredLogger) error {
+// StartWorkers starts the workers that process the Triggers of events
+// queued in the EventQueue.
+func (r Sink) StartWorkers() {
+	r.EventQueue.run(r.processQueued)
+}
 
-	if t == nil {
-		return errors.New("EventListenerTrigger not defined")
+// processQueued makes an attempt at processing the Triggers of a queued
+// event, resuming from where the previous attempt failed. Unless the attempt
+// is final, a Trigger that failed with a retryable error is left for the
+// queue to retry without recording its outcome, so that the outcome of each
+// Trigger is recorded once. The delivery key of the event is released once
+// all of its queued Triggers are processed if none of them created anything.
+func (r Sink) processQueued(qt *queuedTrigger, final bool) error {
+	var result TriggerResult
+	var err error
+	for i := qt.index; i < len(qt.triggers); i++ {
+		var progress *triggerProgress
+		result, progress, err = r.attemptTrigger(&qt.triggers[i], qt.progress, qt.request.Clone(qt.request.Context()), qt.event, qt.eventID, qt.log)
+		qt.progress = nil
+		last := result.matched() || i == len(qt.triggers)-1
+		if last && err != nil && !final && isRetryable(err) {
+			// The retry resumes from this Trigger
+			qt.index, qt.progress = i, progress
+			return err
+		}
+		r.recordTrigger(result, progress, qt.request, qt.event, qt.eventID, qt.log)
+		if last {
+			break
+		}
+	}
+	// Let a redelivery of the event try again if nothing was created.
+	if qt.delivery.processed(result.responseCode(err) == http.StatusCreated) {
+		r.releaseDelivery(qt.delivery.key, qt.log)
+	}
+	return err
+}
+
+// processFirstMatch evaluates the Triggers in order, processing the event for
+// each until one of them matches it. It returns the results of the evaluated
+// Triggers along with the error of the matching Trigger, or of the last one
+// if none matched. A single Trigger is simply processed.
+func (r Sink) processFirstMatch(triggers []triggersv1.EventListenerTrigger, request *http.Request, event []byte, eventID string, eventLog *zap.SugaredLogger) ([]TriggerResult, error) {
+	results := make([]TriggerResult, 0, len(triggers))
+	var err error
+	for i := range triggers {
+		var result TriggerResult
+		result, err = r.processTrigger(&triggers[i], request.Clone(request.Context()), event, eventID, eventLog)
+		results = append(results, result)
+		if result.matched() {
+			break
+		}
+	}
+	return results, err
+}
+
+// processTrigger processes the event for a single Trigger, returning the
+// TriggerResult describing the outcome along with any error. The outcome is
+// also recorded in the metrics and sent as a CloudEvent to the
+// EventListener's CloudEventURI.
+func (r Sink) processTrigger(t *triggersv1.EventListenerTrigger, request *http.Request, event []byte, eventID string, eventLog *zap.SugaredLogger) (TriggerResult, error) {
+	result, progress, err := r.attemptTrigger(t, nil, request, event, eventID, eventLog)
+	r.recordTrigger(result, progress, request, event, eventID, eventLog)
+	return result, err
+}
+
+// triggerProgress is how far processing the event for a Trigger got once its
+// interceptors let the event continue. Retrying the Trigger from its progress
+// does not run the interceptors again, nor create the resources that were
+// already created.
+type triggerProgress struct {
+	// trigger is the resolved Trigger, and original the Trigger as set on
+	// the EventListener
+	trigger  *triggersv1.EventListenerTrigger
+	original triggersv1.EventListenerTrigger
+	result   TriggerResult
+	log      *zap.SugaredLogger
+
+	// payload, header and extensions are returned by the interceptors
+	payload    []byte
+	header     http.Header
+	extensions map[string]interface{}
+
+	// rendered is true once the resources are rendered from params, leaving
+	// in resources those that are not created yet
+	rendered  bool
+	params    []triggersv1.Param
+	resources []json.RawMessage
+}
+
+// attemptTrigger makes an attempt at processing the event for a single
+// Trigger, resuming from progress if it is set. It returns the TriggerResult
+// describing the outcome along with any error, and the progress of the
+// Trigger once its interceptors let the event continue.
+func (r Sink) attemptTrigger(t *triggersv1.EventListenerTrigger, progress *triggerProgress, request *http.Request, event []byte, eventID string, eventLog *zap.SugaredLogger) (TriggerResult, *triggerProgress, error) {
+	ctx, span := tracing.StartSpan(request.Context(), "Trigger")
+	defer span.End()
+	request = request.WithContext(ctx)
+
+	var result TriggerResult
+	var err error
+	if progress == nil {
+		progress, result, err = r.interceptTrigger(t, request, event, eventID, eventLog)
+	}
+	if progress != nil {
+		result, err = r.createTriggerResources(progress, request, event, eventID)
 	}
+	span.SetAttributes(
+		label.String("trigger", result.Name),
+		label.String("outcome", result.outcome()),
+	)
+	if result.FailedStep != "" {
+		span.SetStatus(otelcodes.Error, result.Error)
+	}
+	return result, progress, err
+}
 
-	if t.Template == nil && t.TriggerRef != "" {
-		trigger, err := r.TriggerLister.Triggers(r.EventListenerNamespace).Get(t.TriggerRef)
+// recordTrigger records the outcome of processing the event for a Trigger in
+// the metrics and sends it as a CloudEvent to the EventListener's
+// CloudEventURI. If creating the resources failed, the event is also kept as
+// a failed event.
+func (r Sink) recordTrigger(result TriggerResult, progress *triggerProgress, request *http.Request, event []byte, eventID string, eventLog *zap.SugaredLogger) {
+	metrics.RecordTriggerOutcome(r.EventListenerName, result.Name, result.outcome())
+	r.emitTriggerResult(eventID, result, eventLog)
+	if progress == nil || result.DryRun || result.FailedStep != CreateResourcesStep {
+		return
+	}
+	r.storeFailedEvent(request.Context(), FailedEvent{
+		EventID:    eventID,
+		Trigger:    progress.original,
+		Method:     request.Method,
+		URL:        request.URL.String(),
+		Header:     request.Header,
+		Body:       event,
+		Extensions: progress.extensions,
+		Params:     progress.params,
+		Error:      result.Error,
+		FailedAt:   metav1.Now(),
+	}, progress.log)
+}
+
+// interceptTrigger resolves the Trigger and runs its interceptors, returning
+// the progress to create its resources from if they let the event continue.
+func (r Sink) interceptTrigger(t *triggersv1.EventListenerTrigger, request *http.Request, event []byte, eventID string, eventLog *zap.SugaredLogger) (*triggerProgress, TriggerResult, error) {
+	result := TriggerResult{}
+	// The Trigger as set on the EventListener is kept with a failed event,
+	// so that replaying it resolves the referenced Trigger again.
+	var original triggersv1.EventListenerTrigger
+	if t != nil {
+		original = *t
+	}
+	t, err := r.resolveTriggerRef(t, &result)
+	if err != nil {
+		return nil, result, err
+	}
+	result.DryRun = t.DryRun
+
+	log := eventLog.With(zap.String(triggersv1.TriggerLabelKey, t.Name))
+
+	payload, header, extensions, err := r.intercept(t, request, event, eventID, &result, log)
+	if err != nil || result.Filtered {
+		return nil, result, err
+	}
+	return &triggerProgress{
+		trigger:    t,
+		original:   original,
+		result:     result,
+		log:        log,
+		payload:    payload,
+		header:     header,
+		extensions: extensions,
+	}, result, nil
+}
+
+// createTriggerResources rate limits the event for the Trigger, then renders
+// and creates its resources, skipping what the progress shows was done by a
+// previous attempt.
+func (r Sink) createTriggerResources(p *triggerProgress, request *http.Request, event []byte, eventID string) (TriggerResult, error) {
+	t, log := p.trigger, p.log
+	p.result.FailedStep, p.result.Error = "", ""
+	if !p.rendered {
+		release, err := r.limitEvent(t.RateLimit, p.result.Name, request, event, log)
 		if err != nil {
-			r.Logger.Errorf("Error getting Trigger %s in Namespace %s: %s", t.TriggerRef, r.EventListenerNamespace, err)
-			return err
+			p.result.fail(RateLimitStep, err)
+			return p.result, err
 		}
-		trig, err := triggersv1.ToEventListenerTrigger(trigger.Spec)
+		defer release()
+
+		p.params, p.resources, err = r.renderResources(request.Context(), t, p.payload, p.header, p.extensions, &p.result, log)
 		if err != nil {
-			r.Logger.Errorf("Error changing Trigger to EventListenerTrigger: %s", err)
-			return err
+			return p.result, err
 		}
-		t = &trig
+		p.rendered = true
+	}
+	created, err := r.CreateResources(request.Context(), t.ServiceAccountName, p.resources, t.Name, eventID, t.Retry, t.DryRun, log)
+	p.resources = p.resources[len(created):]
+	for _, c := range created {
+		p.result.Resources = append(p.result.Resources, CreatedResource{
+			APIVersion: c.GetAPIVersion(),
+			Kind:       c.GetKind(),
+			Namespace:  c.GetNamespace(),
+			Name:       c.GetName(),
+		})
+	}
+	if err != nil {
+		log.Error(err)
+		p.result.fail(CreateResourcesStep, err)
+		return p.result, err
 	}
+	return p.result, nil
+}
 
-	log := eventLog.With(zap.String(triggersv1.TriggerLabelKey, t.Name))
+// resolveTriggerRef returns the Trigger that the EventListenerTrigger
+// references, or the EventListenerTrigger itself when it is defined inline,
+// naming the result after it. The rate limit and retry policy set on the
+// EventListener take precedence over those of the referenced Trigger, and
+// the Trigger is in dry run if either of them is.
+func (r Sink) resolveTriggerRef(t *triggersv1.EventListenerTrigger, result *TriggerResult) (*triggersv1.EventListenerTrigger, error) {
+	if t == nil {
+		err := errors.New("EventListenerTrigger not defined")
+		result.fail(ResolveTriggerStep, err)
+		return nil, err
+	}
+	result.Name = t.Name
+	if t.Template != nil || t.TriggerRef == "" {
+		return t, nil
+	}
+	if result.Name == "" {
+		result.Name = t.TriggerRef
+	}
+	trigger, err := r.TriggerLister.Triggers(r.EventListenerNamespace).Get(t.TriggerRef)
+	if err != nil {
+		r.Logger.Errorf("Error getting Trigger %s in Namespace %s: %s", t.TriggerRef, r.EventListenerNamespace, err)
+		result.fail(ResolveTriggerStep, err)
+		return nil, err
+	}
+	trig, err := triggersv1.ToEventListenerTrigger(trigger.Spec)
+	if err != nil {
+		r.Logger.Errorf("Error changing Trigger to EventListenerTrigger: %s", err)
+		result.fail(ResolveTriggerStep, err)
+		return nil, err
+	}
+	if trig.Name != "" {
+		result.Name = trig.Name
+	}
+	if t.RateLimit != nil {
+		trig.RateLimit = t.RateLimit
+	}
+	if t.Retry != nil {
+		trig.Retry = t.Retry
+	}
+	trig.DryRun = trig.DryRun || t.DryRun
+	return &trig, nil
+}
+
+// interceptorErrorCodes are the status codes of the interceptors that could
+// not process an event, as opposed to those that rejected it.
+var interceptorErrorCodes = map[codes.Code]bool{
+	codes.Unknown:          true,
+	codes.Internal:         true,
+	codes.Unavailable:      true,
+	codes.DeadlineExceeded: true,
+}
 
+// intercept runs the interceptors of the Trigger, returning the body, header
+// and extensions that its bindings are resolved against. The result is
+// marked as filtered when an interceptor stops processing the Trigger.
+func (r Sink) intercept(t *triggersv1.EventListenerTrigger, request *http.Request, event []byte, eventID string, result *TriggerResult, log *zap.SugaredLogger) ([]byte, http.Header, map[string]interface{}, error) {
 	finalPayload, header, iresp, err := r.ExecuteInterceptors(t, request, event, log, eventID)
 	if err != nil {
 		log.Error(err)
-		return err
+		result.fail(InterceptorsStep, err)
+		return nil, nil, nil, err
 	}
 
+	extensions := map[string]interface{}{}
 	if iresp != nil {
 		if !iresp.Continue {
-			log.Infof("interceptor stopped trigger processing: %w", iresp.Status.Err())
-			return iresp.Status.Err()
+			result.Status = &InterceptorStatus{
+				Code:    iresp.Status.Code().String(),
+				Message: iresp.Status.Message(),
+			}
+			// Interceptors that could not process the event fail the
+			// Trigger rather than filter the event
+			if interceptorErrorCodes[iresp.Status.Code()] {
+				err := fmt.Errorf("interceptor failed: %w", iresp.Status.Err())
+				log.Error(err)
+				result.fail(InterceptorsStep, err)
+				return nil, nil, nil, err
+			}
+			log.Infof("interceptor stopped trigger processing: %s", iresp.Status.Err())
+			result.Filtered = true
+			return nil, nil, nil, iresp.Status.Err()
+		}
+		if iresp.Extensions != nil {
+			extensions = iresp.Extensions
 		}
 	}
+	return finalPayload, header, extensions, nil
+}
 
+// renderResources resolves the bindings and template of the Trigger,
+// returning the resolved params and the resources to create.
+func (r Sink) renderResources(ctx context.Context, t *triggersv1.EventListenerTrigger, payload []byte, header http.Header, extensions map[string]interface{}, result *TriggerResult, log *zap.SugaredLogger) ([]triggersv1.Param, []json.RawMessage, error) {
+	_, resolveSpan := tracing.StartSpan(ctx, "ResolveParams")
 	rt, err := template.ResolveTrigger(*t,
 		r.TriggerBindingLister.TriggerBindings(r.EventListenerNamespace).Get,
 		r.ClusterTriggerBindingLister.Get,
 		r.TriggerTemplateLister.TriggerTemplates(r.EventListenerNamespace).Get)
 	if err != nil {
+		tracing.EndSpan(resolveSpan, err)
 		log.Error(err)
-		return err
-	}
-	extensions := map[string]interface{}{}
-	if iresp != nil && iresp.Extensions != nil {
-		extensions = iresp.Extensions
+		result.fail(ResolveTriggerStep, err)
+		return nil, nil, err
 	}
-	params, err := template.ResolveParams(rt, finalPayload, header, extensions)
+	params, err := template.ResolveParams(rt, payload, header, extensions)
+	tracing.EndSpan(resolveSpan, err)
 	if err != nil {
 		log.Error(err)
-		return err
+		result.fail(ResolveParamsStep, err)
+		return nil, nil, err
 	}
 
 	log.Infof("ResolvedParams : %+v", params)
-	resources := template.ResolveResources(rt.TriggerTemplate, params)
-	if err := r.CreateResources(t.ServiceAccountName, resources, t.Name, eventID, log); err != nil {
-		log.Error(err)
-		return err
-	}
-	return nil
+	return params, template.ResolveResources(rt.TriggerTemplate, params), nil
 }
 
 // ExecuteInterceptor executes all interceptors for the Trigger and returns back the body, header, and InterceptorResponse to use.
@@ -224,68 +908,73 @@ func (r Sink) ExecuteInterceptors(t *triggersv1.EventListenerTrigger, in *http.R
 			EventID:  eventID,
 			// t.Name might not be fully accurate until we get rid of triggers inlined within EventListener
 			TriggerID: fmt.Sprintf("namespaces/%s/triggers/%s", r.EventListenerNamespace, t.Name), // TODO: t.Name might be wrong
+			SourceIP:  sourceIP(in.Context()),
 		},
 	}
 
-	var interceptorResponse *triggersv1.InterceptorResponse
 	for _, i := range t.Interceptors {
-		var interceptor interceptors.Interceptor
+		var interceptor triggersv1.InterceptorInterface
+		var interceptorType string
 		switch {
 		case i.Webhook != nil:
-			interceptor = webhook.NewInterceptor(i.Webhook, r.HTTPClient, r.EventListenerNamespace, log)
+			interceptor = webhook.NewInterceptor(r.interceptorClients(), r.KubeClientSet, log)
+			interceptorType = "webhook"
 		case i.GitHub != nil:
-			interceptor = github.NewInterceptor(i.GitHub, r.KubeClientSet, r.EventListenerNamespace, log)
+			interceptor = github.NewInterceptor(r.KubeClientSet, log)
+			interceptorType = "github"
 		case i.GitLab != nil:
-			interceptor = gitlab.NewInterceptor(i.GitLab, r.KubeClientSet, r.EventListenerNamespace, log)
+			interceptor = gitlab.NewInterceptor(r.KubeClientSet, log)
+			interceptorType = "gitlab"
 		case i.CEL != nil:
 			interceptor = cel.NewInterceptor(r.KubeClientSet, log)
+			interceptorType = "cel"
 		case i.Bitbucket != nil:
-			interceptor = bitbucket.NewInterceptor(i.Bitbucket, r.KubeClientSet, r.EventListenerNamespace, log)
+			interceptor = bitbucket.NewInterceptor(r.KubeClientSet, log)
+			interceptorType = "bitbucket"
+		case i.Ref != nil:
+			remote, err := r.remoteInterceptor(i.Ref)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			interceptor = remote
+			interceptorType = i.Ref.Name
 		default:
 			return nil, nil, nil, fmt.Errorf("unknown interceptor type: %v", i)
 		}
+		ctx, span := tracing.StartSpan(in.Context(), "Interceptor")
+		span.SetAttributes(label.String("interceptor", interceptorType))
+		start := time.Now()
 
-		// Immutable Interface check
-		if interceptorInterface, ok := interceptor.(triggersv1.InterceptorInterface); ok {
-			// Set per interceptor config params to the request
-			request.InterceptorParams = interceptors.GetInterceptorParams(i)
-			interceptorResponse = interceptorInterface.Process(context.Background(), &request)
-			if !interceptorResponse.Continue {
-				return nil, nil, interceptorResponse, nil
-			}
-
-			if interceptorResponse.Extensions != nil {
-				// Merge any extensions and pass it on to the next request in the chain
-				for k, v := range interceptorResponse.Extensions {
-					request.Extensions[k] = v
-				}
+		// Set per interceptor config params to the request
+		request.InterceptorParams = interceptors.GetInterceptorParams(i)
+		var interceptorResponse *triggersv1.InterceptorResponse
+		if w, ok := interceptor.(*webhook.Interceptor); ok {
+			// Webhook interceptors replace the body and header of the event
+			// for the rest of the chain
+			var body []byte
+			var header http.Header
+			body, header, interceptorResponse = w.Intercept(ctx, &request)
+			if interceptorResponse.Continue {
+				request.Body, request.Header = body, header
 			}
-			// Clear interceptorParams for the next interceptor in chain
-			request.InterceptorParams = map[string]interface{}{}
 		} else {
-			// Old style interceptor (everything but CEL at the moment)
-			req := &http.Request{
-				Method: http.MethodPost,
-				Header: request.Header,
-				URL:    in.URL,
-				Body:   ioutil.NopCloser(bytes.NewBuffer(request.Body)),
-			}
-
-			res, err := interceptor.ExecuteTrigger(req)
-			if err != nil {
-				return nil, nil, nil, err
-			}
+			interceptorResponse = interceptor.Process(ctx, &request)
+		}
+		metrics.RecordInterceptorLatency(r.EventListenerName, t.Name, interceptorType, time.Since(start))
+		if !interceptorResponse.Continue {
+			tracing.EndSpan(span, interceptorResponse.Status.Err())
+			return nil, nil, interceptorResponse, nil
+		}
+		span.End()
 
-			payload, err := ioutil.ReadAll(res.Body)
-			if err != nil {
-				return nil, nil, nil, fmt.Errorf("error reading webhook interceptor response body: %w", err)
+		if interceptorResponse.Extensions != nil {
+			// Merge any extensions and pass it on to the next request in the chain
+			for k, v := range interceptorResponse.Extensions {
+				request.Extensions[k] = v
 			}
-			defer res.Body.Close()
-			// Set the next request to be the output of the last response to enable
-			// request chaining.
-			request.Header = res.Header.Clone()
-			request.Body = payload
 		}
+		// Clear interceptorParams for the next interceptor in chain
+		request.InterceptorParams = map[string]interface{}{}
 	}
 	return request.Body, request.Header, &triggersv1.InterceptorResponse{
 		Continue:   true,
@@ -293,7 +982,40 @@ func (r Sink) ExecuteInterceptors(t *triggersv1.EventListenerTrigger, in *http.R
 	}, nil
 }
 
-func (r Sink) CreateResources(sa string, res []json.RawMessage, triggerName, eventID string, log *zap.SugaredLogger) error {
+// interceptorClients returns the clients reaching interceptors over HTTP.
+func (r Sink) interceptorClients() *interceptors.Clients {
+	if r.InterceptorClients != nil {
+		return r.InterceptorClients
+	}
+	return interceptors.NewClients(r.HTTPClient)
+}
+
+// remoteInterceptor returns the interceptor sending events to the
+// ClusterInterceptor of ref.
+func (r Sink) remoteInterceptor(ref *triggersv1.InterceptorRef) (*interceptors.Remote, error) {
+	ci, err := r.ClusterInterceptorLister.Get(ref.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ClusterInterceptor %s: %w", ref.Name, err)
+	}
+	u, err := ci.ResolveURL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the URL of ClusterInterceptor %s: %w", ref.Name, err)
+	}
+	client, err := r.interceptorClients().Get(ci.Spec.ClientConfig.CaBundle)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the client of ClusterInterceptor %s: %w", ref.Name, err)
+	}
+	remote := interceptors.NewRemote(client, u)
+	remote.TokenFile = r.InterceptorTokenFile
+	return remote, nil
+}
+
+// CreateResources creates the given resources for the Trigger, returning
+// those created before any error. Creating each of them is retried under the
+// retry policy, which may be nil for the default policy. If dryRun is true,
+// the resources are created with a server-side dry run and logged instead of
+// being persisted.
+func (r Sink) CreateResources(ctx context.Context, sa string, res []json.RawMessage, triggerName, eventID string, retry *triggersv1.RetryPolicy, dryRun bool, log *zap.SugaredLogger) ([]*unstructured.Unstructured, error) {
 	discoveryClient := r.DiscoveryClient
 	dynamicClient := r.DynamicClient
 	var err error
@@ -306,15 +1028,23 @@ func (r Sink) CreateResources(sa string, res []json.RawMessage, triggerName, eve
 		discoveryClient, dynamicClient, err = r.Auth.OverrideAuthentication(sa, r.EventListenerNamespace, log, r.DiscoveryClient, r.DynamicClient)
 		if err != nil {
 			log.Errorf("problem cloning rest config: %#v", err)
-			return err
+			return nil, err
 		}
 	}
 
+	created := make([]*unstructured.Unstructured, 0, len(res))
 	for _, rr := range res {
-		if err := resources.Create(r.Logger, rr, triggerName, eventID, r.EventListenerName, r.EventListenerNamespace, discoveryClient, dynamicClient); err != nil {
+		c, err := resources.Create(ctx, r.Logger, rr, triggerName, eventID, r.EventListenerName, r.EventListenerNamespace, discoveryClient, dynamicClient, resources.RetryFor(retry), dryRun)
+		if err != nil {
 			log.Errorf("problem creating obj: %#v", err)
-			return err
+			return created, err
+		}
+		if dryRun {
+			if b, err := c.MarshalJSON(); err == nil {
+				log.Infof("Dry run of Trigger %s would create: %s", triggerName, b)
+			}
 		}
+		created = append(created, c)
 	}
-	return nil
+	return created, nil
 }
@@ -18,9 +18,12 @@ package sink
 
 import (
 	"bytes"
+	"compress/gzip"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -28,7 +31,10 @@ import (
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
+	"go.opentelemetry.io/otel/api/global"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"go.uber.org/zap/zaptest"
@@ -47,6 +53,7 @@ import (
 	triggersv1 "github.com/tektoncd/triggers/pkg/apis/triggers/v1alpha1"
 	dynamicclientset "github.com/tektoncd/triggers/pkg/client/dynamic/clientset"
 	"github.com/tektoncd/triggers/pkg/client/dynamic/clientset/tekton"
+	clusterinterceptorinformer "github.com/tektoncd/triggers/pkg/client/injection/informers/triggers/v1alpha1/clusterinterceptor"
 	clustertriggerbindinginformer "github.com/tektoncd/triggers/pkg/client/injection/informers/triggers/v1alpha1/clustertriggerbinding"
 	eventlistenerinformer "github.com/tektoncd/triggers/pkg/client/injection/informers/triggers/v1alpha1/eventlistener"
 	triggerinformer "github.com/tektoncd/triggers/pkg/client/injection/informers/triggers/v1alpha1/trigger"
@@ -55,6 +62,7 @@ import (
 	"github.com/tektoncd/triggers/pkg/template"
 	"github.com/tektoncd/triggers/test"
 	bldr "github.com/tektoncd/triggers/test/builder"
+	"google.golang.org/grpc/codes"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -62,8 +70,10 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
 	fakedynamic "k8s.io/client-go/dynamic/fake"
 	ktesting "k8s.io/client-go/testing"
+	"knative.dev/pkg/apis"
 	rtesting "knative.dev/pkg/reconciler/testing"
 )
 
@@ -114,6 +124,7 @@ func getSinkAssets(t *testing.T, resources test.Resources, elName string, auth A
 		TriggerBindingLister:        triggerbindinginformer.Get(ctx).Lister(),
 		ClusterTriggerBindingLister: clustertriggerbindinginformer.Get(ctx).Lister(),
 		TriggerTemplateLister:       triggertemplateinformer.Get(ctx).Lister(),
+		ClusterInterceptorLister:    clusterinterceptorinformer.Get(ctx).Lister(),
 	}
 	return r, dynamicClient
 }
@@ -151,7 +162,7 @@ func checkSinkResponse(t *testing.T, resp *http.Response, elName string) {
 		Namespace:     namespace,
 		EventID:       eventID,
 	}
-	if diff := cmp.Diff(wantBody, gotBody); diff != "" {
+	if diff := cmp.Diff(wantBody, gotBody, cmpopts.IgnoreFields(Response{}, "Triggers")); diff != "" {
 		t.Errorf("did not get expected response back -want,+got: %s", diff)
 	}
 }
@@ -896,8 +907,13 @@ func TestExecuteInterceptor_error(t *testing.T) {
 	if err != nil {
 		t.Fatalf("http.NewRequest: %v", err)
 	}
-	if resp, _, _, err := s.ExecuteInterceptors(trigger, req, nil, logger.Sugar(), eventID); err == nil {
-		t.Errorf("expected error, got: %+v, %v", string(resp), err)
+	_, _, resp, err := s.ExecuteInterceptors(trigger, req, nil, logger.Sugar(), eventID)
+	if err != nil {
+		t.Fatalf("ExecuteInterceptors() unexpected error: %v", err)
+	}
+	// The interceptor responding with a 5xx status code is unavailable
+	if resp == nil || resp.Continue || resp.Status.Code() != codes.Unavailable {
+		t.Errorf("ExecuteInterceptors() = %+v, want a status with code %s", resp, codes.Unavailable)
 	}
 
 	if si.called {
@@ -940,6 +956,168 @@ func TestExecuteInterceptor_NotContinue(t *testing.T) {
 	}
 }
 
+func TestHandleEvent_InterceptorStatus(t *testing.T) {
+	rejecting := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unknown repository", http.StatusBadRequest)
+	}))
+	defer rejecting.Close()
+	unavailable := httptest.NewServer(http.NotFoundHandler())
+	unavailable.Close()
+
+	tests := []struct {
+		name           string
+		server         *httptest.Server
+		wantCode       codes.Code
+		wantFiltered   bool
+		wantFailedStep string
+	}{{
+		name:         "rejected",
+		server:       rejecting,
+		wantCode:     codes.FailedPrecondition,
+		wantFiltered: true,
+	}, {
+		name:           "unavailable",
+		server:         unavailable,
+		wantCode:       codes.Unavailable,
+		wantFailedStep: InterceptorsStep,
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tb, tt := getResources(t, "$(body.repository.url)")
+			el := bldr.EventListener("el", namespace, bldr.EventListenerSpec(
+				bldr.EventListenerTrigger("tt", "v1alpha1",
+					bldr.EventListenerTriggerBinding("tb", "TriggerBinding", "v1alpha1"),
+					bldr.EventListenerTriggerInterceptor("foo", "v1", "Service", ""),
+				),
+			))
+			resources := test.Resources{
+				TriggerBindings:  []*triggersv1.TriggerBinding{tb},
+				TriggerTemplates: []*triggersv1.TriggerTemplate{tt},
+				EventListeners:   []*triggersv1.EventListener{el},
+			}
+			sink, dynamicClient := getSinkAssets(t, resources, el.Name, DefaultAuthOverride{})
+			u, _ := url.Parse(tc.server.URL)
+			// Redirect all requests to the interceptor's server.
+			sink.HTTPClient = &http.Client{Transport: &http.Transport{Proxy: http.ProxyURL(u)}}
+
+			rec := httptest.NewRecorder()
+			sink.HandleEvent(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"repository": {"url": "testurl"}}`)))
+			var resp Response
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("Error decoding the response: %s", err)
+			}
+			if len(resp.Triggers) != 1 {
+				t.Fatalf("HandleEvent() triggers = %+v, want 1", resp.Triggers)
+			}
+			got := resp.Triggers[0]
+			if got.Status == nil || got.Status.Code != tc.wantCode.String() || got.Filtered != tc.wantFiltered || got.FailedStep != tc.wantFailedStep {
+				t.Errorf("HandleEvent() trigger result = %+v, want status %s, filtered %t and failed step %q", got, tc.wantCode, tc.wantFiltered, tc.wantFailedStep)
+			}
+			if len(dynamicClient.Actions()) != 0 {
+				t.Errorf("HandleEvent() created resources for an event that was not intercepted: %v", dynamicClient.Actions())
+			}
+		})
+	}
+}
+
+func TestHandleEvent_ClusterInterceptor(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req triggersv1.InterceptorRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if req.InterceptorParams["allow"] != true {
+			_, _ = w.Write([]byte(`{"status": {"code": 7, "message": "repository not allowed"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"continue": true, "extensions": {"scanner": {"url": "scanned"}}}`))
+	}))
+	defer srv.Close()
+	ci := &triggersv1.ClusterInterceptor{
+		ObjectMeta: metav1.ObjectMeta{Name: "scanner"},
+		Spec: triggersv1.ClusterInterceptorSpec{
+			ClientConfig: triggersv1.ClientConfig{
+				URL: apis.HTTP(strings.TrimPrefix(srv.URL, "http://")),
+			},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		interceptor    string
+		allow          string
+		wantURL        string
+		wantCode       string
+		wantFailedStep string
+	}{{
+		name:        "allowed",
+		interceptor: "scanner",
+		allow:       "true",
+		wantURL:     "scanned",
+	}, {
+		name:        "rejected",
+		interceptor: "scanner",
+		allow:       "false",
+		wantCode:    codes.PermissionDenied.String(),
+	}, {
+		name:           "missing ClusterInterceptor",
+		interceptor:    "missing",
+		allow:          "true",
+		wantFailedStep: InterceptorsStep,
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tb, tt := getResources(t, "$(extensions.scanner.url)")
+			el := bldr.EventListener("el", namespace, bldr.EventListenerSpec(
+				bldr.EventListenerTrigger("tt", "v1alpha1",
+					bldr.EventListenerTriggerBinding("tb", "TriggerBinding", "v1alpha1"),
+					bldr.EventListenerInterceptorRef(tc.interceptor,
+						bldr.EventListenerInterceptorRefParam("allow", tc.allow),
+					),
+				),
+			))
+			resources := test.Resources{
+				ClusterInterceptors: []*triggersv1.ClusterInterceptor{ci},
+				TriggerBindings:     []*triggersv1.TriggerBinding{tb},
+				TriggerTemplates:    []*triggersv1.TriggerTemplate{tt},
+				EventListeners:      []*triggersv1.EventListener{el},
+			}
+			sink, dynamicClient := getSinkAssets(t, resources, el.Name, DefaultAuthOverride{})
+			sink.HTTPClient = srv.Client()
+
+			rec := httptest.NewRecorder()
+			sink.HandleEvent(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"repository": {"url": "testurl"}}`)))
+			var resp Response
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("Error decoding the response: %s", err)
+			}
+			if len(resp.Triggers) != 1 {
+				t.Fatalf("HandleEvent() triggers = %+v, want 1", resp.Triggers)
+			}
+			got := resp.Triggers[0]
+			if got.FailedStep != tc.wantFailedStep {
+				t.Errorf("HandleEvent() failed step = %q, want %q", got.FailedStep, tc.wantFailedStep)
+			}
+			if tc.wantCode != "" && (got.Status == nil || got.Status.Code != tc.wantCode || !got.Filtered) {
+				t.Errorf("HandleEvent() trigger result = %+v, want filtered with status %s", got, tc.wantCode)
+			}
+
+			var urls []string
+			for _, pr := range getCreatedPipelineResources(t, dynamicClient.Actions()) {
+				urls = append(urls, pr.Spec.Params[0].Value)
+			}
+			var wantURLs []string
+			if tc.wantURL != "" {
+				wantURLs = []string{tc.wantURL}
+			}
+			if diff := cmp.Diff(wantURLs, urls); diff != "" {
+				t.Errorf("HandleEvent() created resources for urls (-want +got): %s", diff)
+			}
+		})
+	}
+}
+
 const userWithPermissions = "user-with-permissions"
 const userWithoutPermissions = "user-with-no-permissions"
 const userWithForbiddenAccess = "user-forbidden"
@@ -1336,3 +1514,1230 @@ func makeCapturingLogger(t *testing.T) (*observer.ObservedLogs, *zap.SugaredLogg
 	l := zaptest.NewLogger(t, zaptest.WrapOptions(zap.WrapCore(func(zapcore.Core) zapcore.Core { return core }))).Sugar()
 	return logs, l
 }
+
+func TestHandleEvent_Async(t *testing.T) {
+	eventBody := json.RawMessage(`{"head_commit": {"id": "testrevision"}, "repository": {"url": "testurl"}}`)
+	tb, tt := getResources(t, "$(body.repository.url)")
+	el := bldr.EventListener("el", namespace, bldr.EventListenerSpec(
+		bldr.EventListenerTrigger("tt", "v1alpha1",
+			bldr.EventListenerTriggerBinding("tb", "TriggerBinding", "v1alpha1"),
+		),
+		bldr.EventListenerProcessingMode(triggersv1.AsyncProcessingMode)))
+
+	resources := test.Resources{
+		TriggerBindings:  []*triggersv1.TriggerBinding{tb},
+		TriggerTemplates: []*triggersv1.TriggerTemplate{tt},
+		EventListeners:   []*triggersv1.EventListener{el},
+	}
+	sink, dynamicClient := getSinkAssets(t, resources, el.Name, DefaultAuthOverride{})
+	sink.EventQueue = NewEventQueue(10, 1, 0)
+	sink.StartWorkers()
+
+	ts := httptest.NewServer(http.HandlerFunc(sink.HandleEvent))
+	defer ts.Close()
+
+	resp, err := http.Post(ts.URL, "application/json", bytes.NewReader(eventBody))
+	if err != nil {
+		t.Fatalf("Error creating Post request: %s", err)
+	}
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("expected response code 202 but got: %v", resp.Status)
+	}
+	var gotBody Response
+	if err := json.NewDecoder(resp.Body).Decode(&gotBody); err != nil {
+		t.Fatalf("Error reading response body: %s", err)
+	}
+	if gotBody.EventID != eventID {
+		t.Errorf("EventID in response: want %s, got %s", eventID, gotBody.EventID)
+	}
+
+	if err := sink.EventQueue.Drain(wait.ForeverTestTimeout); err != nil {
+		t.Fatalf("Drain() unexpected error: %v", err)
+	}
+	gotPrs := getCreatedPipelineResources(t, dynamicClient.Actions())
+	if len(gotPrs) != 1 || gotPrs[0].Name != "my-pipelineresource" {
+		t.Errorf("expected my-pipelineresource to be created, got: %+v", gotPrs)
+	}
+}
+
+func TestHandleEvent_AsyncRetryResumes(t *testing.T) {
+	tb, _ := getResources(t, "$(body.repository.url)")
+	templates := []bldr.TriggerTemplateSpecOp{bldr.TriggerTemplateParam("url", "", "")}
+	for _, name := range []string{"first", "second"} {
+		pipelineResource := pipelinev1alpha1.PipelineResource{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: "tekton.dev/v1alpha1",
+				Kind:       "PipelineResource",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+			},
+			Spec: pipelinev1alpha1.PipelineResourceSpec{
+				Type: pipelinev1.PipelineResourceTypeGit,
+				Params: []pipelinev1.ResourceParam{{
+					Name:  "url",
+					Value: "$(tt.params.url)",
+				}},
+			},
+		}
+		b, err := json.Marshal(pipelineResource)
+		if err != nil {
+			t.Fatalf("Error marshalling pipelineResource: %s", err)
+		}
+		templates = append(templates, bldr.TriggerResourceTemplate(runtime.RawExtension{Raw: b}))
+	}
+	tt := bldr.TriggerTemplate("tt", namespace, bldr.TriggerTemplateSpec(templates...))
+	el := bldr.EventListener("el", namespace, bldr.EventListenerSpec(
+		bldr.EventListenerTrigger("tt", "v1alpha1",
+			bldr.EventListenerTriggerBinding("tb", "TriggerBinding", "v1alpha1"),
+		),
+		bldr.EventListenerProcessingMode(triggersv1.AsyncProcessingMode)))
+	resources := test.Resources{
+		TriggerBindings:  []*triggersv1.TriggerBinding{tb},
+		TriggerTemplates: []*triggersv1.TriggerTemplate{tt},
+		EventListeners:   []*triggersv1.EventListener{el},
+	}
+	sink, dynamicClient := getSinkAssets(t, resources, el.Name, DefaultAuthOverride{})
+	var mu sync.Mutex
+	creates := map[string]int{}
+	dynamicClient.PrependReactor("create", "*", func(action ktesting.Action) (bool, runtime.Object, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		name := action.(ktesting.CreateAction).GetObject().(*unstructured.Unstructured).GetName()
+		creates[name]++
+		// The second resource fails to be created on the first attempt
+		if name == "second" && creates[name] == 1 {
+			return true, nil, kerrors.NewServiceUnavailable("unavailable")
+		}
+		return false, nil, nil
+	})
+	sink.EventQueue = NewEventQueue(10, 1, 2)
+	sink.StartWorkers()
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"repository": {"url": "testurl"}}`))
+	rec := httptest.NewRecorder()
+	sink.HandleEvent(rec, req)
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("expected response code %d but got: %d", http.StatusAccepted, rec.Code)
+	}
+	if err := wait.PollImmediate(10*time.Millisecond, wait.ForeverTestTimeout, func() (bool, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		return creates["second"] == 2, nil
+	}); err != nil {
+		t.Fatalf("the failed resource was not retried: %v", err)
+	}
+	if err := sink.EventQueue.Drain(wait.ForeverTestTimeout); err != nil {
+		t.Fatalf("Drain() unexpected error: %v", err)
+	}
+	// The retry resumes from the resource that failed
+	if diff := cmp.Diff(map[string]int{"first": 1, "second": 2}, creates); diff != "" {
+		t.Errorf("attempts at creating each resource (-want +got): %s", diff)
+	}
+}
+
+func TestHandleEvent_AsyncQueueFull(t *testing.T) {
+	eventBody := json.RawMessage(`{"repository": {"url": "testurl"}}`)
+	tb, tt := getResources(t, "$(body.repository.url)")
+	el := bldr.EventListener("el", namespace, bldr.EventListenerSpec(
+		bldr.EventListenerTrigger("tt", "v1alpha1",
+			bldr.EventListenerTriggerBinding("tb", "TriggerBinding", "v1alpha1"),
+		),
+		bldr.EventListenerTrigger("tt", "v1alpha1",
+			bldr.EventListenerTriggerBinding("tb", "TriggerBinding", "v1alpha1"),
+		),
+		bldr.EventListenerProcessingMode(triggersv1.AsyncProcessingMode)))
+
+	resources := test.Resources{
+		TriggerBindings:  []*triggersv1.TriggerBinding{tb},
+		TriggerTemplates: []*triggersv1.TriggerTemplate{tt},
+		EventListeners:   []*triggersv1.EventListener{el},
+	}
+	sink, dynamicClient := getSinkAssets(t, resources, el.Name, DefaultAuthOverride{})
+	// Room for only one of the two triggers; the workers are never started.
+	sink.EventQueue = NewEventQueue(1, 1, 0)
+
+	ts := httptest.NewServer(http.HandlerFunc(sink.HandleEvent))
+	defer ts.Close()
+
+	resp, err := http.Post(ts.URL, "application/json", bytes.NewReader(eventBody))
+	if err != nil {
+		t.Fatalf("Error creating Post request: %s", err)
+	}
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("expected response code 503 but got: %v", resp.Status)
+	}
+	if actions := dynamicClient.Actions(); len(actions) != 0 {
+		t.Errorf("expected no resources to be created, got: %v", actions)
+	}
+}
+
+func TestHandleEvent_TriggerResults(t *testing.T) {
+	eventBody := json.RawMessage(`{"repository": {"url": "testurl"}}`)
+	tb, tt := getResources(t, "$(body.repository.url)")
+	tbMissing := bldr.TriggerBinding("tb-missing", namespace,
+		bldr.TriggerBindingSpec(
+			bldr.TriggerBindingParam("revision", "$(body.missing.revision)"),
+		))
+	el := bldr.EventListener("el", namespace, bldr.EventListenerSpec(
+		bldr.EventListenerTrigger("tt", "v1alpha1",
+			bldr.EventListenerTriggerName("created"),
+			bldr.EventListenerTriggerBinding("tb", "TriggerBinding", "v1alpha1"),
+		),
+		bldr.EventListenerTrigger("tt", "v1alpha1",
+			bldr.EventListenerTriggerName("filtered"),
+			bldr.EventListenerTriggerBinding("tb", "TriggerBinding", "v1alpha1"),
+			bldr.EventListenerCELInterceptor("body.repository.url == 'other'"),
+		),
+		bldr.EventListenerTrigger("tt", "v1alpha1",
+			bldr.EventListenerTriggerName("bad-params"),
+			bldr.EventListenerTriggerBinding("tb-missing", "TriggerBinding", "v1alpha1"),
+		),
+		bldr.EventListenerTriggerRef("unknown"),
+	))
+
+	resources := test.Resources{
+		TriggerBindings:  []*triggersv1.TriggerBinding{tb, tbMissing},
+		TriggerTemplates: []*triggersv1.TriggerTemplate{tt},
+		EventListeners:   []*triggersv1.EventListener{el},
+	}
+	sink, _ := getSinkAssets(t, resources, el.Name, DefaultAuthOverride{})
+	ts := httptest.NewServer(http.HandlerFunc(sink.HandleEvent))
+	defer ts.Close()
+
+	resp, err := http.Post(ts.URL, "application/json", bytes.NewReader(eventBody))
+	if err != nil {
+		t.Fatalf("Error creating Post request: %s", err)
+	}
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected response code 201 but got: %v", resp.Status)
+	}
+	var gotBody Response
+	if err := json.NewDecoder(resp.Body).Decode(&gotBody); err != nil {
+		t.Fatalf("Error reading response body: %s", err)
+	}
+
+	want := []TriggerResult{{
+		Name: "created",
+		Resources: []CreatedResource{{
+			APIVersion: "tekton.dev/v1alpha1",
+			Kind:       "PipelineResource",
+			Namespace:  namespace,
+			Name:       "my-pipelineresource",
+		}},
+	}, {
+		Name:     "filtered",
+		Filtered: true,
+		Status: &InterceptorStatus{
+			Code:    "FailedPrecondition",
+			Message: "expression body.repository.url == 'other' did not return true",
+		},
+	}, {
+		Name:       "bad-params",
+		FailedStep: ResolveParamsStep,
+	}, {
+		Name:       "unknown",
+		FailedStep: ResolveTriggerStep,
+		Error:      `trigger.triggers.tekton.dev "unknown" not found`,
+	}}
+	// The error resolving params depends on the JSONPath library.
+	if diff := cmp.Diff(want, gotBody.Triggers, cmpopts.IgnoreFields(TriggerResult{}, "Error")); diff != "" {
+		t.Errorf("did not get expected trigger results -want,+got: %s", diff)
+	}
+	if gotBody.Triggers[2].Error == "" || gotBody.Triggers[3].Error != want[3].Error {
+		t.Errorf("did not get expected trigger errors: %+v", gotBody.Triggers)
+	}
+}
+
+func TestHandleEvent_Deduplication(t *testing.T) {
+	eventBody := json.RawMessage(`{"repository": {"url": "testurl"}, "delivery": "abc"}`)
+	tb, tt := getResources(t, "$(body.repository.url)")
+	tests := []struct {
+		name  string
+		dedup triggersv1.Deduplication
+	}{{
+		name:  "header",
+		dedup: triggersv1.Deduplication{Header: "X-GitHub-Delivery"},
+	}, {
+		name:  "expression",
+		dedup: triggersv1.Deduplication{Expression: "body.delivery + '-' + header.canonical('X-GitHub-Delivery')"},
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			el := bldr.EventListener("el", namespace, bldr.EventListenerSpec(
+				bldr.EventListenerTrigger("tt", "v1alpha1",
+					bldr.EventListenerTriggerBinding("tb", "TriggerBinding", "v1alpha1"),
+				),
+				bldr.EventListenerDeduplication(tc.dedup)))
+			resources := test.Resources{
+				TriggerBindings:  []*triggersv1.TriggerBinding{tb},
+				TriggerTemplates: []*triggersv1.TriggerTemplate{tt},
+				EventListeners:   []*triggersv1.EventListener{el},
+			}
+			sink, dynamicClient := getSinkAssets(t, resources, el.Name, DefaultAuthOverride{})
+			sink.DeliveryStore = NewMemoryDeliveryStore()
+			ts := httptest.NewServer(http.HandlerFunc(sink.HandleEvent))
+			defer ts.Close()
+
+			post := func() *http.Response {
+				t.Helper()
+				req, err := http.NewRequest(http.MethodPost, ts.URL, bytes.NewReader(eventBody))
+				if err != nil {
+					t.Fatalf("Error creating Post request: %s", err)
+				}
+				req.Header.Set("X-GitHub-Delivery", "72d3162e")
+				resp, err := http.DefaultClient.Do(req)
+				if err != nil {
+					t.Fatalf("Error sending Post request: %s", err)
+				}
+				return resp
+			}
+
+			checkSinkResponse(t, post(), el.Name)
+
+			resp := post()
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("expected response code 200 for the duplicate but got: %v", resp.Status)
+			}
+			var gotBody Response
+			if err := json.NewDecoder(resp.Body).Decode(&gotBody); err != nil {
+				t.Fatalf("Error reading response body: %s", err)
+			}
+			wantBody := Response{
+				EventListener: el.Name,
+				Namespace:     namespace,
+				EventID:       eventID,
+				Duplicate:     true,
+			}
+			if diff := cmp.Diff(wantBody, gotBody); diff != "" {
+				t.Errorf("did not get expected response back -want,+got: %s", diff)
+			}
+			if prs := getCreatedPipelineResources(t, dynamicClient.Actions()); len(prs) != 1 {
+				t.Errorf("expected 1 resource to be created, got: %d", len(prs))
+			}
+		})
+	}
+}
+
+func TestHandleEvent_DeduplicationReleasedOnFailure(t *testing.T) {
+	tb, tt := getResources(t, "$(body.repository.url)")
+	el := bldr.EventListener("el", namespace, bldr.EventListenerSpec(
+		bldr.EventListenerTrigger("tt", "v1alpha1",
+			bldr.EventListenerTriggerBinding("tb", "TriggerBinding", "v1alpha1"),
+			bldr.EventListenerCELInterceptor("body.ready"),
+		),
+		bldr.EventListenerDeduplication(triggersv1.Deduplication{Header: "X-GitHub-Delivery"})))
+	resources := test.Resources{
+		TriggerBindings:  []*triggersv1.TriggerBinding{tb},
+		TriggerTemplates: []*triggersv1.TriggerTemplate{tt},
+		EventListeners:   []*triggersv1.EventListener{el},
+	}
+	sink, _ := getSinkAssets(t, resources, el.Name, DefaultAuthOverride{})
+	sink.DeliveryStore = NewMemoryDeliveryStore()
+	ts := httptest.NewServer(http.HandlerFunc(sink.HandleEvent))
+	defer ts.Close()
+
+	post := func(body string) *http.Response {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodPost, ts.URL, strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("Error creating Post request: %s", err)
+		}
+		req.Header.Set("X-GitHub-Delivery", "72d3162e")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Error sending Post request: %s", err)
+		}
+		return resp
+	}
+
+	if resp := post(`{"ready": false, "repository": {"url": "testurl"}}`); resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("expected response code 202 but got: %v", resp.Status)
+	}
+	// Nothing was created, so the redelivery is processed again.
+	checkSinkResponse(t, post(`{"ready": true, "repository": {"url": "testurl"}}`), el.Name)
+}
+
+func TestHandleEvent_AsyncDeduplicationReleasedOnFailure(t *testing.T) {
+	tb, tt := getResources(t, "$(body.repository.url)")
+	el := bldr.EventListener("el", namespace, bldr.EventListenerSpec(
+		bldr.EventListenerTrigger("tt", "v1alpha1",
+			bldr.EventListenerTriggerBinding("tb", "TriggerBinding", "v1alpha1"),
+			bldr.EventListenerCELInterceptor("body.ready"),
+		),
+		bldr.EventListenerProcessingMode(triggersv1.AsyncProcessingMode),
+		bldr.EventListenerDeduplication(triggersv1.Deduplication{Header: "X-GitHub-Delivery"})))
+	resources := test.Resources{
+		TriggerBindings:  []*triggersv1.TriggerBinding{tb},
+		TriggerTemplates: []*triggersv1.TriggerTemplate{tt},
+		EventListeners:   []*triggersv1.EventListener{el},
+	}
+	sink, dynamicClient := getSinkAssets(t, resources, el.Name, DefaultAuthOverride{})
+	sink.DeliveryStore = NewMemoryDeliveryStore()
+	sink.EventQueue = NewEventQueue(10, 1, 0)
+	sink.StartWorkers()
+	ts := httptest.NewServer(http.HandlerFunc(sink.HandleEvent))
+	defer ts.Close()
+
+	post := func(body string) Response {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodPost, ts.URL, strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("Error creating Post request: %s", err)
+		}
+		req.Header.Set("X-GitHub-Delivery", "72d3162e")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Error sending Post request: %s", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusAccepted {
+			t.Fatalf("expected response code 202 but got: %v", resp.Status)
+		}
+		var r Response
+		if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+			t.Fatalf("Error reading response body: %s", err)
+		}
+		return r
+	}
+
+	post(`{"ready": false, "repository": {"url": "testurl"}}`)
+	if err := wait.PollImmediate(10*time.Millisecond, wait.ForeverTestTimeout, func() (bool, error) {
+		sink.EventQueue.mu.Lock()
+		defer sink.EventQueue.mu.Unlock()
+		return sink.EventQueue.pending == 0, nil
+	}); err != nil {
+		t.Fatalf("The queued Trigger was not processed: %v", err)
+	}
+	// Nothing was created, so the redelivery is processed again.
+	if r := post(`{"ready": true, "repository": {"url": "testurl"}}`); r.Duplicate {
+		t.Errorf("The redelivery of an event that created nothing is a duplicate: %+v", r)
+	}
+	if err := sink.EventQueue.Drain(wait.ForeverTestTimeout); err != nil {
+		t.Fatalf("Drain() unexpected error: %v", err)
+	}
+	if gotPrs := getCreatedPipelineResources(t, dynamicClient.Actions()); len(gotPrs) != 1 {
+		t.Errorf("expected the redelivery to create my-pipelineresource, got: %+v", gotPrs)
+	}
+}
+
+func TestHandleEvent_Body(t *testing.T) {
+	eventBody := []byte(`{"repository": {"url": "testurl"}}`)
+	var gzipped bytes.Buffer
+	gz := gzip.NewWriter(&gzipped)
+	if _, err := gz.Write(eventBody); err != nil {
+		t.Fatalf("Error compressing body: %s", err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatalf("Error compressing body: %s", err)
+	}
+
+	tests := []struct {
+		name        string
+		body        []byte
+		header      http.Header
+		maxBodySize int64
+		wantCode    int
+	}{{
+		name:        "body within limit",
+		body:        eventBody,
+		maxBodySize: int64(len(eventBody)),
+		wantCode:    http.StatusCreated,
+	}, {
+		name:        "body too large",
+		body:        eventBody,
+		maxBodySize: int64(len(eventBody)) - 1,
+		wantCode:    http.StatusRequestEntityTooLarge,
+	}, {
+		name:     "gzip encoded body",
+		body:     gzipped.Bytes(),
+		header:   http.Header{"Content-Encoding": []string{"gzip"}},
+		wantCode: http.StatusCreated,
+	}, {
+		name:        "decompressed body too large",
+		body:        gzipped.Bytes(),
+		header:      http.Header{"Content-Encoding": []string{"gzip"}},
+		maxBodySize: int64(len(eventBody)) - 1,
+		wantCode:    http.StatusRequestEntityTooLarge,
+	}, {
+		name:     "invalid gzip encoded body",
+		body:     eventBody,
+		header:   http.Header{"Content-Encoding": []string{"gzip"}},
+		wantCode: http.StatusBadRequest,
+	}, {
+		name:     "form encoded body",
+		body:     []byte(`payload=` + url.QueryEscape(string(eventBody))),
+		header:   http.Header{"Content-Type": []string{"application/x-www-form-urlencoded"}},
+		wantCode: http.StatusCreated,
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tb, tt := getResources(t, "$(body.repository.url)")
+			el := bldr.EventListener("el", namespace, bldr.EventListenerSpec(
+				bldr.EventListenerTrigger("tt", "v1alpha1",
+					bldr.EventListenerTriggerBinding("tb", "TriggerBinding", "v1alpha1"),
+				)))
+			resources := test.Resources{
+				TriggerBindings:  []*triggersv1.TriggerBinding{tb},
+				TriggerTemplates: []*triggersv1.TriggerTemplate{tt},
+				EventListeners:   []*triggersv1.EventListener{el},
+			}
+			sink, dynamicClient := getSinkAssets(t, resources, el.Name, DefaultAuthOverride{})
+			sink.MaxBodySize = tc.maxBodySize
+			ts := httptest.NewServer(http.HandlerFunc(sink.HandleEvent))
+			defer ts.Close()
+
+			req, err := http.NewRequest(http.MethodPost, ts.URL, bytes.NewReader(tc.body))
+			if err != nil {
+				t.Fatalf("Error creating Post request: %s", err)
+			}
+			for k, v := range tc.header {
+				req.Header[k] = v
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("Error sending Post request: %s", err)
+			}
+			if resp.StatusCode != tc.wantCode {
+				t.Fatalf("expected response code %d but got: %v", tc.wantCode, resp.Status)
+			}
+			if tc.wantCode != http.StatusCreated {
+				return
+			}
+			prs := getCreatedPipelineResources(t, dynamicClient.Actions())
+			if len(prs) != 1 || prs[0].Spec.Params[0].Value != "testurl" {
+				t.Errorf("expected a resource with the url from the body to be created, got: %+v", prs)
+			}
+		})
+	}
+}
+
+func TestHandleEvent_CloudEvent(t *testing.T) {
+	tests := []struct {
+		name        string
+		reply       bool
+		header      http.Header
+		body        string
+		wantHeaders http.Header
+	}{{
+		name: "binary content mode",
+		header: http.Header{
+			"Content-Type":   []string{"application/json"},
+			"Ce-Specversion": []string{"1.0"},
+			"Ce-Type":        []string{"dev.knative.example"},
+			"Ce-Source":      []string{"/example"},
+			"Ce-Id":          []string{"1234"},
+		},
+		body: `{"repository": {"url": "testurl"}}`,
+	}, {
+		name: "structured content mode",
+		header: http.Header{
+			"Content-Type": []string{"application/cloudevents+json"},
+		},
+		body: `{"specversion": "1.0", "type": "dev.knative.example", "source": "/example", "id": "1234", "data": {"repository": {"url": "testurl"}}}`,
+	}, {
+		name:  "cloudevent reply",
+		reply: true,
+		header: http.Header{
+			"Content-Type": []string{"application/cloudevents+json"},
+		},
+		body: `{"specversion": "1.0", "type": "dev.knative.example", "source": "/example", "id": "1234", "data": {"repository": {"url": "testurl"}}}`,
+		wantHeaders: http.Header{
+			"Ce-Specversion": []string{"1.0"},
+			"Ce-Type":        []string{ResponseCloudEventType},
+			"Ce-Source":      []string{"/apis/triggers.tekton.dev/v1alpha1/namespaces/foo/eventlisteners/el"},
+			"Ce-Id":          []string{eventID},
+		},
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tb, tt := getResources(t, "$(context.source)/$(context.type)/$(body.repository.url)")
+			ops := []bldr.EventListenerSpecOp{
+				bldr.EventListenerTrigger("tt", "v1alpha1",
+					bldr.EventListenerTriggerBinding("tb", "TriggerBinding", "v1alpha1"),
+					bldr.EventListenerCELInterceptor("context.id == '1234'"),
+				),
+			}
+			if tc.reply {
+				ops = append(ops, bldr.EventListenerCloudEventReply())
+			}
+			el := bldr.EventListener("el", namespace, bldr.EventListenerSpec(ops...))
+			resources := test.Resources{
+				TriggerBindings:  []*triggersv1.TriggerBinding{tb},
+				TriggerTemplates: []*triggersv1.TriggerTemplate{tt},
+				EventListeners:   []*triggersv1.EventListener{el},
+			}
+			sink, dynamicClient := getSinkAssets(t, resources, el.Name, DefaultAuthOverride{})
+			ts := httptest.NewServer(http.HandlerFunc(sink.HandleEvent))
+			defer ts.Close()
+
+			req, err := http.NewRequest(http.MethodPost, ts.URL, strings.NewReader(tc.body))
+			if err != nil {
+				t.Fatalf("Error creating Post request: %s", err)
+			}
+			req.Header = tc.header
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("Error sending Post request: %s", err)
+			}
+			gotHeaders := http.Header{}
+			for k, v := range resp.Header {
+				if strings.HasPrefix(k, "Ce-") {
+					gotHeaders[k] = v
+				}
+			}
+			checkSinkResponse(t, resp, el.Name)
+			if diff := cmp.Diff(tc.wantHeaders, gotHeaders, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("did not get expected CloudEvent headers -want,+got: %s", diff)
+			}
+
+			prs := getCreatedPipelineResources(t, dynamicClient.Actions())
+			if len(prs) != 1 || prs[0].Spec.Params[0].Value != "/example/dev.knative.example/testurl" {
+				t.Errorf("expected a resource with the url from the CloudEvent to be created, got: %+v", prs)
+			}
+		})
+	}
+}
+
+func TestHandleEvent_LifecycleCloudEvents(t *testing.T) {
+	type received struct {
+		header http.Header
+		data   LifecycleEvent
+	}
+	events := make(chan received, 10)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var data LifecycleEvent
+		if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+			t.Errorf("Error decoding CloudEvent data: %s", err)
+		}
+		events <- received{header: r.Header, data: data}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer receiver.Close()
+
+	eventBody := json.RawMessage(`{"repository": {"url": "testurl"}}`)
+	tb, tt := getResources(t, "$(body.repository.url)")
+	tbMissing := bldr.TriggerBinding("tb-missing", namespace,
+		bldr.TriggerBindingSpec(
+			bldr.TriggerBindingParam("revision", "$(body.missing.revision)"),
+		))
+	el := bldr.EventListener("el", namespace, bldr.EventListenerSpec(
+		bldr.EventListenerTrigger("tt", "v1alpha1",
+			bldr.EventListenerTriggerName("created"),
+			bldr.EventListenerTriggerBinding("tb", "TriggerBinding", "v1alpha1"),
+		),
+		bldr.EventListenerTrigger("tt", "v1alpha1",
+			bldr.EventListenerTriggerName("filtered"),
+			bldr.EventListenerCELInterceptor("body.repository.url == 'other'"),
+		),
+		bldr.EventListenerTrigger("tt", "v1alpha1",
+			bldr.EventListenerTriggerName("bad-params"),
+			bldr.EventListenerTriggerBinding("tb-missing", "TriggerBinding", "v1alpha1"),
+		),
+		bldr.EventListenerCloudEventURI(receiver.URL),
+	))
+	resources := test.Resources{
+		TriggerBindings:  []*triggersv1.TriggerBinding{tb, tbMissing},
+		TriggerTemplates: []*triggersv1.TriggerTemplate{tt},
+		EventListeners:   []*triggersv1.EventListener{el},
+	}
+	sink, _ := getSinkAssets(t, resources, el.Name, DefaultAuthOverride{})
+	sink.HTTPClient = receiver.Client()
+	sink.CloudEvents = NewCloudEventSender()
+	ts := httptest.NewServer(http.HandlerFunc(sink.HandleEvent))
+	defer ts.Close()
+
+	resp, err := http.Post(ts.URL, "application/json", bytes.NewReader(eventBody))
+	if err != nil {
+		t.Fatalf("Error creating Post request: %s", err)
+	}
+	checkSinkResponse(t, resp, el.Name)
+	// Draining waits for the CloudEvents to be sent
+	if err := sink.CloudEvents.Drain(wait.ForeverTestTimeout); err != nil {
+		t.Fatalf("Drain() unexpected error: %v", err)
+	}
+	if len(events) != 4 {
+		t.Errorf("%d CloudEvents were sent once drained, want 4", len(events))
+	}
+
+	got := map[string]LifecycleEvent{}
+	for i := 0; i < 4; i++ {
+		select {
+		case e := <-events:
+			if e.header.Get("Ce-Specversion") != "1.0" || e.header.Get("Ce-Id") == "" ||
+				e.header.Get("Ce-Source") != "/apis/triggers.tekton.dev/v1alpha1/namespaces/foo/eventlisteners/el" ||
+				e.header.Get("Ce-Subject") != e.data.Name {
+				t.Errorf("unexpected CloudEvent headers: %v", e.header)
+			}
+			got[e.header.Get("Ce-Type")] = e.data
+		case <-time.After(wait.ForeverTestTimeout):
+			t.Fatalf("timed out waiting for CloudEvents, got: %+v", got)
+		}
+	}
+
+	want := map[string]LifecycleEvent{
+		EventReceivedCloudEventType: {EventListener: el.Name, Namespace: namespace, EventID: eventID},
+		ResourcesCreatedCloudEventType: {
+			EventListener: el.Name, Namespace: namespace, EventID: eventID,
+			TriggerResult: TriggerResult{
+				Name: "created",
+				Resources: []CreatedResource{{
+					APIVersion: "tekton.dev/v1alpha1",
+					Kind:       "PipelineResource",
+					Namespace:  namespace,
+					Name:       "my-pipelineresource",
+				}},
+			},
+		},
+		TriggerFilteredCloudEventType: {
+			EventListener: el.Name, Namespace: namespace, EventID: eventID,
+			TriggerResult: TriggerResult{
+				Name:     "filtered",
+				Filtered: true,
+				Status: &InterceptorStatus{
+					Code:    "FailedPrecondition",
+					Message: "expression body.repository.url == 'other' did not return true",
+				},
+			},
+		},
+		TriggerFailedCloudEventType: {
+			EventListener: el.Name, Namespace: namespace, EventID: eventID,
+			TriggerResult: TriggerResult{
+				Name:       "bad-params",
+				FailedStep: ResolveParamsStep,
+			},
+		},
+	}
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreFields(TriggerResult{}, "Error")); diff != "" {
+		t.Errorf("did not get expected CloudEvents -want,+got: %s", diff)
+	}
+}
+
+func TestHandleEvent_Tracing(t *testing.T) {
+	// The provider of the SDK samples the traces of sampled callers
+	global.SetTracerProvider(sdktrace.NewTracerProvider())
+	traceID := "4bf92f3577b34da6a3ce929d0e0e4736"
+	incoming := "00-" + traceID + "-00f067aa0ba902b7-01"
+
+	traceparents := make(chan string, 1)
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparents <- r.Header.Get("Traceparent")
+		body, _ := ioutil.ReadAll(r.Body)
+		_, _ = w.Write(body)
+	}))
+	defer webhook.Close()
+	client := webhook.Client()
+	u, _ := url.Parse(webhook.URL)
+	client.Transport = &http.Transport{Proxy: http.ProxyURL(u)}
+
+	tb, tt := getResources(t, "$(body.repository.url)")
+	el := bldr.EventListener("el", namespace, bldr.EventListenerSpec(
+		bldr.EventListenerTrigger("tt", "v1alpha1",
+			bldr.EventListenerTriggerBinding("tb", "TriggerBinding", "v1alpha1"),
+			bldr.EventListenerTriggerInterceptor("foo", "v1", "Service", namespace),
+		),
+	))
+	resources := test.Resources{
+		TriggerBindings:  []*triggersv1.TriggerBinding{tb},
+		TriggerTemplates: []*triggersv1.TriggerTemplate{tt},
+		EventListeners:   []*triggersv1.EventListener{el},
+	}
+	sink, dynamicClient := getSinkAssets(t, resources, el.Name, DefaultAuthOverride{})
+	sink.HTTPClient = client
+	ts := httptest.NewServer(http.HandlerFunc(sink.HandleEvent))
+	defer ts.Close()
+
+	req, err := http.NewRequest(http.MethodPost, ts.URL, bytes.NewReader([]byte(`{"repository": {"url": "testurl"}}`)))
+	if err != nil {
+		t.Fatalf("Error creating Post request: %s", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Traceparent", incoming)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Error sending Post request: %s", err)
+	}
+	checkSinkResponse(t, resp, el.Name)
+
+	got := <-traceparents
+	if !strings.HasPrefix(got, "00-"+traceID+"-") || got == incoming {
+		t.Errorf("webhook interceptor got traceparent %q, want a child span of %q", got, incoming)
+	}
+
+	prs := getCreatedPipelineResources(t, dynamicClient.Actions())
+	if len(prs) != 1 {
+		t.Fatalf("expected 1 created resource, got %d", len(prs))
+	}
+	if got := prs[0].Annotations["triggers.tekton.dev/trace-id"]; got != traceID {
+		t.Errorf("trace-id annotation = %q, want %q", got, traceID)
+	}
+}
+
+func TestHandleEvent_FirstMatch(t *testing.T) {
+	eventBody := json.RawMessage(`{"repository": {"url": "testurl"}}`)
+	tb, tt := getResources(t, "$(body.repository.url)")
+	for _, mode := range []triggersv1.EventProcessingMode{triggersv1.SyncProcessingMode, triggersv1.AsyncProcessingMode} {
+		t.Run(string(mode), func(t *testing.T) {
+			el := bldr.EventListener("el", namespace, bldr.EventListenerSpec(
+				bldr.EventListenerMatchPolicy(triggersv1.FirstMatchPolicy),
+				bldr.EventListenerProcessingMode(mode),
+				bldr.EventListenerTrigger("tt", "v1alpha1",
+					bldr.EventListenerTriggerName("lowest"),
+					bldr.EventListenerTriggerBinding("tb", "TriggerBinding", "v1alpha1"),
+				),
+				bldr.EventListenerTrigger("tt", "v1alpha1",
+					bldr.EventListenerTriggerName("filtered"),
+					bldr.EventListenerTriggerPriority(10),
+					bldr.EventListenerTriggerBinding("tb", "TriggerBinding", "v1alpha1"),
+					bldr.EventListenerCELInterceptor("body.repository.url == 'other'"),
+				),
+				bldr.EventListenerTrigger("tt", "v1alpha1",
+					bldr.EventListenerTriggerName("first"),
+					bldr.EventListenerTriggerPriority(5),
+					bldr.EventListenerTriggerBinding("tb", "TriggerBinding", "v1alpha1"),
+					bldr.EventListenerCELInterceptor("body.repository.url == 'testurl'"),
+				),
+				bldr.EventListenerTrigger("tt", "v1alpha1",
+					bldr.EventListenerTriggerName("second"),
+					bldr.EventListenerTriggerPriority(5),
+					bldr.EventListenerTriggerBinding("tb", "TriggerBinding", "v1alpha1"),
+				),
+			))
+			resources := test.Resources{
+				TriggerBindings:  []*triggersv1.TriggerBinding{tb},
+				TriggerTemplates: []*triggersv1.TriggerTemplate{tt},
+				EventListeners:   []*triggersv1.EventListener{el},
+			}
+			sink, dynamicClient := getSinkAssets(t, resources, el.Name, DefaultAuthOverride{})
+			sink.EventQueue = NewEventQueue(10, 1, 0)
+			sink.StartWorkers()
+			ts := httptest.NewServer(http.HandlerFunc(sink.HandleEvent))
+			defer ts.Close()
+
+			resp, err := http.Post(ts.URL, "application/json", bytes.NewReader(eventBody))
+			if err != nil {
+				t.Fatalf("Error creating Post request: %s", err)
+			}
+			var gotBody Response
+			if err := json.NewDecoder(resp.Body).Decode(&gotBody); err != nil {
+				t.Fatalf("Error reading response body: %s", err)
+			}
+			if mode == triggersv1.SyncProcessingMode {
+				if resp.StatusCode != http.StatusCreated {
+					t.Fatalf("expected response code 201 but got: %v", resp.Status)
+				}
+				var gotNames []string
+				for _, result := range gotBody.Triggers {
+					gotNames = append(gotNames, result.Name)
+				}
+				if diff := cmp.Diff([]string{"filtered", "first"}, gotNames); diff != "" {
+					t.Errorf("did not evaluate the expected triggers -want,+got: %s", diff)
+				}
+			}
+
+			if err := sink.EventQueue.Drain(wait.ForeverTestTimeout); err != nil {
+				t.Fatalf("Drain() unexpected error: %v", err)
+			}
+			gotPrs := getCreatedPipelineResources(t, dynamicClient.Actions())
+			if len(gotPrs) != 1 || gotPrs[0].Labels[triggerLabel] != "first" {
+				t.Errorf("expected only the first matching trigger to create resources, got: %+v", gotPrs)
+			}
+		})
+	}
+}
+
+func TestByPriority(t *testing.T) {
+	triggers := []triggersv1.EventListenerTrigger{
+		{Name: "a"},
+		{Name: "b", Priority: 1},
+		{Name: "c", Priority: -1},
+		{Name: "d", Priority: 1},
+		{Name: "e"},
+	}
+	var got []string
+	for _, t := range byPriority(triggers) {
+		got = append(got, t.Name)
+	}
+	if diff := cmp.Diff([]string{"b", "d", "a", "e", "c"}, got); diff != "" {
+		t.Errorf("byPriority() -want,+got: %s", diff)
+	}
+	if triggers[0].Name != "a" || triggers[1].Name != "b" {
+		t.Errorf("byPriority() modified its argument: %+v", triggers)
+	}
+}
+
+func TestHandleEvent_RateLimit(t *testing.T) {
+	tb, tt := getResources(t, "$(body.repository.url)")
+	el := bldr.EventListener("el", namespace, bldr.EventListenerSpec(
+		bldr.EventListenerRateLimit(triggersv1.RateLimit{
+			Limit:  1,
+			Period: &metav1.Duration{Duration: time.Minute},
+			Key:    "body.repository.url",
+		}),
+		bldr.EventListenerTrigger("tt", "v1alpha1",
+			bldr.EventListenerTriggerBinding("tb", "TriggerBinding", "v1alpha1"),
+		),
+	))
+	resources := test.Resources{
+		TriggerBindings:  []*triggersv1.TriggerBinding{tb},
+		TriggerTemplates: []*triggersv1.TriggerTemplate{tt},
+		EventListeners:   []*triggersv1.EventListener{el},
+	}
+	sink, _ := getSinkAssets(t, resources, el.Name, DefaultAuthOverride{})
+	sink.RateLimiters = NewRateLimiters()
+	ts := httptest.NewServer(http.HandlerFunc(sink.HandleEvent))
+	defer ts.Close()
+
+	post := func(body string) *http.Response {
+		t.Helper()
+		resp, err := http.Post(ts.URL, "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("Error creating Post request: %s", err)
+		}
+		return resp
+	}
+
+	if resp := post(`{"repository": {"url": "testurl"}}`); resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected response code 201 but got: %v", resp.Status)
+	}
+	resp := post(`{"repository": {"url": "testurl"}}`)
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("expected response code 429 but got: %v", resp.Status)
+	}
+	if got := resp.Header.Get("Retry-After"); got != "60" {
+		t.Errorf("expected Retry-After 60 but got: %q", got)
+	}
+	// Events with another key are limited separately
+	if resp := post(`{"repository": {"url": "otherurl"}}`); resp.StatusCode == http.StatusTooManyRequests {
+		t.Errorf("expected an event with another key not to be rate limited, got: %v", resp.Status)
+	}
+}
+
+func TestHandleEvent_TriggerRateLimit(t *testing.T) {
+	tb, tt := getResources(t, "$(body.repository.url)")
+	el := bldr.EventListener("el", namespace, bldr.EventListenerSpec(
+		bldr.EventListenerTrigger("tt", "v1alpha1",
+			bldr.EventListenerTriggerName("limited"),
+			bldr.EventListenerTriggerBinding("tb", "TriggerBinding", "v1alpha1"),
+			bldr.EventListenerTriggerRateLimit(triggersv1.RateLimit{Limit: 1, Period: &metav1.Duration{Duration: time.Minute}}),
+		),
+		bldr.EventListenerTrigger("tt", "v1alpha1",
+			bldr.EventListenerTriggerName("filtered"),
+			bldr.EventListenerTriggerBinding("tb", "TriggerBinding", "v1alpha1"),
+			bldr.EventListenerCELInterceptor("body.repository.url == 'other'"),
+		),
+	))
+	resources := test.Resources{
+		TriggerBindings:  []*triggersv1.TriggerBinding{tb},
+		TriggerTemplates: []*triggersv1.TriggerTemplate{tt},
+		EventListeners:   []*triggersv1.EventListener{el},
+	}
+	sink, dynamicClient := getSinkAssets(t, resources, el.Name, DefaultAuthOverride{})
+	sink.RateLimiters = NewRateLimiters()
+	ts := httptest.NewServer(http.HandlerFunc(sink.HandleEvent))
+	defer ts.Close()
+
+	for i, wantCode := range []int{http.StatusCreated, http.StatusTooManyRequests} {
+		resp, err := http.Post(ts.URL, "application/json", strings.NewReader(`{"repository": {"url": "testurl"}}`))
+		if err != nil {
+			t.Fatalf("Error creating Post request: %s", err)
+		}
+		if resp.StatusCode != wantCode {
+			t.Fatalf("event %d: expected response code %d but got: %v", i, wantCode, resp.Status)
+		}
+		var gotBody Response
+		if err := json.NewDecoder(resp.Body).Decode(&gotBody); err != nil {
+			t.Fatalf("Error reading response body: %s", err)
+		}
+		if wantCode == http.StatusTooManyRequests {
+			if got := resp.Header.Get("Retry-After"); got != "60" {
+				t.Errorf("expected Retry-After 60 but got: %q", got)
+			}
+			if got := gotBody.Triggers[0]; got.Name != "limited" || got.FailedStep != RateLimitStep {
+				t.Errorf("expected the limited trigger to fail at the RateLimit step, got: %+v", got)
+			}
+		}
+	}
+	if gotPrs := getCreatedPipelineResources(t, dynamicClient.Actions()); len(gotPrs) != 1 {
+		t.Errorf("expected a single resource to be created, got: %+v", gotPrs)
+	}
+}
+
+func TestHandleEvent_Retry(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		maxAttempts  int32
+		wantCode     int
+		wantAttempts int
+	}{{
+		name:         "retried",
+		maxAttempts:  2,
+		wantCode:     http.StatusCreated,
+		wantAttempts: 2,
+	}, {
+		name:         "retries disabled",
+		maxAttempts:  1,
+		wantCode:     http.StatusAccepted,
+		wantAttempts: 1,
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			tb, tt := getResources(t, "$(body.repository.url)")
+			el := bldr.EventListener("el", namespace, bldr.EventListenerSpec(
+				bldr.EventListenerTrigger("tt", "v1alpha1",
+					bldr.EventListenerTriggerBinding("tb", "TriggerBinding", "v1alpha1"),
+					bldr.EventListenerTriggerRetry(triggersv1.RetryPolicy{
+						MaxAttempts: tc.maxAttempts,
+						Backoff:     &metav1.Duration{Duration: time.Millisecond},
+					}),
+				),
+			))
+			resources := test.Resources{
+				TriggerBindings:  []*triggersv1.TriggerBinding{tb},
+				TriggerTemplates: []*triggersv1.TriggerTemplate{tt},
+				EventListeners:   []*triggersv1.EventListener{el},
+			}
+			sink, dynamicClient := getSinkAssets(t, resources, el.Name, DefaultAuthOverride{})
+			attempts := 0
+			dynamicClient.PrependReactor("create", "*", func(action ktesting.Action) (bool, runtime.Object, error) {
+				attempts++
+				if attempts == 1 {
+					return true, nil, kerrors.NewServiceUnavailable("unavailable")
+				}
+				return false, nil, nil
+			})
+			ts := httptest.NewServer(http.HandlerFunc(sink.HandleEvent))
+			defer ts.Close()
+
+			resp, err := http.Post(ts.URL, "application/json", strings.NewReader(`{"repository": {"url": "testurl"}}`))
+			if err != nil {
+				t.Fatalf("Error creating Post request: %s", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tc.wantCode {
+				t.Errorf("expected response code %d but got: %v", tc.wantCode, resp.Status)
+			}
+			if attempts != tc.wantAttempts {
+				t.Errorf("expected %d attempts at creating the resource but got %d", tc.wantAttempts, attempts)
+			}
+		})
+	}
+}
+
+func TestHandleEvent_CancelledRequest(t *testing.T) {
+	tb, tt := getResources(t, "$(body.repository.url)")
+	el := bldr.EventListener("el", namespace, bldr.EventListenerSpec(
+		bldr.EventListenerTrigger("tt", "v1alpha1",
+			bldr.EventListenerTriggerBinding("tb", "TriggerBinding", "v1alpha1"),
+			bldr.EventListenerTriggerRetry(triggersv1.RetryPolicy{
+				MaxAttempts: 2,
+				Backoff:     &metav1.Duration{Duration: time.Millisecond},
+			}),
+		),
+	))
+	resources := test.Resources{
+		TriggerBindings:  []*triggersv1.TriggerBinding{tb},
+		TriggerTemplates: []*triggersv1.TriggerTemplate{tt},
+		EventListeners:   []*triggersv1.EventListener{el},
+	}
+	sink, dynamicClient := getSinkAssets(t, resources, el.Name, DefaultAuthOverride{})
+	attempts := 0
+	dynamicClient.PrependReactor("create", "*", func(action ktesting.Action) (bool, runtime.Object, error) {
+		attempts++
+		if attempts == 1 {
+			return true, nil, kerrors.NewServiceUnavailable("unavailable")
+		}
+		return false, nil, nil
+	})
+
+	// The client disconnected, or the handler timed out, before the Trigger
+	// was processed
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"repository": {"url": "testurl"}}`)).WithContext(ctx)
+	rec := httptest.NewRecorder()
+	sink.HandleEvent(rec, req)
+	if rec.Code != http.StatusCreated {
+		t.Errorf("expected response code %d but got: %d", http.StatusCreated, rec.Code)
+	}
+	if attempts != 2 {
+		t.Errorf("expected 2 attempts at creating the resource but got %d", attempts)
+	}
+}
+
+func TestHandleEvent_Routing(t *testing.T) {
+	tb, tt := getResources(t, "$(body.repository.url)")
+	el := bldr.EventListener("el", namespace, bldr.EventListenerSpec(
+		bldr.EventListenerTrigger("tt", "v1alpha1",
+			bldr.EventListenerTriggerName("github"),
+			bldr.EventListenerTriggerPath("/github"),
+			bldr.EventListenerTriggerMethod(http.MethodPost),
+			bldr.EventListenerTriggerBinding("tb", "TriggerBinding", "v1alpha1"),
+		),
+		bldr.EventListenerTrigger("tt", "v1alpha1",
+			bldr.EventListenerTriggerName("gitlab"),
+			bldr.EventListenerTriggerPath("/gitlab"),
+			bldr.EventListenerTriggerBinding("tb", "TriggerBinding", "v1alpha1"),
+		),
+	))
+	resources := test.Resources{
+		TriggerBindings:  []*triggersv1.TriggerBinding{tb},
+		TriggerTemplates: []*triggersv1.TriggerTemplate{tt},
+		EventListeners:   []*triggersv1.EventListener{el},
+	}
+
+	for _, tc := range []struct {
+		name         string
+		method       string
+		path         string
+		wantCode     int
+		wantTriggers []string
+	}{{
+		name:         "github",
+		method:       http.MethodPost,
+		path:         "/github",
+		wantCode:     http.StatusCreated,
+		wantTriggers: []string{"github"},
+	}, {
+		name:         "gitlab with any method",
+		method:       http.MethodPut,
+		path:         "/gitlab",
+		wantCode:     http.StatusCreated,
+		wantTriggers: []string{"gitlab"},
+	}, {
+		name:     "unknown path",
+		method:   http.MethodPost,
+		path:     "/bitbucket",
+		wantCode: http.StatusNotFound,
+	}, {
+		name:     "wrong method",
+		method:   http.MethodGet,
+		path:     "/github",
+		wantCode: http.StatusMethodNotAllowed,
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			sink, _ := getSinkAssets(t, resources, el.Name, DefaultAuthOverride{})
+			ts := httptest.NewServer(http.HandlerFunc(sink.HandleEvent))
+			defer ts.Close()
+
+			req, err := http.NewRequest(tc.method, ts.URL+tc.path, strings.NewReader(`{"repository": {"url": "testurl"}}`))
+			if err != nil {
+				t.Fatalf("Error creating request: %s", err)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("Error sending request: %s", err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != tc.wantCode {
+				t.Fatalf("expected response code %d but got: %v", tc.wantCode, resp.Status)
+			}
+			if tc.wantTriggers == nil {
+				return
+			}
+			var gotBody Response
+			if err := json.NewDecoder(resp.Body).Decode(&gotBody); err != nil {
+				t.Fatalf("Error reading response body: %s", err)
+			}
+			var gotTriggers []string
+			for _, result := range gotBody.Triggers {
+				gotTriggers = append(gotTriggers, result.Name)
+			}
+			if diff := cmp.Diff(tc.wantTriggers, gotTriggers); diff != "" {
+				t.Errorf("did not process the expected triggers -want,+got: %s", diff)
+			}
+		})
+	}
+}
+
+func TestHandleEvent_DryRun(t *testing.T) {
+	tb, tt := getResources(t, "$(body.repository.url)")
+	dryRunTrigger := bldr.EventListenerTrigger("tt", "v1alpha1",
+		bldr.EventListenerTriggerName("new"),
+		bldr.EventListenerTriggerPriority(10),
+		bldr.EventListenerTriggerDryRun(),
+		bldr.EventListenerTriggerBinding("tb", "TriggerBinding", "v1alpha1"),
+	)
+	for _, tc := range []struct {
+		name        string
+		spec        bldr.EventListenerOp
+		wantCode    int
+		wantResults []TriggerResult
+	}{{
+		name:     "only dry run",
+		spec:     bldr.EventListenerSpec(dryRunTrigger),
+		wantCode: http.StatusAccepted,
+		wantResults: []TriggerResult{{
+			Name:   "new",
+			DryRun: true,
+			Resources: []CreatedResource{{
+				APIVersion: "tekton.dev/v1alpha1",
+				Kind:       "PipelineResource",
+				Namespace:  namespace,
+				Name:       "my-pipelineresource",
+			}},
+		}},
+	}, {
+		name: "dry run does not match first",
+		spec: bldr.EventListenerSpec(
+			bldr.EventListenerMatchPolicy(triggersv1.FirstMatchPolicy),
+			bldr.EventListenerTrigger("tt", "v1alpha1",
+				bldr.EventListenerTriggerName("old"),
+				bldr.EventListenerTriggerBinding("tb", "TriggerBinding", "v1alpha1"),
+			),
+			dryRunTrigger,
+		),
+		wantCode: http.StatusCreated,
+		wantResults: []TriggerResult{{
+			Name:   "new",
+			DryRun: true,
+			Resources: []CreatedResource{{
+				APIVersion: "tekton.dev/v1alpha1",
+				Kind:       "PipelineResource",
+				Namespace:  namespace,
+				Name:       "my-pipelineresource",
+			}},
+		}, {
+			Name: "old",
+			Resources: []CreatedResource{{
+				APIVersion: "tekton.dev/v1alpha1",
+				Kind:       "PipelineResource",
+				Namespace:  namespace,
+				Name:       "my-pipelineresource",
+			}},
+		}},
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			el := bldr.EventListener("el", namespace, tc.spec)
+			resources := test.Resources{
+				TriggerBindings:  []*triggersv1.TriggerBinding{tb},
+				TriggerTemplates: []*triggersv1.TriggerTemplate{tt},
+				EventListeners:   []*triggersv1.EventListener{el},
+			}
+			sink, dynamicClient := getSinkAssets(t, resources, el.Name, DefaultAuthOverride{})
+			// The fake dynamic client persists resources created with a dry
+			// run, which would conflict with those of the other Trigger
+			dynamicClient.PrependReactor("create", "*", func(action ktesting.Action) (bool, runtime.Object, error) {
+				return true, action.(ktesting.CreateAction).GetObject(), nil
+			})
+			ts := httptest.NewServer(http.HandlerFunc(sink.HandleEvent))
+			defer ts.Close()
+
+			resp, err := http.Post(ts.URL, "application/json", strings.NewReader(`{"repository": {"url": "testurl"}}`))
+			if err != nil {
+				t.Fatalf("Error creating Post request: %s", err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != tc.wantCode {
+				t.Errorf("expected response code %d but got: %v", tc.wantCode, resp.Status)
+			}
+			var got Response
+			if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+				t.Fatalf("Error decoding the response: %s", err)
+			}
+			if diff := cmp.Diff(tc.wantResults, got.Triggers); diff != "" {
+				t.Errorf("Trigger results (-want +got): %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	triggersv1 "github.com/tektoncd/triggers/pkg/apis/triggers/v1alpha1"
+)
+
+// errSourceNotAllowed is returned for requests sent from an address that
+// the EventListener does not accept requests from.
+var errSourceNotAllowed = errors.New("source address not allowed")
+
+type sourceIPKey struct{}
+
+// withSourceIP returns a copy of ctx carrying the address of the client that
+// sent the event.
+func withSourceIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, sourceIPKey{}, ip)
+}
+
+// sourceIP returns the address of the client that sent the event, or "" if
+// ctx does not carry it.
+func sourceIP(ctx context.Context) string {
+	ip, _ := ctx.Value(sourceIPKey{}).(string)
+	return ip
+}
+
+// checkSource returns the address of the client that sent the request,
+// failing with errSourceNotAllowed when the EventListener does not accept
+// requests from it. The address is taken from the X-Forwarded-For header when
+// the request is sent by a trusted proxy.
+func checkSource(sources *triggersv1.AllowedSources, request *http.Request) (string, error) {
+	if sources == nil {
+		ip := clientIP(request, nil)
+		if ip == nil {
+			return "", nil
+		}
+		return ip.String(), nil
+	}
+	allowed, err := parseCIDRs(sources.CIDRs)
+	if err != nil {
+		return "", err
+	}
+	trusted, err := parseCIDRs(sources.TrustedProxies)
+	if err != nil {
+		return "", err
+	}
+	ip := clientIP(request, trusted)
+	if ip == nil {
+		return "", fmt.Errorf("%w: unknown address %q", errSourceNotAllowed, request.RemoteAddr)
+	}
+	if !containsIP(allowed, ip) {
+		return ip.String(), fmt.Errorf("%w: %s", errSourceNotAllowed, ip)
+	}
+	return ip.String(), nil
+}
+
+// clientIP returns the address of the client that sent the request. When the
+// request is sent by a trusted proxy, the X-Forwarded-For header is walked
+// from the last hop, skipping the trusted proxies, so that a client cannot
+// pretend to be sending from another address by setting the header itself.
+func clientIP(request *http.Request, trusted []*net.IPNet) net.IP {
+	host, _, err := net.SplitHostPort(request.RemoteAddr)
+	if err != nil {
+		host = request.RemoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !containsIP(trusted, ip) {
+		return ip
+	}
+	var hops []string
+	for _, header := range request.Header.Values("X-Forwarded-For") {
+		hops = append(hops, strings.Split(header, ",")...)
+	}
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := net.ParseIP(strings.TrimSpace(hops[i]))
+		if hop == nil {
+			// The last valid hop is a trusted proxy
+			return ip
+		}
+		ip = hop
+		if !containsIP(trusted, ip) {
+			return ip
+		}
+	}
+	return ip
+}
+
+func parseCIDRs(cidrs []string) ([]*net.IPNet, error) {
+	nets := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, n, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CIDR %q: %w", cidr, err)
+		}
+		nets = append(nets, n)
+	}
+	return nets, nil
+}
+
+func containsIP(nets []*net.IPNet, ip net.IP) bool {
+	for _, n := range nets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
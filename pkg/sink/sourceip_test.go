@@ -0,0 +1,147 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sink
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	triggersv1 "github.com/tektoncd/triggers/pkg/apis/triggers/v1alpha1"
+	"github.com/tektoncd/triggers/test"
+	bldr "github.com/tektoncd/triggers/test/builder"
+)
+
+func TestCheckSource(t *testing.T) {
+	sources := &triggersv1.AllowedSources{
+		CIDRs:          []string{"192.30.252.0/22", "2001:db8::/32"},
+		TrustedProxies: []string{"10.0.0.0/8"},
+	}
+	tests := []struct {
+		name       string
+		sources    *triggersv1.AllowedSources
+		remoteAddr string
+		forwarded  []string
+		want       string
+		wantErr    bool
+	}{{
+		name:       "no allowed sources",
+		remoteAddr: "172.16.0.1:1234",
+		want:       "172.16.0.1",
+	}, {
+		name:       "allowed address",
+		sources:    sources,
+		remoteAddr: "192.30.252.1:1234",
+		want:       "192.30.252.1",
+	}, {
+		name:       "allowed IPv6 address",
+		sources:    sources,
+		remoteAddr: "[2001:db8::1]:1234",
+		want:       "2001:db8::1",
+	}, {
+		name:       "address not allowed",
+		sources:    sources,
+		remoteAddr: "172.16.0.1:1234",
+		want:       "172.16.0.1",
+		wantErr:    true,
+	}, {
+		name:       "forwarded by a trusted proxy",
+		sources:    sources,
+		remoteAddr: "10.0.0.1:1234",
+		forwarded:  []string{"192.30.252.1"},
+		want:       "192.30.252.1",
+	}, {
+		name:       "forwarded by several trusted proxies",
+		sources:    sources,
+		remoteAddr: "10.0.0.1:1234",
+		forwarded:  []string{"192.30.252.1, 10.0.0.3", "10.0.0.2"},
+		want:       "192.30.252.1",
+	}, {
+		name:       "forwarded address set by the client",
+		sources:    sources,
+		remoteAddr: "10.0.0.1:1234",
+		forwarded:  []string{"192.30.252.1, 172.16.0.1"},
+		want:       "172.16.0.1",
+		wantErr:    true,
+	}, {
+		name:       "forwarded by a proxy that is not trusted",
+		sources:    sources,
+		remoteAddr: "172.16.0.1:1234",
+		forwarded:  []string{"192.30.252.1"},
+		want:       "172.16.0.1",
+		wantErr:    true,
+	}, {
+		name:       "trusted proxy without a forwarded address",
+		sources:    sources,
+		remoteAddr: "10.0.0.1:1234",
+		want:       "10.0.0.1",
+		wantErr:    true,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for _, f := range tt.forwarded {
+				req.Header.Add("X-Forwarded-For", f)
+			}
+			got, err := checkSource(tt.sources, req)
+			if tt.wantErr != errors.Is(err, errSourceNotAllowed) {
+				t.Errorf("checkSource() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("checkSource() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandleEvent_AllowedSources(t *testing.T) {
+	tb, tt := getResources(t, "$(body.repository.url)")
+	el := bldr.EventListener("el", namespace, bldr.EventListenerSpec(
+		bldr.EventListenerAllowedSources([]string{"192.30.252.0/22"}),
+		bldr.EventListenerTrigger("tt", "v1alpha1",
+			bldr.EventListenerTriggerBinding("tb", "TriggerBinding", "v1alpha1"),
+		),
+	))
+	resources := test.Resources{
+		TriggerBindings:  []*triggersv1.TriggerBinding{tb},
+		TriggerTemplates: []*triggersv1.TriggerTemplate{tt},
+		EventListeners:   []*triggersv1.EventListener{el},
+	}
+	sink, dynamicClient := getSinkAssets(t, resources, el.Name, DefaultAuthOverride{})
+
+	for _, c := range []struct {
+		remoteAddr string
+		wantCode   int
+	}{
+		{remoteAddr: "172.16.0.1:1234", wantCode: http.StatusForbidden},
+		{remoteAddr: "192.30.252.1:1234", wantCode: http.StatusCreated},
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"repository": {"url": "testurl"}}`))
+		req.RemoteAddr = c.remoteAddr
+		rec := httptest.NewRecorder()
+		sink.HandleEvent(rec, req)
+		if rec.Code != c.wantCode {
+			t.Errorf("HandleEvent() from %s status = %d, want %d", c.remoteAddr, rec.Code, c.wantCode)
+		}
+		if c.wantCode == http.StatusForbidden && len(dynamicClient.Actions()) != 0 {
+			t.Errorf("HandleEvent() created resources for a request that is not allowed: %v", dynamicClient.Actions())
+		}
+	}
+}
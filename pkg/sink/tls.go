@@ -0,0 +1,177 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sink
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// tlsReloadInterval is how often the TLS files are checked for changes.
+const tlsReloadInterval = 10 * time.Second
+
+// NewTLSConfig returns the TLS configuration of a server presenting the
+// certificate in certFile and keyFile. If clientCAFile is not empty, the
+// client certificates are verified against the CA certificates it holds. The
+// files are checked for changes every 10 seconds until ctx is done, and read
+// again when they change, such as when the Secret they are mounted from is
+// updated.
+func NewTLSConfig(ctx context.Context, certFile, keyFile, clientCAFile string, log *zap.SugaredLogger) (*tls.Config, error) {
+	return newTLSConfig(ctx, certFile, keyFile, clientCAFile, tlsReloadInterval, log)
+}
+
+func newTLSConfig(ctx context.Context, certFile, keyFile, clientCAFile string, interval time.Duration, log *zap.SugaredLogger) (*tls.Config, error) {
+	l := &tlsLoader{
+		certFile:     certFile,
+		keyFile:      keyFile,
+		clientCAFile: clientCAFile,
+		log:          log,
+	}
+	if _, err := l.load(); err != nil {
+		return nil, err
+	}
+	go l.watch(ctx, interval)
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		// Certificates are served from the configuration of each client,
+		// GetCertificate is only set for the server to accept the config
+		// without certificate files.
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return &l.get().Certificates[0], nil
+		},
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			return l.get(), nil
+		},
+	}, nil
+}
+
+// RequireClientCertificate wraps h to reject requests that were not sent with
+// a client certificate verified against the client CA certificates. Without
+// one, the server lets the TLS handshake succeed so that the probes of the
+// kubelet still reach the handlers that do not require a certificate.
+func RequireClientCertificate(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 {
+			http.Error(w, "a verified client certificate is required", http.StatusUnauthorized)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// tlsLoader loads the TLS configuration from its files, and loads it again
+// when they change.
+type tlsLoader struct {
+	certFile     string
+	keyFile      string
+	clientCAFile string
+	log          *zap.SugaredLogger
+
+	mu     sync.Mutex
+	stamp  string
+	config *tls.Config
+}
+
+// get returns the current TLS configuration.
+func (l *tlsLoader) get() *tls.Config {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.config
+}
+
+// watch loads the TLS configuration again whenever its files change, until
+// ctx is done. If the files cannot be loaded, the previous configuration is
+// kept.
+func (l *tlsLoader) watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		l.mu.Lock()
+		stamp := l.stamp
+		l.mu.Unlock()
+		if current, err := l.fileStamp(); err == nil && current == stamp {
+			continue
+		}
+		if _, err := l.load(); err != nil {
+			l.log.Errorf("Error reloading the TLS certificates, serving the previous ones: %s", err)
+		}
+	}
+}
+
+func (l *tlsLoader) load() (*tls.Config, error) {
+	// Take the stamp first so that a change while loading is picked up
+	// next time
+	stamp, err := l.fileStamp()
+	if err != nil {
+		return nil, err
+	}
+	cert, err := tls.LoadX509KeyPair(l.certFile, l.keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the TLS certificate: %w", err)
+	}
+	config := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+	}
+	if l.clientCAFile != "" {
+		pem, err := ioutil.ReadFile(l.clientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the client CA certificates: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no client CA certificates found in %s", l.clientCAFile)
+		}
+		config.ClientCAs = pool
+		config.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.config, l.stamp = config, stamp
+	return config, nil
+}
+
+// fileStamp identifies the current version of the files.
+func (l *tlsLoader) fileStamp() (string, error) {
+	var stamp string
+	for _, name := range []string{l.certFile, l.keyFile, l.clientCAFile} {
+		if name == "" {
+			continue
+		}
+		info, err := os.Stat(name)
+		if err != nil {
+			return "", err
+		}
+		stamp += fmt.Sprintf("%s:%d:%d;", name, info.ModTime().UnixNano(), info.Size())
+	}
+	return stamp, nil
+}
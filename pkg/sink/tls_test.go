@@ -0,0 +1,281 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sink
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zaptest"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// testCA issues certificates for the tests.
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() unexpected error: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("CreateCertificate() unexpected error: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("ParseCertificate() unexpected error: %v", err)
+	}
+	return &testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue returns the PEM encoded certificate and key with the given serial
+// number, for a server on localhost or for a client.
+func (ca *testCA) issue(t *testing.T, serial int64, usage x509.ExtKeyUsage) ([]byte, []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() unexpected error: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		DNSNames:     []string{"localhost"},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatalf("CreateCertificate() unexpected error: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("MarshalECPrivateKey() unexpected error: %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func writeFile(t *testing.T, name string, data []byte, modTime time.Time) {
+	t.Helper()
+	if err := ioutil.WriteFile(name, data, 0600); err != nil {
+		t.Fatalf("WriteFile() unexpected error: %v", err)
+	}
+	if err := os.Chtimes(name, modTime, modTime); err != nil {
+		t.Fatalf("Chtimes() unexpected error: %v", err)
+	}
+}
+
+func startTLSServer(t *testing.T, config *tls.Config, h http.Handler) *httptest.Server {
+	t.Helper()
+	ts := httptest.NewUnstartedServer(h)
+	ts.TLS = config
+	ts.StartTLS()
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func tlsClient(ca *testCA, certs ...tls.Certificate) *http.Client {
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	return &http.Client{Transport: &http.Transport{
+		TLSClientConfig:   &tls.Config{RootCAs: roots, Certificates: certs},
+		DisableKeepAlives: true,
+	}}
+}
+
+func TestNewTLSConfig_Reload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tls")
+	if err != nil {
+		t.Fatalf("TempDir() unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	ca := newTestCA(t)
+	modTime := time.Now().Add(-time.Minute)
+	cert, key := ca.issue(t, 10, x509.ExtKeyUsageServerAuth)
+	writeFile(t, certFile, cert, modTime)
+	writeFile(t, keyFile, key, modTime)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	config, err := newTLSConfig(ctx, certFile, keyFile, "", 10*time.Millisecond, zaptest.NewLogger(t).Sugar())
+	if err != nil {
+		t.Fatalf("NewTLSConfig() unexpected error: %v", err)
+	}
+	ts := startTLSServer(t, config, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	client := tlsClient(ca)
+	servedSerial := func() int64 {
+		t.Helper()
+		resp, err := client.Get(ts.URL)
+		if err != nil {
+			t.Fatalf("Get() unexpected error: %v", err)
+		}
+		resp.Body.Close()
+		return resp.TLS.PeerCertificates[0].SerialNumber.Int64()
+	}
+
+	if got := servedSerial(); got != 10 {
+		t.Errorf("served certificate %d, want 10", got)
+	}
+	// The certificate is also served to a server without certificate files
+	served, err := config.GetCertificate(&tls.ClientHelloInfo{})
+	if err != nil {
+		t.Fatalf("GetCertificate() unexpected error: %v", err)
+	}
+	if leaf, err := x509.ParseCertificate(served.Certificate[0]); err != nil || leaf.SerialNumber.Int64() != 10 {
+		t.Errorf("GetCertificate() = %v, %v, want the certificate 10", leaf, err)
+	}
+
+	// The rotated certificate is served
+	cert, key = ca.issue(t, 11, x509.ExtKeyUsageServerAuth)
+	modTime = modTime.Add(time.Second)
+	writeFile(t, certFile, cert, modTime)
+	writeFile(t, keyFile, key, modTime)
+	if err := wait.PollImmediate(10*time.Millisecond, wait.ForeverTestTimeout, func() (bool, error) {
+		return servedSerial() == 11, nil
+	}); err != nil {
+		t.Errorf("served certificate %d after rotation, want 11", servedSerial())
+	}
+
+	// An invalid certificate is not served
+	modTime = modTime.Add(time.Second)
+	writeFile(t, certFile, []byte("invalid"), modTime)
+	time.Sleep(50 * time.Millisecond)
+	if got := servedSerial(); got != 11 {
+		t.Errorf("served certificate %d after an invalid rotation, want 11", got)
+	}
+}
+
+func TestNewTLSConfig_ClientCertificate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tls")
+	if err != nil {
+		t.Fatalf("TempDir() unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	certFile, keyFile, caFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key"), filepath.Join(dir, "ca.crt")
+	ca := newTestCA(t)
+	modTime := time.Now()
+	cert, key := ca.issue(t, 10, x509.ExtKeyUsageServerAuth)
+	writeFile(t, certFile, cert, modTime)
+	writeFile(t, keyFile, key, modTime)
+	writeFile(t, caFile, ca.pem, modTime)
+
+	config, err := NewTLSConfig(context.Background(), certFile, keyFile, caFile, zaptest.NewLogger(t).Sugar())
+	if err != nil {
+		t.Fatalf("NewTLSConfig() unexpected error: %v", err)
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/", RequireClientCertificate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})))
+	mux.HandleFunc("/live", func(w http.ResponseWriter, r *http.Request) {})
+	ts := startTLSServer(t, config, mux)
+
+	clientCert, clientKey := ca.issue(t, 20, x509.ExtKeyUsageClientAuth)
+	pair, err := tls.X509KeyPair(clientCert, clientKey)
+	if err != nil {
+		t.Fatalf("X509KeyPair() unexpected error: %v", err)
+	}
+	otherCert, otherKey := newTestCA(t).issue(t, 30, x509.ExtKeyUsageClientAuth)
+	otherPair, err := tls.X509KeyPair(otherCert, otherKey)
+	if err != nil {
+		t.Fatalf("X509KeyPair() unexpected error: %v", err)
+	}
+
+	for _, tc := range []struct {
+		name     string
+		client   *http.Client
+		path     string
+		wantCode int
+		wantErr  bool
+	}{{
+		name:     "client certificate",
+		client:   tlsClient(ca, pair),
+		path:     "/",
+		wantCode: http.StatusOK,
+	}, {
+		name:     "no client certificate",
+		client:   tlsClient(ca),
+		path:     "/",
+		wantCode: http.StatusUnauthorized,
+	}, {
+		name:     "probe without client certificate",
+		client:   tlsClient(ca),
+		path:     "/live",
+		wantCode: http.StatusOK,
+	}, {
+		name:    "client certificate from another CA",
+		client:  tlsClient(ca, otherPair),
+		path:    "/",
+		wantErr: true,
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := tc.client.Get(ts.URL + tc.path)
+			if tc.wantErr {
+				if err == nil {
+					resp.Body.Close()
+					t.Fatalf("expected the TLS handshake to fail, got: %v", resp.Status)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Get() unexpected error: %v", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tc.wantCode {
+				t.Errorf("expected response code %d but got: %v", tc.wantCode, resp.Status)
+			}
+		})
+	}
+}
+
+func TestNewTLSConfig_Invalid(t *testing.T) {
+	if _, err := NewTLSConfig(context.Background(), "does-not-exist.crt", "does-not-exist.key", "", zaptest.NewLogger(t).Sugar()); err == nil {
+		t.Error("NewTLSConfig() with missing files expected an error")
+	}
+}
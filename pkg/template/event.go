@@ -19,7 +19,9 @@ package template
 import (
 	"encoding/json"
 	"fmt"
+	"mime"
 	"net/http"
+	"net/url"
 	"strings"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -34,6 +36,10 @@ const (
 	//
 	// This can be removed when this functionality is no-longer needed.
 	OldEscapeAnnotation = "triggers.tekton.dev/old-escape-quotes"
+
+	// cloudEventHeaderPrefix prefixes the HTTP headers holding the context
+	// attributes of a CloudEvent in binary content mode.
+	cloudEventHeaderPrefix = "Ce-"
 )
 
 // ResolveParams takes given triggerbindings and produces the resulting
@@ -71,10 +77,15 @@ type event struct {
 	Header     map[string]string      `json:"header"`
 	Body       interface{}            `json:"body"`
 	Extensions map[string]interface{} `json:"extensions"`
+	Context    map[string]string      `json:"context"`
 }
 
 // newEvent returns a new Event from HTTP headers and body
 func newEvent(body []byte, headers http.Header, extensions map[string]interface{}) (*event, error) {
+	body, err := DecodeBody(body, headers)
+	if err != nil {
+		return nil, err
+	}
 	var data interface{}
 	if len(body) > 0 {
 		if err := json.Unmarshal(body, &data); err != nil {
@@ -90,9 +101,56 @@ func newEvent(body []byte, headers http.Header, extensions map[string]interface{
 		Header:     joinedHeaders,
 		Body:       data,
 		Extensions: extensions,
+		Context:    CloudEventContext(headers),
 	}, nil
 }
 
+// CloudEventContext returns the context attributes of a CloudEvent in binary
+// content mode, keyed by attribute name, from the Ce- prefixed HTTP headers.
+// It is empty if the event is not a CloudEvent.
+func CloudEventContext(header http.Header) map[string]string {
+	context := map[string]string{}
+	for k, v := range header {
+		if len(k) > len(cloudEventHeaderPrefix) && strings.EqualFold(k[:len(cloudEventHeaderPrefix)], cloudEventHeaderPrefix) {
+			context[strings.ToLower(k[len(cloudEventHeaderPrefix):])] = strings.Join(v, ",")
+		}
+	}
+	if len(context) > 0 {
+		if contentType := header.Get("Content-Type"); contentType != "" {
+			context["datacontenttype"] = contentType
+		}
+	}
+	return context
+}
+
+// DecodeBody returns the JSON body of an event from its HTTP body and headers.
+// An application/x-www-form-urlencoded body is decoded into a JSON object with
+// a field for each form parameter, unless it only has a payload parameter
+// holding a JSON document, as sent by GitHub, in which case that document is
+// returned. Other bodies are returned as is.
+func DecodeBody(body []byte, header http.Header) ([]byte, error) {
+	mediaType, _, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil || mediaType != "application/x-www-form-urlencoded" {
+		return body, nil
+	}
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse form encoded request body: %w", err)
+	}
+	if payload, ok := form["payload"]; ok && len(form) == 1 && len(payload) == 1 && json.Valid([]byte(payload[0])) {
+		return []byte(payload[0]), nil
+	}
+	fields := make(map[string]interface{}, len(form))
+	for k, v := range form {
+		if len(v) == 1 {
+			fields[k] = v[0]
+		} else {
+			fields[k] = v
+		}
+	}
+	return json.Marshal(fields)
+}
+
 // applyEventValuesToParams returns a slice of Params with the JSONPath variables replaced
 // with values from the event body, headers, and extensions.
 func applyEventValuesToParams(params []triggersv1.Param, body []byte, header http.Header, extensions map[string]interface{},
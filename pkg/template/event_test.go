@@ -192,6 +192,32 @@ func TestApplyEventValuesToParams(t *testing.T) {
 		params: []triggersv1.Param{bldr.Param("foo", "$(body)")},
 		body:   json.RawMessage(`{}`),
 		want:   []triggersv1.Param{bldr.Param("foo", "{}")},
+	}, {
+		name:   "cloudevent context attributes",
+		params: []triggersv1.Param{bldr.Param("foo", "$(context.type) $(context.source) $(context.datacontenttype)")},
+		header: map[string][]string{
+			"Ce-Specversion": {"1.0"},
+			"Ce-Type":        {"dev.knative.example"},
+			"Ce-Source":      {"/example"},
+			"Content-Type":   {"application/json"},
+		},
+		want: []triggersv1.Param{bldr.Param("foo", "dev.knative.example /example application/json")},
+	}, {
+		name:   "form encoded body",
+		params: []triggersv1.Param{bldr.Param("foo", "$(body.ref) $(body.tags[1])")},
+		body:   []byte(`ref=refs%2Fheads%2Fmain&tags=a&tags=b`),
+		header: map[string][]string{
+			"Content-Type": {"application/x-www-form-urlencoded"},
+		},
+		want: []triggersv1.Param{bldr.Param("foo", "refs/heads/main b")},
+	}, {
+		name:   "form encoded GitHub payload",
+		params: []triggersv1.Param{bldr.Param("foo", "$(body.c.d)")},
+		body:   []byte(`payload=%7B%22c%22%3A%7B%22d%22%3A%22e%22%7D%7D`),
+		header: map[string][]string{
+			"Content-Type": {"application/x-www-form-urlencoded; charset=utf-8"},
+		},
+		want: []triggersv1.Param{bldr.Param("foo", "e")},
 	}, {
 		name:   "entire body",
 		params: []triggersv1.Param{bldr.Param("foo", "$(body)")},
@@ -558,3 +584,108 @@ func TestResolveResources(t *testing.T) {
 		})
 	}
 }
+
+func TestDecodeBody(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		contentType string
+		want        string
+	}{{
+		name:        "json body",
+		body:        `{"payload":"a"}`,
+		contentType: "application/json",
+		want:        `{"payload":"a"}`,
+	}, {
+		name: "no content type",
+		body: `{"a":"b"}`,
+		want: `{"a":"b"}`,
+	}, {
+		name:        "form parameters",
+		body:        `a=b&c=d&c=e`,
+		contentType: "application/x-www-form-urlencoded",
+		want:        `{"a":"b","c":["d","e"]}`,
+	}, {
+		name:        "form payload parameter",
+		body:        `payload=%7B%22a%22%3A%22b%22%7D`,
+		contentType: "application/x-www-form-urlencoded",
+		want:        `{"a":"b"}`,
+	}, {
+		name:        "form payload parameter that is not json",
+		body:        `payload=a`,
+		contentType: "application/x-www-form-urlencoded",
+		want:        `{"payload":"a"}`,
+	}, {
+		name:        "form payload with other parameters",
+		body:        `payload=%7B%7D&a=b`,
+		contentType: "application/x-www-form-urlencoded",
+		want:        `{"a":"b","payload":"{}"}`,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			if tt.contentType != "" {
+				header.Set("Content-Type", tt.contentType)
+			}
+			got, err := DecodeBody([]byte(tt.body), header)
+			if err != nil {
+				t.Fatalf("DecodeBody() unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, string(got)); diff != "" {
+				t.Errorf("DecodeBody() (-want, +got) = %s", diff)
+			}
+		})
+	}
+}
+
+func TestDecodeBody_Error(t *testing.T) {
+	header := http.Header{"Content-Type": []string{"application/x-www-form-urlencoded"}}
+	if _, err := DecodeBody([]byte(`a=%zz`), header); err == nil {
+		t.Error("DecodeBody() did not return an error for an invalid form body")
+	}
+}
+
+func TestCloudEventContext(t *testing.T) {
+	tests := []struct {
+		name   string
+		header http.Header
+		want   map[string]string
+	}{{
+		name: "binary cloudevent",
+		header: http.Header{
+			"Ce-Specversion":  []string{"1.0"},
+			"Ce-Id":           []string{"1234"},
+			"Ce-Myextension":  []string{"a", "b"},
+			"Content-Type":    []string{"application/json"},
+			"X-Forwarded-For": []string{"10.0.0.1"},
+		},
+		want: map[string]string{
+			"specversion":     "1.0",
+			"id":              "1234",
+			"myextension":     "a,b",
+			"datacontenttype": "application/json",
+		},
+	}, {
+		name: "non canonical header keys",
+		header: http.Header{
+			"ce-type": []string{"dev.knative.example"},
+		},
+		want: map[string]string{
+			"type": "dev.knative.example",
+		},
+	}, {
+		name: "not a cloudevent",
+		header: http.Header{
+			"Content-Type": []string{"application/json"},
+			"Ce-":          []string{"empty"},
+		},
+		want: map[string]string{},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := cmp.Diff(tt.want, CloudEventContext(tt.header)); diff != "" {
+				t.Errorf("CloudEventContext() (-want, +got) = %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tracing traces the processing of events by the EventListener sink
+// with OpenTelemetry. Spans are propagated using the W3C Trace Context headers
+// and exported to an OpenTelemetry Collector over OTLP.
+package tracing
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel/api/global"
+	"go.opentelemetry.io/otel/api/trace"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp"
+	"go.opentelemetry.io/otel/propagators"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/semconv"
+)
+
+const (
+	// ServiceName is the name of the service the spans of the sink belong to.
+	ServiceName = "eventlistener"
+	// instrumentationName is the name of the tracer of the spans.
+	instrumentationName = "github.com/tektoncd/triggers"
+)
+
+var propagator = propagators.TraceContext{}
+
+// Setup exports the sampled spans to the OTLP receiver at endpoint, and
+// samples new traces with the given probability. Traces continued from a
+// traceparent header keep the sampling decision of their caller. It returns a
+// function that flushes and stops the exporter.
+func Setup(endpoint string, sampleRate float64) (func(), error) {
+	exporter, err := otlp.NewExporter(
+		otlp.WithInsecure(),
+		otlp.WithAddress(endpoint),
+	)
+	if err != nil {
+		return nil, err
+	}
+	processor := sdktrace.NewBatchSpanProcessor(exporter)
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithConfig(sdktrace.Config{DefaultSampler: sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRate))}),
+		sdktrace.WithResource(resource.New(semconv.ServiceNameKey.String(ServiceName))),
+		sdktrace.WithSpanProcessor(processor),
+	)
+	global.SetTracerProvider(provider)
+	return func() {
+		// Unregistering the processor flushes its spans
+		provider.UnregisterSpanProcessor(processor)
+		_ = exporter.Shutdown(context.Background())
+	}, nil
+}
+
+// StartSpan starts a span that is a child of the span in ctx, if any.
+func StartSpan(ctx context.Context, name string, opts ...trace.SpanOption) (context.Context, trace.Span) {
+	return global.Tracer(instrumentationName).Start(ctx, name, opts...)
+}
+
+// StartSpanFromRequest starts a span continuing the trace of the incoming
+// request's traceparent header, if it has one.
+func StartSpanFromRequest(r *http.Request, name string) (context.Context, trace.Span) {
+	ctx := propagator.Extract(r.Context(), r.Header)
+	return StartSpan(ctx, name, trace.WithSpanKind(trace.SpanKindServer))
+}
+
+// Inject sets the traceparent header of the outgoing request r to the span in
+// ctx.
+func Inject(ctx context.Context, r *http.Request) {
+	propagator.Inject(ctx, r.Header)
+}
+
+// TraceID returns the ID of the trace of the span in ctx if it is sampled,
+// and an empty string otherwise.
+func TraceID(ctx context.Context) string {
+	sc := trace.SpanFromContext(ctx).SpanContext()
+	if !sc.IsValid() || !sc.IsSampled() {
+		return ""
+	}
+	return sc.TraceID.String()
+}
+
+// Detach returns a context that is never cancelled but carries the span of
+// ctx, for processing that outlives the request.
+func Detach(ctx context.Context) context.Context {
+	return trace.ContextWithSpan(context.Background(), trace.SpanFromContext(ctx))
+}
+
+// EndSpan records err, if any, as the status of span and ends it.
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
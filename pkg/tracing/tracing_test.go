@@ -0,0 +1,97 @@
+/*
+Copyright 2020 The Tekton Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/api/global"
+	"go.opentelemetry.io/otel/api/trace"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+const (
+	traceID     = "4bf92f3577b34da6a3ce929d0e0e4736"
+	traceparent = "00-" + traceID + "-00f067aa0ba902b7-01"
+)
+
+func init() {
+	// Sample every span that is not continued from an unsampled trace
+	global.SetTracerProvider(sdktrace.NewTracerProvider())
+}
+
+func TestStartSpanFromRequest(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.Header.Set("Traceparent", traceparent)
+	ctx, span := StartSpanFromRequest(req, "test")
+	defer span.End()
+
+	if got := TraceID(ctx); got != traceID {
+		t.Errorf("TraceID() = %q, want %q", got, traceID)
+	}
+
+	out := httptest.NewRequest(http.MethodPost, "/", nil)
+	Inject(ctx, out)
+	got := out.Header.Get("Traceparent")
+	if !strings.HasPrefix(got, "00-"+traceID+"-") || got == traceparent {
+		t.Errorf("Inject() set traceparent %q, want a child span of %q", got, traceparent)
+	}
+}
+
+func TestStartSpanFromRequest_NoTraceparent(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	ctx, span := StartSpanFromRequest(req, "test")
+	defer span.End()
+
+	if !trace.SpanFromContext(ctx).SpanContext().IsValid() {
+		t.Error("StartSpanFromRequest() did not start a span")
+	}
+	if span.SpanContext().TraceID.String() == traceID {
+		t.Error("StartSpanFromRequest() continued a trace without a traceparent")
+	}
+}
+
+func TestTraceID_NotSampled(t *testing.T) {
+	if got := TraceID(context.Background()); got != "" {
+		t.Errorf("TraceID() without a span = %q, want empty", got)
+	}
+	provider := sdktrace.NewTracerProvider(sdktrace.WithConfig(sdktrace.Config{DefaultSampler: sdktrace.NeverSample()}))
+	ctx, span := provider.Tracer("test").Start(context.Background(), "test")
+	defer span.End()
+	if got := TraceID(ctx); got != "" {
+		t.Errorf("TraceID() of an unsampled span = %q, want empty", got)
+	}
+}
+
+func TestDetach(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	ctx, span := StartSpan(ctx, "test")
+	defer span.End()
+	cancel()
+
+	detached := Detach(ctx)
+	if detached.Err() != nil {
+		t.Errorf("Detach() returned a cancelled context: %v", detached.Err())
+	}
+	if trace.SpanFromContext(detached) != span {
+		t.Error("Detach() did not keep the span")
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/tektoncd/triggers/pkg/apis/triggers/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"knative.dev/pkg/apis"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 	duckv1alpha1 "knative.dev/pkg/apis/duck/v1alpha1"
@@ -106,6 +107,92 @@ func EventListenerReplicas(replicas int32) EventListenerSpecOp {
 	}
 }
 
+// EventListenerProcessingMode sets the specified ProcessingMode of the EventListener.
+func EventListenerProcessingMode(mode v1alpha1.EventProcessingMode) EventListenerSpecOp {
+	return func(spec *v1alpha1.EventListenerSpec) {
+		spec.ProcessingMode = mode
+	}
+}
+
+// EventListenerDeduplication sets the specified Deduplication of the EventListener.
+func EventListenerDeduplication(dedup v1alpha1.Deduplication) EventListenerSpecOp {
+	return func(spec *v1alpha1.EventListenerSpec) {
+		spec.Deduplication = &dedup
+	}
+}
+
+// EventListenerCloudEventReply makes the EventListener reply to CloudEvents with a CloudEvent.
+func EventListenerCloudEventReply() EventListenerSpecOp {
+	return func(spec *v1alpha1.EventListenerSpec) {
+		spec.CloudEventReply = true
+	}
+}
+
+// EventListenerCloudEventURI sets the URI the EventListener sends CloudEvents to.
+func EventListenerCloudEventURI(uri string) EventListenerSpecOp {
+	return func(spec *v1alpha1.EventListenerSpec) {
+		spec.CloudEventURI = uri
+	}
+}
+
+// EventListenerMatchPolicy sets the specified MatchPolicy of the EventListener.
+func EventListenerMatchPolicy(policy v1alpha1.TriggerMatchPolicy) EventListenerSpecOp {
+	return func(spec *v1alpha1.EventListenerSpec) {
+		spec.MatchPolicy = policy
+	}
+}
+
+// EventListenerRateLimit sets the specified RateLimit of the EventListener.
+func EventListenerRateLimit(limit v1alpha1.RateLimit) EventListenerSpecOp {
+	return func(spec *v1alpha1.EventListenerSpec) {
+		spec.RateLimit = &limit
+	}
+}
+
+// EventListenerTLS sets the Secrets holding the TLS certificates of the EventListener.
+func EventListenerTLS(secretName, clientCASecretName string) EventListenerSpecOp {
+	return func(spec *v1alpha1.EventListenerSpec) {
+		spec.TLS = &v1alpha1.EventListenerTLS{
+			SecretName:         secretName,
+			ClientCASecretName: clientCASecretName,
+		}
+	}
+}
+
+// EventListenerAuthentication sets the specified Authentication of the EventListener.
+func EventListenerAuthentication(auth v1alpha1.EventListenerAuthentication) EventListenerSpecOp {
+	return func(spec *v1alpha1.EventListenerSpec) {
+		spec.Authentication = &auth
+	}
+}
+
+// EventListenerAllowedSources restricts the addresses the EventListener accepts requests from.
+func EventListenerAllowedSources(cidrs []string, trustedProxies ...string) EventListenerSpecOp {
+	return func(spec *v1alpha1.EventListenerSpec) {
+		spec.AllowedSources = &v1alpha1.AllowedSources{
+			CIDRs:          cidrs,
+			TrustedProxies: trustedProxies,
+		}
+	}
+}
+
+// EventListenerAdmin serves the admin endpoint of the EventListener.
+func EventListenerAdmin(tokenSecretName string, recentEvents int32) EventListenerSpecOp {
+	return func(spec *v1alpha1.EventListenerSpec) {
+		spec.Admin = &v1alpha1.EventListenerAdmin{
+			TokenSecretName: tokenSecretName,
+			RecentEvents:    recentEvents,
+		}
+	}
+}
+
+// EventListenerDeadLetter keeps up to maxEvents failed events of the EventListener.
+func EventListenerDeadLetter(maxEvents int32) EventListenerSpecOp {
+	return func(spec *v1alpha1.EventListenerSpec) {
+		spec.DeadLetter = &v1alpha1.DeadLetter{MaxEvents: maxEvents}
+	}
+}
+
 // EventListenerPodTemplate sets the specified pod template of the EventListener.
 func EventListenerPodTemplate(podTemplate v1alpha1.PodTemplate) EventListenerSpecOp {
 	return func(spec *v1alpha1.EventListenerSpec) {
@@ -218,6 +305,48 @@ func EventListenerTriggerName(name string) EventListenerTriggerOp {
 	}
 }
 
+// EventListenerTriggerPriority sets the Priority of the Trigger in EventListenerSpec Triggers.
+func EventListenerTriggerPriority(priority int32) EventListenerTriggerOp {
+	return func(trigger *v1alpha1.EventListenerTrigger) {
+		trigger.Priority = priority
+	}
+}
+
+// EventListenerTriggerRetry sets the specified RetryPolicy of the EventListenerTrigger.
+func EventListenerTriggerRetry(policy v1alpha1.RetryPolicy) EventListenerTriggerOp {
+	return func(trigger *v1alpha1.EventListenerTrigger) {
+		trigger.Retry = &policy
+	}
+}
+
+// EventListenerTriggerDryRun makes the EventListenerTrigger create its resources with a dry run.
+func EventListenerTriggerDryRun() EventListenerTriggerOp {
+	return func(trigger *v1alpha1.EventListenerTrigger) {
+		trigger.DryRun = true
+	}
+}
+
+// EventListenerTriggerRateLimit sets the specified RateLimit of the EventListenerTrigger.
+func EventListenerTriggerRateLimit(limit v1alpha1.RateLimit) EventListenerTriggerOp {
+	return func(trigger *v1alpha1.EventListenerTrigger) {
+		trigger.RateLimit = &limit
+	}
+}
+
+// EventListenerTriggerPath sets the specified Path of the EventListenerTrigger.
+func EventListenerTriggerPath(path string) EventListenerTriggerOp {
+	return func(trigger *v1alpha1.EventListenerTrigger) {
+		trigger.Path = path
+	}
+}
+
+// EventListenerTriggerMethod sets the specified Method of the EventListenerTrigger.
+func EventListenerTriggerMethod(method string) EventListenerTriggerOp {
+	return func(trigger *v1alpha1.EventListenerTrigger) {
+		trigger.Method = method
+	}
+}
+
 // EventListenerTriggerServiceAccount set the specified ServiceAccountName of the EventListenerTrigger.
 func EventListenerTriggerServiceAccount(saName, namespace string) EventListenerTriggerOp {
 	return func(trigger *v1alpha1.EventListenerTrigger) {
@@ -320,6 +449,33 @@ func EventListenerCELOverlay(key, expression string) EventInterceptorOp {
 	}
 }
 
+// EventListenerInterceptorRef adds an interceptor that refers to the
+// ClusterInterceptor name to the EventListenerTrigger.
+func EventListenerInterceptorRef(name string, ops ...EventInterceptorOp) EventListenerTriggerOp {
+	return func(t *v1alpha1.EventListenerTrigger) {
+		i := &v1alpha1.EventInterceptor{
+			Ref: &v1alpha1.InterceptorRef{
+				Name: name,
+			},
+		}
+		for _, op := range ops {
+			op(i)
+		}
+		t.Interceptors = append(t.Interceptors, i)
+	}
+}
+
+// EventListenerInterceptorRefParam adds a param with the JSON encoded value to
+// the interceptor.
+func EventListenerInterceptorRefParam(name, value string) EventInterceptorOp {
+	return func(i *v1alpha1.EventInterceptor) {
+		i.Params = append(i.Params, v1alpha1.InterceptorParams{
+			Name:  name,
+			Value: runtime.RawExtension{Raw: []byte(value)},
+		})
+	}
+}
+
 // EventListenerResources set specified resources to the EventListener.
 func EventListenerResources(ops ...EventListenerResourceOp) EventListenerSpecOp {
 	return func(spec *v1alpha1.EventListenerSpec) {
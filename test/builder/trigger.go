@@ -21,6 +21,7 @@ import (
 	"github.com/tektoncd/triggers/pkg/apis/triggers/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 // TriggerOp is an operation which modifies the Trigger.
@@ -180,3 +181,30 @@ func TriggerSpecCELOverlay(key, expression string) TriggerInterceptorOp {
 		}
 	}
 }
+
+// TriggerSpecInterceptorRef adds an interceptor that refers to the
+// ClusterInterceptor name to the TriggerSpec.
+func TriggerSpecInterceptorRef(name string, ops ...TriggerInterceptorOp) TriggerSpecOp {
+	return func(spec *v1alpha1.TriggerSpec) {
+		i := &v1alpha1.TriggerInterceptor{
+			Ref: &v1alpha1.InterceptorRef{
+				Name: name,
+			},
+		}
+		for _, op := range ops {
+			op(i)
+		}
+		spec.Interceptors = append(spec.Interceptors, i)
+	}
+}
+
+// TriggerSpecInterceptorRefParam adds a param with the JSON encoded value to
+// the interceptor.
+func TriggerSpecInterceptorRefParam(name, value string) TriggerInterceptorOp {
+	return func(i *v1alpha1.TriggerInterceptor) {
+		i.Params = append(i.Params, v1alpha1.InterceptorParams{
+			Name:  name,
+			Value: runtime.RawExtension{Raw: []byte(value)},
+		})
+	}
+}
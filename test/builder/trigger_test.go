@@ -25,6 +25,7 @@ import (
 	"github.com/tektoncd/triggers/pkg/apis/triggers/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"knative.dev/pkg/apis"
 	"knative.dev/pkg/ptr"
 )
@@ -307,6 +308,34 @@ func TestTriggerBuilder(t *testing.T) {
 					TriggerSpecCELOverlay("value", "'testing'")),
 			),
 		),
+	}, {
+		name: "One Trigger with ClusterInterceptor",
+		normal: &v1alpha1.Trigger{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "name",
+				Namespace: "namespace",
+			},
+			Spec: v1alpha1.TriggerSpec{
+				Interceptors: []*v1alpha1.TriggerInterceptor{{
+					Ref: &v1alpha1.InterceptorRef{Name: "scanner"},
+					Params: []v1alpha1.InterceptorParams{{
+						Name:  "severities",
+						Value: runtime.RawExtension{Raw: []byte(`["high", "critical"]`)},
+					}},
+				}},
+				Template: v1alpha1.TriggerSpecTemplate{
+					Ref:        ptr.String("tt1"),
+					APIVersion: "v1alpha1",
+				},
+			},
+		},
+		builder: Trigger("name", "namespace",
+			TriggerSpec(
+				TriggerSpecTemplate("tt1", "v1alpha1"),
+				TriggerSpecInterceptorRef("scanner",
+					TriggerSpecInterceptorRefParam("severities", `["high", "critical"]`)),
+			),
+		),
 	},
 	}
 	for _, tt := range tests {
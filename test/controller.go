@@ -30,6 +30,7 @@ import (
 	dynamicclientset "github.com/tektoncd/triggers/pkg/client/dynamic/clientset"
 	"github.com/tektoncd/triggers/pkg/client/dynamic/clientset/tekton"
 	faketriggersclient "github.com/tektoncd/triggers/pkg/client/injection/client/fake"
+	fakeclusterinterceptorinformer "github.com/tektoncd/triggers/pkg/client/injection/informers/triggers/v1alpha1/clusterinterceptor/fake"
 	fakeclustertriggerbindinginformer "github.com/tektoncd/triggers/pkg/client/injection/informers/triggers/v1alpha1/clustertriggerbinding/fake"
 	fakeeventlistenerinformer "github.com/tektoncd/triggers/pkg/client/injection/informers/triggers/v1alpha1/eventlistener/fake"
 	faketriggerinformer "github.com/tektoncd/triggers/pkg/client/injection/informers/triggers/v1alpha1/trigger/fake"
@@ -56,6 +57,7 @@ import (
 type Resources struct {
 	Namespaces             []*corev1.Namespace
 	ClusterTriggerBindings []*v1alpha1.ClusterTriggerBinding
+	ClusterInterceptors    []*v1alpha1.ClusterInterceptor
 	EventListeners         []*v1alpha1.EventListener
 	TriggerBindings        []*v1alpha1.TriggerBinding
 	TriggerTemplates       []*v1alpha1.TriggerTemplate
@@ -98,6 +100,7 @@ func SeedResources(t *testing.T, ctx context.Context, r Resources) Clients {
 
 	// Setup fake informer for reconciler tests
 	ctbInformer := fakeclustertriggerbindinginformer.Get(ctx)
+	ciInformer := fakeclusterinterceptorinformer.Get(ctx)
 	elInformer := fakeeventlistenerinformer.Get(ctx)
 	ttInformer := faketriggertemplateinformer.Get(ctx)
 	tbInformer := faketriggerbindinginformer.Get(ctx)
@@ -124,6 +127,14 @@ func SeedResources(t *testing.T, ctx context.Context, r Resources) Clients {
 			t.Fatal(err)
 		}
 	}
+	for _, ci := range r.ClusterInterceptors {
+		if err := ciInformer.Informer().GetIndexer().Add(ci); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := c.Triggers.TriggersV1alpha1().ClusterInterceptors().Create(context.Background(), ci, metav1.CreateOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
 	for _, el := range r.EventListeners {
 		if err := elInformer.Informer().GetIndexer().Add(el); err != nil {
 			t.Fatal(err)
@@ -226,6 +237,14 @@ func GetResourcesFromClients(c Clients) (*Resources, error) {
 	for _, ctb := range ctbList.Items {
 		testResources.ClusterTriggerBindings = append(testResources.ClusterTriggerBindings, ctb.DeepCopy())
 	}
+	// Add ClusterInterceptors
+	ciList, err := c.Triggers.TriggersV1alpha1().ClusterInterceptors().List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, ci := range ciList.Items {
+		testResources.ClusterInterceptors = append(testResources.ClusterInterceptors, ci.DeepCopy())
+	}
 	nsList, err := c.Kube.CoreV1().Namespaces().List(context.Background(), metav1.ListOptions{})
 	if err != nil {
 		return nil, err